@@ -174,6 +174,32 @@ func dialRPC(endpoint string) (*rpc.Client, error) {
 	return rpc.Dial(endpoint)
 }
 
+// dialChildChain connects to the child chain node at --childchain.url, or the
+// IPC endpoint in the data directory.
+func dialChildChain(ctx *cli.Context) *rpc.Client {
+	endpoint := ctx.GlobalString(utils.ChildChainUrlFlag.Name)
+	if endpoint == "" {
+		path := node.DefaultDataDir()
+		if ctx.GlobalIsSet(utils.DataDirFlag.Name) {
+			path = ctx.GlobalString(utils.DataDirFlag.Name)
+		}
+		if path != "" {
+			if ctx.GlobalBool(utils.TestnetFlag.Name) {
+				path = filepath.Join(path, "testnet")
+			} else if ctx.GlobalBool(utils.RinkebyFlag.Name) {
+				path = filepath.Join(path, "rinkeby")
+			}
+		}
+		endpoint = fmt.Sprintf("%s/geth.ipc", path)
+	}
+
+	client, err := dialRPC(endpoint)
+	if err != nil {
+		utils.Fatalf("Failed to connect endpoint: %v", err)
+	}
+	return client
+}
+
 // ephemeralConsole starts a new geth node, attaches an ephemeral JavaScript
 // console to it, executes each of the files specified as arguments and tears
 // everything down.
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/Onther-Tech/plasma-evm/cmd/utils"
+	"github.com/Onther-Tech/plasma-evm/common/hexutil"
+	"github.com/Onther-Tech/plasma-evm/params"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	costCmd = cli.Command{
+		Name:     "cost",
+		Usage:    "Print the costs of root chain transactions sent by the node",
+		Category: "PLASMA OPERATOR COMMANDS",
+		Description: `
+The cost command prints gas, bonded ether and refunds of submission, challenge
+and finalization transactions sent to RootChain contract by the running node.
+`,
+		Subcommands: []cli.Command{
+			{
+				Name:      "epochs",
+				Usage:     "Print the costs for each epoch",
+				ArgsUsage: "<forkNumber>",
+				Action:    utils.MigrateFlags(costsByEpoch),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.ChildChainUrlFlag,
+				},
+				Description: `
+    geth cost epochs <forkNumber>
+
+Print the costs of root chain transactions for each epoch in the fork.
+Transactions not attributed to an epoch (e.g., finalizeRequests) are not included.
+`,
+			},
+			{
+				Name:      "days",
+				Usage:     "Print the costs for each day",
+				ArgsUsage: "[<days>]",
+				Action:    utils.MigrateFlags(costsByDay),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.ChildChainUrlFlag,
+				},
+				Description: `
+    geth cost days [<days>]
+
+Print the costs of root chain transactions for each day (UTC) in the recent <days>.
+All recorded days are printed if <days> is omitted.
+`,
+			},
+		},
+	}
+)
+
+// costSummary is the cost summary returned by pls_operatorCostsByEpoch and
+// pls_operatorCostsByDay.
+type costSummary struct {
+	ForkNumber  hexutil.Uint64 `json:"forkNumber"`
+	EpochNumber hexutil.Uint64 `json:"epochNumber"`
+	Date        string         `json:"date"`
+	NumTxs      hexutil.Uint64 `json:"numTxs"`
+	GasUsed     hexutil.Uint64 `json:"gasUsed"`
+	GasCost     *hexutil.Big   `json:"gasCost"`
+	Bonded      *hexutil.Big   `json:"bonded"`
+	Refunded    *hexutil.Big   `json:"refunded"`
+	Total       *hexutil.Big   `json:"total"`
+}
+
+func costsByEpoch(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("Expected 1 parameter, not %d", len(ctx.Args()))
+	}
+
+	forkNumber, err := strconv.ParseUint(ctx.Args().Get(0), 10, 64)
+	if err != nil {
+		utils.Fatalf("Failed to parse fork number: %v", err)
+	}
+
+	client := dialChildChain(ctx)
+	defer client.Close()
+
+	var costs []costSummary
+	if err := client.Call(&costs, "pls_operatorCostsByEpoch", hexutil.Uint64(forkNumber)); err != nil {
+		utils.Fatalf("Failed to read costs: %v", err)
+	}
+
+	fmt.Printf("%-8s %-8s %-8s %-12s %-16s %-16s %-16s %-16s\n", "Fork", "Epoch", "Txs", "Gas Used", "Gas Cost", "Bonded", "Refunded", "Total")
+	for _, c := range costs {
+		fmt.Printf("%-8d %-8d %-8d %-12d %-16s %-16s %-16s %-16s\n", c.ForkNumber, c.EpochNumber, c.NumTxs, c.GasUsed,
+			etherString(c.GasCost), etherString(c.Bonded), etherString(c.Refunded), etherString(c.Total))
+	}
+
+	return nil
+}
+
+func costsByDay(ctx *cli.Context) error {
+	var days uint64
+	switch len(ctx.Args()) {
+	case 0:
+	case 1:
+		var err error
+		if days, err = strconv.ParseUint(ctx.Args().Get(0), 10, 64); err != nil {
+			utils.Fatalf("Failed to parse days: %v", err)
+		}
+	default:
+		utils.Fatalf("Expected at most 1 parameter, not %d", len(ctx.Args()))
+	}
+
+	client := dialChildChain(ctx)
+	defer client.Close()
+
+	var costs []costSummary
+	if err := client.Call(&costs, "pls_operatorCostsByDay", hexutil.Uint64(days)); err != nil {
+		utils.Fatalf("Failed to read costs: %v", err)
+	}
+
+	fmt.Printf("%-12s %-8s %-12s %-16s %-16s %-16s %-16s\n", "Date", "Txs", "Gas Used", "Gas Cost", "Bonded", "Refunded", "Total")
+	for _, c := range costs {
+		fmt.Printf("%-12s %-8d %-12d %-16s %-16s %-16s %-16s\n", c.Date, c.NumTxs, c.GasUsed,
+			etherString(c.GasCost), etherString(c.Bonded), etherString(c.Refunded), etherString(c.Total))
+	}
+
+	return nil
+}
+
+// etherString formats the amount of wei in ether.
+func etherString(wei *hexutil.Big) string {
+	if wei == nil {
+		return "0"
+	}
+	ether := new(big.Float).Quo(new(big.Float).SetInt(wei.ToInt()), big.NewFloat(params.Ether))
+	return ether.Text('f', 6)
+}
@@ -173,19 +173,36 @@ var (
 
 	plasmaFlags = []cli.Flag{
 		utils.OperatorMinEtherFlag,
+		utils.OperatorMinRunwayFlag,
+		utils.OperatorSubmittersFlag,
+		utils.OperatorPipelineFlag,
 		utils.OperatorAddressFlag,
 		utils.OperatorKeyFlag,
 		utils.OperatorPasswordFileFlag,
 		utils.DeveloperKeyFlag,
 		utils.RootChainUrlFlag,
 		utils.RootChainContractFlag,
+		utils.RootChainFallbackUrlsFlag,
+		utils.RootChainQuorumFlag,
+		utils.RootChainConfirmationsFlag,
 		utils.RootChainGasPriceFlag,
 		utils.RootChainDeployGasPriceFlag,
 		utils.TxMinGasPriceFlag,
 		utils.TxMaxGasPriceFlag,
 		utils.TxResubmitFlag,
+		utils.TxGasPriceStrategyFlag,
+		utils.TxDeadlineWindowFlag,
+		utils.TxFillNonceGapFlag,
 		utils.ChallengerAddressFlag,
 		utils.ChallengerPasswordFileFlag,
+		utils.URBSubmitterAddressFlag,
+		utils.URBSubmitterPasswordFileFlag,
+		utils.FinalizerFlag,
+		utils.FinalizerAccountFlag,
+		utils.FinalizerMaxBlocksFlag,
+		utils.FinalizerMaxRequestsFlag,
+		utils.FinalizerBudgetFlag,
+		utils.FinalizerIntervalFlag,
 	}
 
 	staminaFlags = []cli.Flag{
@@ -269,6 +286,12 @@ func init() {
 		stakingCmd,
 		// See staminacmd.go
 		staminaCmd,
+		// See requestcmd.go
+		requestCmd,
+		// See costcmd.go
+		costCmd,
+		// See submittercmd.go
+		submitterCmd,
 	}
 	sort.Sort(cli.CommandsByName(app.Commands))
 
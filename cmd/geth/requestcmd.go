@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/Onther-Tech/plasma-evm/accounts/abi/bind"
+	"github.com/Onther-Tech/plasma-evm/cmd/utils"
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/contracts/plasma"
+	"github.com/Onther-Tech/plasma-evm/contracts/plasma/rootchain"
+	"github.com/Onther-Tech/plasma-evm/log"
+	"github.com/Onther-Tech/plasma-evm/request"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	requestERUFlag = cli.BoolFlag{
+		Name:  "eru",
+		Usage: "Request is an escape request (ERU)",
+	}
+	requestNoSimulateFlag = cli.BoolFlag{
+		Name:  "nosimulate",
+		Usage: "Do not simulate request in child chain before sending transaction",
+	}
+
+	requestCmd = cli.Command{
+		Name:     "request",
+		Usage:    "Make requests to RootChain contract",
+		Category: "PLASMA REQUEST COMMANDS",
+		Description: `
+The request command sends transaction to make enter / exit requests in RootChain contract.
+`,
+		Subcommands: []cli.Command{
+			{
+				Name:      "enter",
+				Usage:     "Make enter request to move tokens from root chain to child chain",
+				ArgsUsage: "<token> <amount>",
+				Action:    utils.MigrateFlags(startEnter),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.RootChainUrlFlag,
+					utils.RootChainContractFlag,
+					utils.ChildChainUrlFlag,
+					utils.UnlockedAccountFlag,
+					utils.PasswordFileFlag,
+					utils.RootChainSenderFlag,
+					utils.RootChainGasPriceFlag,
+					requestNoSimulateFlag,
+				},
+				Description: `
+    geth request enter <token> <amount>
+
+Make an enter request to move <amount> of <token> from root chain to child chain.
+<token> is EtherToken for PETH or a requestable token contract in root chain.
+The request is simulated in child chain (--childchain.url) before sending transaction.
+
+NOTE:
+<amount> must be a float in 18 decimals (e.g., 1.5)
+`,
+			},
+			{
+				Name:      "exit",
+				Usage:     "Make exit request to move tokens from child chain to root chain",
+				ArgsUsage: "<token> <amount>",
+				Action:    utils.MigrateFlags(startExit),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.RootChainUrlFlag,
+					utils.RootChainContractFlag,
+					utils.ChildChainUrlFlag,
+					utils.UnlockedAccountFlag,
+					utils.PasswordFileFlag,
+					utils.RootChainSenderFlag,
+					utils.RootChainGasPriceFlag,
+					requestNoSimulateFlag,
+				},
+				Description: `
+    geth request exit <token> <amount>
+
+Make an exit request to move <amount> of <token> from child chain to root chain.
+COST_ERO is paid with the request. The request is simulated in child chain
+(--childchain.url) before sending transaction.
+
+NOTE:
+<amount> must be a float in 18 decimals (e.g., 1.5)
+`,
+			},
+			{
+				Name:      "status",
+				Usage:     "Print the status of request",
+				ArgsUsage: "<requestId>",
+				Action:    utils.MigrateFlags(requestStatus),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.RootChainUrlFlag,
+					utils.RootChainContractFlag,
+					requestERUFlag,
+				},
+				Description: `
+    geth request status <requestId>
+
+Print the status of the enter / exit request (ERO). Use --eru flag for escape request (ERU).
+`,
+			},
+			{
+				Name:      "make-eru",
+				Usage:     "Make escape request (ERU)",
+				ArgsUsage: "<to> <trieKey> <trieValue>",
+				Action:    utils.MigrateFlags(makeERU),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.RootChainUrlFlag,
+					utils.RootChainContractFlag,
+					utils.UnlockedAccountFlag,
+					utils.PasswordFileFlag,
+					utils.RootChainSenderFlag,
+					utils.RootChainGasPriceFlag,
+				},
+				Description: `
+    geth request make-eru <to> <trieKey> <trieValue>
+
+Make an user-activated exit request (ERU) to the requestable contract <to>.
+ERUs are included in URBs even if operator withholds blocks.
+
+NOTE:
+<trieKey> and <trieValue> are hex encoded. <to> must be mapped to requestable contract in child chain.
+`,
+			},
+		},
+	}
+)
+
+func makeERU(ctx *cli.Context) error {
+	if len(ctx.Args()) != 3 {
+		utils.Fatalf("Expected 3 parameters, not %d", len(ctx.Args()))
+	}
+
+	stack, cfg := makeConfigNode(ctx)
+	opt, backend := initOpts(ctx, stack, &cfg.Pls)
+
+	if opt == nil {
+		utils.Fatalf("Root chain transaction sender is not set. use --rootchain.sender flag")
+	}
+
+	rootchainAddr := cfg.Pls.RootChainContract
+	if (rootchainAddr == common.Address{}) {
+		rootchainAddr = getRootChainAddr(cfg.Node.DataDir)
+	}
+
+	var (
+		to        = common.HexToAddress(ctx.Args().Get(0))
+		trieKey   = common.HexToHash(ctx.Args().Get(1))
+		trieValue = common.FromHex(ctx.Args().Get(2))
+	)
+
+	rootchainCtr, err := rootchain.NewRootChain(rootchainAddr, backend)
+	if err != nil {
+		utils.Fatalf("Failed to load RootChain contract: %v", err)
+	}
+
+	requestableContract, err := rootchainCtr.RequestableContracts(&bind.CallOpts{Pending: false}, to)
+	if err != nil {
+		utils.Fatalf("Failed to read requestable contract: %v", err)
+	}
+
+	if (requestableContract == common.Address{}) {
+		utils.Fatalf("%s is not mapped to requestable contract", to.Hex())
+	}
+
+	costERU, err := rootchainCtr.COSTERU(&bind.CallOpts{Pending: false})
+	if err != nil {
+		utils.Fatalf("Failed to read COST_ERU: %v", err)
+	}
+
+	opt.Value = costERU
+
+	tx, err := rootchainCtr.MakeERU(opt, to, trieKey, trieValue)
+	if err != nil {
+		utils.Fatalf("Failed to send transaction: %v", err)
+	}
+	log.Info("Making ERU", "rootchain", rootchainAddr, "to", to, "trieKey", trieKey, "trieValue", common.Bytes2Hex(trieValue), "tx", tx.Hash())
+
+	if err = plasma.WaitTx(backend, tx.Hash()); err != nil {
+		utils.Fatalf("Failed to wait transaction: %v", err)
+	}
+
+	return nil
+}
+
+func startEnter(ctx *cli.Context) error {
+	return makeRequest(ctx, false)
+}
+
+func startExit(ctx *cli.Context) error {
+	return makeRequest(ctx, true)
+}
+
+func makeRequest(ctx *cli.Context, isExit bool) error {
+	if len(ctx.Args()) != 2 {
+		utils.Fatalf("Expected 2 parameters, not %d", len(ctx.Args()))
+	}
+
+	stack, cfg := makeConfigNode(ctx)
+	opt, backend := initOpts(ctx, stack, &cfg.Pls)
+
+	if opt == nil {
+		utils.Fatalf("Root chain transaction sender is not set. use --rootchain.sender flag")
+	}
+
+	rootchainAddr := cfg.Pls.RootChainContract
+	if (rootchainAddr == common.Address{}) {
+		rootchainAddr = getRootChainAddr(cfg.Node.DataDir)
+	}
+
+	var childBackend bind.ContractCaller
+	if !ctx.GlobalBool(requestNoSimulateFlag.Name) {
+		_, childBackend = initPlsOpts(ctx)
+	}
+
+	builder, err := request.NewBuilder(rootchainAddr, backend, childBackend)
+	if err != nil {
+		utils.Fatalf("Failed to load RootChain contract: %v", err)
+	}
+
+	var (
+		token  = common.HexToAddress(ctx.Args().Get(0))
+		amount = parseFloatString(ctx.Args().Get(1), 18)
+	)
+
+	r, err := builder.NewTokenRequest(isExit, opt.From, token, amount)
+	if err != nil {
+		utils.Fatalf("Failed to make request: %v", err)
+	}
+
+	if err := builder.Simulate(context.Background(), r); err != nil {
+		utils.Fatalf("Failed to simulate request: %v", err)
+	}
+
+	tx, err := builder.Send(opt, r)
+	if err != nil {
+		utils.Fatalf("Failed to send transaction: %v", err)
+	}
+	log.Info("Making request", "rootchain", rootchainAddr, "isExit", isExit, "token", token, "amount", amount, "trieKey", r.TrieKey, "tx", tx.Hash())
+
+	if err = plasma.WaitTx(backend, tx.Hash()); err != nil {
+		utils.Fatalf("Failed to wait transaction: %v", err)
+	}
+
+	return nil
+}
+
+func requestStatus(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("Expected 1 parameter, not %d", len(ctx.Args()))
+	}
+
+	requestId, err := strconv.ParseUint(ctx.Args().Get(0), 10, 64)
+	if err != nil {
+		utils.Fatalf("Failed to parse request id: %v", err)
+	}
+
+	stack, cfg := makeConfigNode(ctx)
+	_, backend := initOpts(ctx, stack, &cfg.Pls)
+
+	rootchainAddr := cfg.Pls.RootChainContract
+	if (rootchainAddr == common.Address{}) {
+		rootchainAddr = getRootChainAddr(cfg.Node.DataDir)
+	}
+
+	builder, err := request.NewBuilder(rootchainAddr, backend, nil)
+	if err != nil {
+		utils.Fatalf("Failed to load RootChain contract: %v", err)
+	}
+
+	status, err := builder.Status(requestId, ctx.GlobalBool(requestERUFlag.Name))
+	if err != nil {
+		utils.Fatalf("Failed to read request: %v", err)
+	}
+
+	fmt.Printf("Request ID:       %d\n", status.RequestId)
+	fmt.Printf("User Activated:   %v\n", status.UserActivated)
+	fmt.Printf("Exit:             %v\n", status.IsExit)
+	fmt.Printf("Transfer:         %v\n", status.IsTransfer)
+	fmt.Printf("Requestor:        %s\n", status.Requestor.Hex())
+	fmt.Printf("To:               %s\n", status.To.Hex())
+	fmt.Printf("Trie Key:         %s\n", status.TrieKey.Hex())
+	fmt.Printf("Trie Value:       0x%s\n", common.Bytes2Hex(status.TrieValue))
+	fmt.Printf("Timestamp:        %d\n", status.Timestamp)
+	fmt.Printf("Request Block ID: %d\n", status.RequestBlockId)
+	fmt.Printf("Submitted:        %v\n", status.Submitted)
+	fmt.Printf("Challenged:       %v\n", status.Challenged)
+	fmt.Printf("Finalized:        %v\n", status.Finalized)
+
+	return nil
+}
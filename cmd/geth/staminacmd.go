@@ -2,9 +2,7 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"math/big"
-	"path/filepath"
 
 	"github.com/Onther-Tech/plasma-evm/accounts"
 	"github.com/Onther-Tech/plasma-evm/accounts/abi/bind"
@@ -12,7 +10,6 @@ import (
 	"github.com/Onther-Tech/plasma-evm/common"
 	stamina "github.com/Onther-Tech/plasma-evm/contracts/stamina/contract"
 	"github.com/Onther-Tech/plasma-evm/log"
-	"github.com/Onther-Tech/plasma-evm/node"
 	"github.com/Onther-Tech/plasma-evm/params"
 	"github.com/Onther-Tech/plasma-evm/plsclient"
 	"gopkg.in/urfave/cli.v1"
@@ -170,26 +167,7 @@ func initPlsOpts(ctx *cli.Context) (*bind.TransactOpts, *plsclient.Client) {
 		}
 	}
 
-	endpoint := ctx.GlobalString(utils.ChildChainUrlFlag.Name)
-	if endpoint == "" {
-		path := node.DefaultDataDir()
-		if ctx.GlobalIsSet(utils.DataDirFlag.Name) {
-			path = ctx.GlobalString(utils.DataDirFlag.Name)
-		}
-		if path != "" {
-			if ctx.GlobalBool(utils.TestnetFlag.Name) {
-				path = filepath.Join(path, "testnet")
-			} else if ctx.GlobalBool(utils.RinkebyFlag.Name) {
-				path = filepath.Join(path, "rinkeby")
-			}
-		}
-		endpoint = fmt.Sprintf("%s/geth.ipc", path)
-	}
-
-	client, err := dialRPC(endpoint)
-	if err != nil {
-		utils.Fatalf("Failed to connect endpoint: %v", err)
-	}
+	client := dialChildChain(ctx)
 
 	return opt, plsclient.NewClient(client)
 }
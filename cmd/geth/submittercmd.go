@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Onther-Tech/plasma-evm/accounts/abi/bind"
+	"github.com/Onther-Tech/plasma-evm/cmd/utils"
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/contracts/plasma"
+	"github.com/Onther-Tech/plasma-evm/contracts/plasma/rootchain"
+	"github.com/Onther-Tech/plasma-evm/log"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	submitterCmd = cli.Command{
+		Name:     "submitter",
+		Usage:    "Manage submitter accounts of RootChain contract",
+		Category: "PLASMA OPERATOR COMMANDS",
+		Description: `
+The submitter command registers or removes the accounts allowed to submit blocks
+to RootChain contract. Registered accounts can be used by the operator node with
+--operator.submitters flag.
+`,
+		Subcommands: []cli.Command{
+			{
+				Name:      "add",
+				Usage:     "Register a submitter account (operator only)",
+				ArgsUsage: "<address>",
+				Action:    utils.MigrateFlags(addSubmitter),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.RootChainUrlFlag,
+					utils.UnlockedAccountFlag,
+					utils.PasswordFileFlag,
+					utils.RootChainSenderFlag,
+					utils.DeveloperKeyFlag,
+					utils.RootChainGasPriceFlag,
+				},
+				Description: `
+    geth submitter add <address>
+
+Register the account as a submitter of RootChain contract.
+--rootchain.sender must be the operator.
+`,
+			},
+			{
+				Name:      "remove",
+				Usage:     "Remove a submitter account",
+				ArgsUsage: "<address>",
+				Action:    utils.MigrateFlags(removeSubmitter),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.RootChainUrlFlag,
+					utils.UnlockedAccountFlag,
+					utils.PasswordFileFlag,
+					utils.RootChainSenderFlag,
+					utils.DeveloperKeyFlag,
+					utils.RootChainGasPriceFlag,
+				},
+				Description: `
+    geth submitter remove <address>
+
+Remove the account from submitters of RootChain contract.
+RootChain contract only allows a submitter to renounce itself, so --rootchain.sender
+must be the submitter account. The operator cannot be removed.
+`,
+			},
+			{
+				Name:      "check",
+				Usage:     "Check whether the account is a submitter",
+				ArgsUsage: "<address>",
+				Action:    utils.MigrateFlags(checkSubmitter),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.RootChainUrlFlag,
+				},
+				Description: `
+    geth submitter check <address>
+
+Print whether the account is a submitter of RootChain contract.
+`,
+			},
+		},
+	}
+)
+
+func parseSubmitterArg(ctx *cli.Context) common.Address {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("Expected 1 parameter, not %d", len(ctx.Args()))
+	}
+
+	hex := ctx.Args().Get(0)
+	if !common.IsHexAddress(hex) {
+		utils.Fatalf("Invalid submitter address: %s", hex)
+	}
+	return common.HexToAddress(hex)
+}
+
+func addSubmitter(ctx *cli.Context) error {
+	submitter := parseSubmitterArg(ctx)
+
+	stack, cfg := makeConfigNode(ctx)
+	opt, backend := initOpts(ctx, stack, &cfg.Pls)
+	if opt == nil {
+		utils.Fatalf("Transaction sender is not set")
+	}
+
+	rootchainAddr := getRootChainAddr(cfg.Node.DataDir)
+	rootchainCtr, err := rootchain.NewRootChain(rootchainAddr, backend)
+	if err != nil {
+		utils.Fatalf("Failed to load RootChain contract: %v", err)
+	}
+
+	operator, err := rootchainCtr.Operator(&bind.CallOpts{Pending: false})
+	if err != nil {
+		utils.Fatalf("Failed to read operator: %v", err)
+	}
+	if operator != opt.From {
+		utils.Fatalf("Transaction sender is not the operator: %s", opt.From)
+	}
+
+	isSubmitter, err := rootchainCtr.IsSubmitter(&bind.CallOpts{Pending: false}, submitter)
+	if err != nil {
+		utils.Fatalf("Failed to read submitter: %v", err)
+	}
+	if isSubmitter {
+		utils.Fatalf("Account is already a submitter: %s", submitter)
+	}
+
+	log.Info("Add submitter", "rootchain", rootchainAddr, "submitter", submitter)
+
+	tx, err := rootchainCtr.AddSubmitter(opt, submitter)
+	if err != nil {
+		utils.Fatalf("Failed to send transaction: %v", err)
+	}
+
+	if err = plasma.WaitTx(backend, tx.Hash()); err != nil {
+		utils.Fatalf("Failed to send transaction: %v", err)
+	}
+
+	return nil
+}
+
+func removeSubmitter(ctx *cli.Context) error {
+	submitter := parseSubmitterArg(ctx)
+
+	stack, cfg := makeConfigNode(ctx)
+	opt, backend := initOpts(ctx, stack, &cfg.Pls)
+	if opt == nil {
+		utils.Fatalf("Transaction sender is not set")
+	}
+	if submitter != opt.From {
+		utils.Fatalf("Transaction sender is not the submitter: %s", opt.From)
+	}
+
+	rootchainAddr := getRootChainAddr(cfg.Node.DataDir)
+	rootchainCtr, err := rootchain.NewRootChain(rootchainAddr, backend)
+	if err != nil {
+		utils.Fatalf("Failed to load RootChain contract: %v", err)
+	}
+
+	operator, err := rootchainCtr.Operator(&bind.CallOpts{Pending: false})
+	if err != nil {
+		utils.Fatalf("Failed to read operator: %v", err)
+	}
+	if operator == submitter {
+		utils.Fatalf("Cannot remove the operator from submitters")
+	}
+
+	isSubmitter, err := rootchainCtr.IsSubmitter(&bind.CallOpts{Pending: false}, submitter)
+	if err != nil {
+		utils.Fatalf("Failed to read submitter: %v", err)
+	}
+	if !isSubmitter {
+		utils.Fatalf("Account is not a submitter: %s", submitter)
+	}
+
+	log.Info("Remove submitter", "rootchain", rootchainAddr, "submitter", submitter)
+
+	tx, err := rootchainCtr.RenounceSubmitter(opt)
+	if err != nil {
+		utils.Fatalf("Failed to send transaction: %v", err)
+	}
+
+	if err = plasma.WaitTx(backend, tx.Hash()); err != nil {
+		utils.Fatalf("Failed to send transaction: %v", err)
+	}
+
+	return nil
+}
+
+func checkSubmitter(ctx *cli.Context) error {
+	submitter := parseSubmitterArg(ctx)
+
+	stack, cfg := makeConfigNode(ctx)
+	_, backend := initOpts(ctx, stack, &cfg.Pls)
+
+	rootchainAddr := getRootChainAddr(cfg.Node.DataDir)
+	rootchainCtr, err := rootchain.NewRootChain(rootchainAddr, backend)
+	if err != nil {
+		utils.Fatalf("Failed to load RootChain contract: %v", err)
+	}
+
+	isSubmitter, err := rootchainCtr.IsSubmitter(&bind.CallOpts{Pending: false}, submitter)
+	if err != nil {
+		utils.Fatalf("Failed to read submitter: %v", err)
+	}
+
+	fmt.Printf("%s is submitter: %v\n", submitter.Hex(), isSubmitter)
+	return nil
+}
@@ -265,6 +265,9 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.OperatorKeyFlag,
 			utils.OperatorPasswordFileFlag,
 			utils.OperatorMinEtherFlag,
+			utils.OperatorMinRunwayFlag,
+			utils.OperatorSubmittersFlag,
+			utils.OperatorPipelineFlag,
 			utils.MinerRecommitIntervalFlag,
 		},
 	},
@@ -275,6 +278,9 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.TxMinGasPriceFlag,
 			utils.TxMaxGasPriceFlag,
 			utils.TxResubmitFlag,
+			utils.TxGasPriceStrategyFlag,
+			utils.TxDeadlineWindowFlag,
+			utils.TxFillNonceGapFlag,
 		},
 	},
 	{
@@ -291,6 +297,19 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.ChallengerAddressFlag,
 			utils.ChallengerPasswordFileFlag,
+			utils.URBSubmitterAddressFlag,
+			utils.URBSubmitterPasswordFileFlag,
+		},
+	},
+	{
+		Name: "PLASMA EVM - FINALIZER",
+		Flags: []cli.Flag{
+			utils.FinalizerFlag,
+			utils.FinalizerAccountFlag,
+			utils.FinalizerMaxBlocksFlag,
+			utils.FinalizerMaxRequestsFlag,
+			utils.FinalizerBudgetFlag,
+			utils.FinalizerIntervalFlag,
 		},
 	},
 	{
@@ -298,6 +317,9 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.RootChainUrlFlag,
 			utils.RootChainContractFlag,
+			utils.RootChainFallbackUrlsFlag,
+			utils.RootChainQuorumFlag,
+			utils.RootChainConfirmationsFlag,
 			utils.RootChainDeployGasPriceFlag,
 		},
 	},
@@ -65,6 +65,7 @@ import (
 	"github.com/Onther-Tech/plasma-evm/pls/downloader"
 	"github.com/Onther-Tech/plasma-evm/pls/gasprice"
 	"github.com/Onther-Tech/plasma-evm/rpc"
+	"github.com/Onther-Tech/plasma-evm/tx"
 
 	whisper "github.com/Onther-Tech/plasma-evm/whisper/whisperv6"
 	pcsclite "github.com/gballet/go-libpcsclite"
@@ -769,6 +770,20 @@ var (
 		Usage: "Plasma operator minimum balance (default = 0.5 ether)",
 		Value: "0.5",
 	}
+	OperatorMinRunwayFlag = cli.Uint64Flag{
+		Name:  "operator.minrunway",
+		Usage: "Minimum number of days the operator balance should last above --operator.minether at recent daily costs",
+		Value: pls.DefaultConfig.OperatorMinRunway,
+	}
+	OperatorSubmittersFlag = cli.StringFlag{
+		Name:  "operator.submitters",
+		Usage: "Comma separated addresses of additional accounts to submit blocks (unlocked with --operator.password)",
+		Value: "",
+	}
+	OperatorPipelineFlag = cli.BoolFlag{
+		Name:  "operator.pipeline",
+		Usage: "Mine the next NRE before it is prepared in root chain if the request epoch before it is empty",
+	}
 
 	// Challenger flags
 	ChallengerAddressFlag = cli.StringFlag{
@@ -780,12 +795,64 @@ var (
 		Usage: "Challenger password file to use for non-interactive password input",
 		Value: "",
 	}
+	URBSubmitterAddressFlag = cli.StringFlag{
+		Name:  "rootchain.urbsubmitter",
+		Usage: "Address of user account to prepare and submit URBs when operator withholds blocks. URBs are not prepared while RootChain contract reverts prepareToSubmitURB",
+	}
+	URBSubmitterPasswordFileFlag = cli.StringFlag{
+		Name:  "urbsubmitter.password",
+		Usage: "URB submitter password file to use for non-interactive password input",
+		Value: "",
+	}
+
+	// Finalizer flags
+	FinalizerFlag = cli.BoolFlag{
+		Name:  "finalizer",
+		Usage: "Enable finalizer to finalize blocks and requests in RootChain contract automatically",
+	}
+	FinalizerAccountFlag = cli.StringFlag{
+		Name:  "finalizer.account",
+		Usage: "Address of account to send finalize transactions. it MUST be unlocked by --unlock, --password flags (default = operator or challenger account)",
+	}
+	FinalizerMaxBlocksFlag = cli.Uint64Flag{
+		Name:  "finalizer.maxblocks",
+		Usage: "Maximum number of finalizeBlock transactions in a round",
+		Value: pls.DefaultConfig.Finalizer.MaxBlocks,
+	}
+	FinalizerMaxRequestsFlag = cli.Uint64Flag{
+		Name:  "finalizer.maxrequests",
+		Usage: "Maximum number of requests to finalize in a transaction",
+		Value: pls.DefaultConfig.Finalizer.MaxRequests,
+	}
+	FinalizerBudgetFlag = cli.Float64Flag{
+		Name:  "finalizer.budget",
+		Usage: "Maximum amount of ether to spend on finalize transactions (default = 0, no limit)",
+	}
+	FinalizerIntervalFlag = cli.DurationFlag{
+		Name:  "finalizer.interval",
+		Usage: "Interval to check blocks and requests to finalize (default = 1m). See https://golang.org/pkg/time/#ParseDuration",
+		Value: pls.DefaultConfig.Finalizer.Interval,
+	}
 
 	// root chain client flags
 	RootChainUrlFlag = cli.StringFlag{
 		Name:  "rootchain.url",
 		Usage: "JSONRPC endpoint of rootchain provider. If URL is empty, ignore the provider.",
 	}
+	RootChainFallbackUrlsFlag = cli.StringFlag{
+		Name:  "rootchain.fallbackurls",
+		Usage: "Comma separated JSONRPC endpoints of rootchain providers to fail over to",
+	}
+	RootChainQuorumFlag = cli.IntFlag{
+		Name:  "rootchain.quorum",
+		Usage: "Number of rootchain providers which must agree on RootChain contract state",
+		Value: 1,
+	}
+	RootChainConfirmationsFlag = cli.Uint64Flag{
+		Name:  "rootchain.confirmations",
+		Usage: "Number of root chain blocks to wait before processing RootChain contract events",
+		Value: pls.DefaultConfig.RootChainConfirmations,
+	}
 	RootChainGasPriceFlag = BigFlag{
 		Name:  "rootchain.gasprice",
 		Usage: "Transaction gas price to root chain in GWei",
@@ -852,6 +919,20 @@ var (
 		Usage: "Pending interval time after submitting a block (default = 10s). If block submit transaction is not mined in 2 intervals, gas price will be adjusted. See https://golang.org/pkg/time/#ParseDuration",
 		Value: pls.DefaultConfig.TxConfig.Interval,
 	}
+	TxGasPriceStrategyFlag = cli.StringFlag{
+		Name:  "tx.strategy",
+		Usage: "Default gas price strategy for root chain transactions (fixed, oracle or deadline). Challenge transactions always use deadline strategy",
+		Value: pls.DefaultConfig.TxConfig.GasPriceStrategy,
+	}
+	TxDeadlineWindowFlag = cli.DurationFlag{
+		Name:  "tx.deadlinewindow",
+		Usage: "Period before the deadline of a transaction to escalate gas price up to max gas price",
+		Value: pls.DefaultConfig.TxConfig.DeadlineWindow,
+	}
+	TxFillNonceGapFlag = cli.BoolFlag{
+		Name:  "tx.fillnoncegap",
+		Usage: "Fill nonce gaps before sent transactions with self-transfers. Disabled by default because a lagging root chain provider reports a pending nonce lower than the actual one, which looks like a gap; gaps are only logged then. Enable it only if every root chain provider is up to date",
+	}
 
 	// Child Chain Transaction Flags
 	ChildChainUrlFlag = cli.StringFlag{
@@ -1239,6 +1320,30 @@ func MakePasswordList(ctx *cli.Context) []string {
 	return lines
 }
 
+// findSigner finds the wallet of the account to sign root chain transactions.
+// Account in the keystore is unlocked with the password file. Account in an
+// external signer or a USB wallet is left to the wallet to approve signing.
+func findSigner(ctx *cli.Context, am *accounts.Manager, ks *keystore.KeyStore, addr common.Address, passwordFlag string, role string) accounts.Account {
+	account := accounts.Account{Address: addr}
+
+	wallet, err := am.Find(account)
+	if err != nil {
+		Fatalf("Failed to find %s account: %v", role, err)
+	}
+
+	if ks != nil && ks.HasAddress(addr) {
+		pwd := readPassword(ctx, ctx.GlobalString(passwordFlag))
+		if err := ks.Unlock(account, pwd); err != nil {
+			Fatalf("Failed to unlock %s account: %v", role, err)
+		}
+		log.Info(fmt.Sprintf("%s account is unlocked", strings.Title(role)), "address", addr)
+	} else {
+		log.Info(fmt.Sprintf("%s account is signed by wallet", strings.Title(role)), "address", addr, "wallet", wallet.URL())
+	}
+
+	return account
+}
+
 func readPassword(ctx *cli.Context, path string) string {
 	if path == "" {
 		return ""
@@ -1590,6 +1695,7 @@ func SetPlsConfig(ctx *cli.Context, stack *node.Node, cfg *pls.Config) {
 	CheckExclusive(ctx, DeveloperFlag, ExternalSignerFlag) // Can't use both ephemeral unlocked and external signer
 	CheckExclusive(ctx, DeveloperFlag, RootChainContractFlag)
 	CheckExclusive(ctx, OperatorAddressFlag, OperatorKeyFlag)
+	CheckExclusive(ctx, OperatorKeyFlag, ExternalSignerFlag) // Operator key is imported into keystore
 
 	var ks *keystore.KeyStore
 	if keystores := stack.AccountManager().Backends(keystore.KeyStoreType); len(keystores) > 0 {
@@ -1667,6 +1773,14 @@ func SetPlsConfig(ctx *cli.Context, stack *node.Node, cfg *pls.Config) {
 		cfg.OperatorMinEther = big.NewInt(int64(v * params.Ether))
 	}
 
+	if ctx.GlobalIsSet(OperatorMinRunwayFlag.Name) {
+		cfg.OperatorMinRunway = ctx.GlobalUint64(OperatorMinRunwayFlag.Name)
+	}
+
+	if ctx.GlobalIsSet(OperatorPipelineFlag.Name) {
+		cfg.OperatorPipeline = ctx.GlobalBool(OperatorPipelineFlag.Name)
+	}
+
 	if ctx.GlobalIsSet(DeveloperKeyFlag.Name) {
 		devKeys := strings.Split(ctx.GlobalString(DeveloperKeyFlag.Name), ",")
 
@@ -1711,22 +1825,26 @@ func SetPlsConfig(ctx *cli.Context, stack *node.Node, cfg *pls.Config) {
 		}
 	}
 
-	if ctx.GlobalIsSet(OperatorAddressFlag.Name) {
-		operatorAddr = common.HexToAddress(ctx.GlobalString(OperatorAddressFlag.Name))
-		account, err := ks.Find(accounts.Account{Address: operatorAddr})
-
-		if err != nil {
-			Fatalf("Failed to find operator account: %v", err)
+	if ctx.GlobalIsSet(RootChainFallbackUrlsFlag.Name) {
+		for _, url := range strings.Split(ctx.GlobalString(RootChainFallbackUrlsFlag.Name), ",") {
+			if url = strings.TrimSpace(url); url != "" {
+				cfg.RootChainFallbackURLs = append(cfg.RootChainFallbackURLs, url)
+			}
 		}
+	}
 
-		pwd := readPassword(ctx, ctx.GlobalString(OperatorPasswordFileFlag.Name))
+	if ctx.GlobalIsSet(RootChainQuorumFlag.Name) {
+		cfg.RootChainQuorum = ctx.GlobalInt(RootChainQuorumFlag.Name)
+	}
 
-		if err = ks.Unlock(account, pwd); err != nil {
-			Fatalf("Failed to unlock operator account: %v", err)
-		}
+	if ctx.GlobalIsSet(RootChainConfirmationsFlag.Name) {
+		cfg.RootChainConfirmations = ctx.GlobalUint64(RootChainConfirmationsFlag.Name)
+	}
+
+	if ctx.GlobalIsSet(OperatorAddressFlag.Name) {
+		operatorAddr = common.HexToAddress(ctx.GlobalString(OperatorAddressFlag.Name))
 
-		log.Info("Operator account is unlocked", "address", operatorAddr)
-		cfg.Operator = account
+		cfg.Operator = findSigner(ctx, stack.AccountManager(), ks, operatorAddr, OperatorPasswordFileFlag.Name, "operator")
 		cfg.NodeMode = pls.ModeOperator
 	}
 
@@ -1758,24 +1876,36 @@ func SetPlsConfig(ctx *cli.Context, stack *node.Node, cfg *pls.Config) {
 		cfg.NodeMode = pls.ModeOperator
 	}
 
-	if ctx.GlobalIsSet(ChallengerAddressFlag.Name) {
-		hex := ctx.GlobalString(ChallengerAddressFlag.Name)
-		addr := common.HexToAddress(hex)
-		account, err := ks.Find(accounts.Account{Address: addr})
-		pwd := readPassword(ctx, ctx.GlobalString(ChallengerPasswordFileFlag.Name))
-
-		if err != nil {
-			Fatalf("Failed to find challenger account: %v", err)
+	if ctx.GlobalIsSet(OperatorSubmittersFlag.Name) {
+		if cfg.NodeMode != pls.ModeOperator {
+			Fatalf("Submitter accounts are only used by operator")
 		}
 
-		if err = ks.Unlock(account, pwd); err != nil {
-			Fatalf("Failed to unlock challenger account: %v", err)
+		for _, hex := range strings.Split(ctx.GlobalString(OperatorSubmittersFlag.Name), ",") {
+			hex = strings.TrimSpace(hex)
+			if hex == "" {
+				continue
+			}
+			if !common.IsHexAddress(hex) {
+				Fatalf("Invalid submitter address: %s", hex)
+			}
+			addr := common.HexToAddress(hex)
+			if addr == cfg.Operator.Address {
+				continue
+			}
+
+			submitter := findSigner(ctx, stack.AccountManager(), ks, addr, OperatorPasswordFileFlag.Name, "submitter")
+			cfg.Submitters = append(cfg.Submitters, submitter)
 		}
-		log.Info("Challenger account is unlocked", "address", addr)
+	}
+
+	if ctx.GlobalIsSet(ChallengerAddressFlag.Name) {
+		hex := ctx.GlobalString(ChallengerAddressFlag.Name)
+		addr := common.HexToAddress(hex)
 
-		challenger := accounts.Account{Address: addr}
+		challenger := findSigner(ctx, stack.AccountManager(), ks, addr, ChallengerPasswordFileFlag.Name, "challenger")
 
-		if cfg.Operator == challenger {
+		if cfg.Operator.Address == challenger.Address {
 			Fatalf("Cannot use same challenger account as operator")
 		}
 
@@ -1797,10 +1927,50 @@ func SetPlsConfig(ctx *cli.Context, stack *node.Node, cfg *pls.Config) {
 		}
 	}
 
+	if ctx.GlobalIsSet(URBSubmitterAddressFlag.Name) {
+		if cfg.NodeMode != pls.ModeUser {
+			Fatalf("URB submitter account is only used by user node. Operator does not submit URBs and challenger submits them with challenger account")
+		}
+
+		addr := common.HexToAddress(ctx.GlobalString(URBSubmitterAddressFlag.Name))
+		cfg.URBSubmitter = findSigner(ctx, stack.AccountManager(), ks, addr, URBSubmitterPasswordFileFlag.Name, "URB submitter")
+	}
+
 	if ctx.GlobalIsSet(RootChainContractFlag.Name) {
 		cfg.RootChainContract = common.HexToAddress(ctx.GlobalString(RootChainContractFlag.Name))
 	}
 
+	if ctx.GlobalBool(FinalizerFlag.Name) {
+		cfg.Finalizer.Enabled = true
+
+		if ctx.GlobalIsSet(FinalizerAccountFlag.Name) {
+			addr := common.HexToAddress(ctx.GlobalString(FinalizerAccountFlag.Name))
+			if _, err := stack.AccountManager().Find(accounts.Account{Address: addr}); err != nil {
+				Fatalf("Failed to find finalizer account: %v", err)
+			}
+			cfg.Finalizer.Account = accounts.Account{Address: addr}
+		} else if cfg.NodeMode == pls.ModeUser {
+			Fatalf("--%s flag is required to enable finalizer in user mode", FinalizerAccountFlag.Name)
+		}
+
+		cfg.Finalizer.MaxBlocks = ctx.GlobalUint64(FinalizerMaxBlocksFlag.Name)
+		cfg.Finalizer.MaxRequests = ctx.GlobalUint64(FinalizerMaxRequestsFlag.Name)
+		cfg.Finalizer.Interval = ctx.GlobalDuration(FinalizerIntervalFlag.Name)
+		if cfg.Finalizer.Interval <= 0 {
+			Fatalf("Finalizer interval must be positive: %v", cfg.Finalizer.Interval)
+		}
+
+		if ctx.GlobalIsSet(FinalizerBudgetFlag.Name) {
+			budget := ctx.GlobalFloat64(FinalizerBudgetFlag.Name)
+			if budget < 0 {
+				Fatalf("Finalizer budget cannot be negative: %g", budget)
+			}
+			cfg.Finalizer.Budget, _ = new(big.Float).Mul(big.NewFloat(budget), big.NewFloat(params.Ether)).Int(nil)
+		}
+
+		log.Info("Finalizer is enabled", "account", cfg.Finalizer.Account.Address, "maxblocks", cfg.Finalizer.MaxBlocks, "maxrequests", cfg.Finalizer.MaxRequests, "budget", cfg.Finalizer.Budget, "interval", cfg.Finalizer.Interval)
+	}
+
 	if ctx.GlobalIsSet(RPCGlobalGasCap.Name) {
 		cfg.RPCGasCap = new(big.Int).SetUint64(ctx.GlobalUint64(RPCGlobalGasCap.Name))
 	}
@@ -1879,6 +2049,21 @@ func SetPlsConfig(ctx *cli.Context, stack *node.Node, cfg *pls.Config) {
 
 	cfg.TxConfig.Interval = ctx.Duration(TxResubmitFlag.Name)
 
+	if ctx.GlobalIsSet(TxGasPriceStrategyFlag.Name) {
+		switch strategy := ctx.GlobalString(TxGasPriceStrategyFlag.Name); strategy {
+		case tx.FixedStepStrategy, tx.OracleStrategy, tx.DeadlineStrategy:
+			cfg.TxConfig.GasPriceStrategy = strategy
+		default:
+			Fatalf("Unknown gas price strategy: %s", strategy)
+		}
+	}
+	if ctx.GlobalIsSet(TxDeadlineWindowFlag.Name) {
+		cfg.TxConfig.DeadlineWindow = ctx.GlobalDuration(TxDeadlineWindowFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxFillNonceGapFlag.Name) {
+		cfg.TxConfig.FillNonceGap = ctx.GlobalBool(TxFillNonceGapFlag.Name)
+	}
+
 	log.Info("Set options for submitting a block", "mingaspirce", cfg.TxConfig.MinGasPrice, "maxgasprice", cfg.TxConfig.MaxGasPrice, "resubmit", cfg.TxConfig.Interval.String())
 
 	// default operator min ether = 1ether
@@ -19,9 +19,11 @@ package core
 import (
 	"fmt"
 
+	"github.com/Onther-Tech/plasma-evm/common"
 	"github.com/Onther-Tech/plasma-evm/consensus"
 	"github.com/Onther-Tech/plasma-evm/core/state"
 	"github.com/Onther-Tech/plasma-evm/core/types"
+	"github.com/Onther-Tech/plasma-evm/log"
 	"github.com/Onther-Tech/plasma-evm/params"
 )
 
@@ -70,9 +72,67 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 		}
 		return consensus.ErrPrunedAncestor
 	}
+	if block.IsRequest() && v.bc.requestTxsFn != nil {
+		return v.validateRequestTxs(block)
+	}
 	return nil
 }
 
+// validateRequestTxs verifies the request transactions of the request block
+// one by one against the request transactions rebuilt from root chain. The
+// block is postponed as a future block if the requests are not known yet or
+// can not be read from root chain.
+func (v *BlockValidator) validateRequestTxs(block *types.Block) error {
+	expected, err := v.bc.requestTxsFn(block)
+	if err != nil {
+		log.Warn("Failed to read requests of request block", "number", block.Number(), "hash", block.Hash(), "err", err)
+		return consensus.ErrFutureBlock
+	}
+	if expected == nil {
+		log.Debug("Requests of request block are not known yet", "number", block.Number(), "hash", block.Hash())
+		return consensus.ErrFutureBlock
+	}
+
+	txs := block.Transactions()
+	for i := 0; i < len(txs) || i < len(expected); i++ {
+		var reason string
+		switch {
+		case i >= len(expected):
+			reason = "added"
+		case i >= len(txs):
+			reason = "missing"
+		case txs[i].Hash() == expected[i].Hash():
+			continue
+		case containsTx(expected, txs[i].Hash()):
+			reason = "reordered"
+		default:
+			reason = "changed"
+		}
+
+		var have, want common.Hash
+		if i < len(txs) {
+			have = txs[i].Hash()
+		}
+		if i < len(expected) {
+			want = expected[i].Hash()
+		}
+
+		log.Error("Request block has invalid request transaction", "number", block.Number(), "hash", block.Hash(), "index", i, "reason", reason, "have", have, "want", want)
+		return fmt.Errorf("%v: %s at index %d (have %x, want %x)", ErrInvalidRequestTx, reason, i, have, want)
+	}
+	return nil
+}
+
+// containsTx returns true if the transactions include the transaction hash.
+func containsTx(txs types.Transactions, hash common.Hash) bool {
+	for _, tx := range txs {
+		if tx.Hash() == hash {
+			return true
+		}
+	}
+	return false
+}
+
 // ValidateState validates the various changes that happen after a state
 // transition, such as amount of used gas, the receipt roots and the state root
 // itself. ValidateState returns a database batch if the validation was a success
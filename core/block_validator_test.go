@@ -17,10 +17,14 @@
 package core
 
 import (
+	"errors"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/consensus"
 	"github.com/Onther-Tech/plasma-evm/consensus/ethash"
 	"github.com/Onther-Tech/plasma-evm/core/rawdb"
 	"github.com/Onther-Tech/plasma-evm/core/types"
@@ -197,3 +201,43 @@ func testHeaderConcurrentAbortion(t *testing.T, threads int) {
 		t.Errorf("verification count too large: have %d, want below %d", verified, 2*threads)
 	}
 }
+
+// Tests that request transactions of request blocks are validated against the
+// expected request transactions, and that the block is postponed if they are
+// not known.
+func TestValidateRequestTxs(t *testing.T) {
+	newRequestTx := func(nonce uint64) *types.Transaction {
+		return types.NewTransaction(nonce, common.Address{0x01}, common.Big0, params.RequestTxGasLimit, common.Big0, nil)
+	}
+	txs := types.Transactions{newRequestTx(0), newRequestTx(1)}
+	block := types.NewBlock(&types.Header{Number: common.Big1}, txs, nil, nil)
+
+	tests := []struct {
+		expected types.Transactions
+		err      error
+		want     error
+	}{
+		{expected: txs},
+		{expected: nil, want: consensus.ErrFutureBlock},
+		{err: errors.New("backend failure"), want: consensus.ErrFutureBlock},
+		{expected: txs[:1], want: ErrInvalidRequestTx},
+		{expected: types.Transactions{txs[1], txs[0]}, want: ErrInvalidRequestTx},
+		{expected: types.Transactions{txs[0], txs[1], newRequestTx(2)}, want: ErrInvalidRequestTx},
+	}
+
+	for i, tt := range tests {
+		v := &BlockValidator{bc: &BlockChain{requestTxsFn: func(*types.Block) (types.Transactions, error) {
+			return tt.expected, tt.err
+		}}}
+
+		err := v.validateRequestTxs(block)
+		switch {
+		case tt.want == nil && err != nil:
+			t.Errorf("test %d: unexpected error: %v", i, err)
+		case tt.want == ErrInvalidRequestTx && (err == nil || !strings.HasPrefix(err.Error(), ErrInvalidRequestTx.Error())):
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.want)
+		case tt.want == consensus.ErrFutureBlock && err != consensus.ErrFutureBlock:
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.want)
+		}
+	}
+}
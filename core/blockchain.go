@@ -182,6 +182,7 @@ type BlockChain struct {
 
 	badBlocks       *lru.Cache                     // Bad block cache
 	shouldPreserve  func(*types.Block) bool        // Function used to determine whether should preserve the given block.
+	requestTxsFn    RequestTxsFn                   // Function used to verify request transactions of request blocks to import.
 	terminateInsert func(common.Hash, uint64) bool // Testing hook used to terminate ancient receipt chain insertion.
 
 	// Plasma Chain Forks arguments
@@ -538,6 +539,15 @@ func (bc *BlockChain) Validator() Validator {
 	return bc.validator
 }
 
+// SetRequestTxsFn sets the function to rebuild request transactions of request
+// blocks. Request blocks to import are verified against them if it is set.
+func (bc *BlockChain) SetRequestTxsFn(fn RequestTxsFn) {
+	bc.chainmu.Lock()
+	defer bc.chainmu.Unlock()
+
+	bc.requestTxsFn = fn
+}
+
 // Processor returns the current processor.
 func (bc *BlockChain) Processor() Processor {
 	return bc.processor
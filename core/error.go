@@ -35,4 +35,8 @@ var (
 
 	// ErrNoGenesis is returned when there is no Genesis Block.
 	ErrNoGenesis = errors.New("genesis not found in chain")
+
+	// ErrInvalidRequestTx is returned if a request block to import has request
+	// transactions different from the requests in root chain.
+	ErrInvalidRequestTx = errors.New("invalid request transaction")
 )
@@ -0,0 +1,460 @@
+package rawdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/core/types"
+	"github.com/Onther-Tech/plasma-evm/ethdb"
+	"github.com/Onther-Tech/plasma-evm/log"
+	"github.com/Onther-Tech/plasma-evm/miner/epoch"
+	"github.com/Onther-Tech/plasma-evm/rlp"
+)
+
+// ReadInvalidExit retrieves the invalid exit of the index-th receipt in a request block.
+func ReadInvalidExit(db ethdb.Reader, fork uint64, num uint64, index uint64) *InvalidExit {
+	data, _ := db.Get(invalidExitKey(fork, num, index))
+	if len(data) == 0 {
+		return nil
+	}
+	ie := new(InvalidExit)
+	if err := rlp.DecodeBytes(data, ie); err != nil {
+		log.Error("Invalid invalid exit RLP", "fork number", fork, "block number", num, "index", index, "err", err)
+		return nil
+	}
+	return ie
+}
+
+// WriteInvalidExit stores an invalid exit.
+func WriteInvalidExit(db ethdb.KeyValueWriter, ie *InvalidExit) {
+	data, err := rlp.EncodeToBytes(ie)
+	if err != nil {
+		log.Crit("Failed to RLP encode invalid exit", "err", err)
+	}
+	if err := db.Put(invalidExitKey(ie.ForkNumber, ie.BlockNumber, ie.Index), data); err != nil {
+		log.Crit("Failed to store invalid exit", "err", err)
+	}
+}
+
+// DeleteInvalidExit removes an invalid exit.
+func DeleteInvalidExit(db ethdb.KeyValueWriter, fork uint64, num uint64, index uint64) {
+	if err := db.Delete(invalidExitKey(fork, num, index)); err != nil {
+		log.Crit("Failed to delete invalid exit", "err", err)
+	}
+}
+
+// ReadAllInvalidExits retrieves all the invalid exits in the database, ordered by
+// fork number, block number and receipt index.
+func ReadAllInvalidExits(db ethdb.Iteratee) []*InvalidExit {
+	it := db.NewIteratorWithPrefix(invalidExitPrefix)
+	defer it.Release()
+
+	var ies []*InvalidExit
+	for it.Next() {
+		ie := new(InvalidExit)
+		if err := rlp.DecodeBytes(it.Value(), ie); err != nil {
+			log.Error("Invalid invalid exit RLP", "key", it.Key(), "err", err)
+			continue
+		}
+		ies = append(ies, ie)
+	}
+	return ies
+}
+
+// WriteNullAddressTx stores a transaction from the null address in a
+// non-request block.
+func WriteNullAddressTx(db ethdb.KeyValueWriter, natx *NullAddressTx) {
+	data, err := rlp.EncodeToBytes(natx)
+	if err != nil {
+		log.Crit("Failed to RLP encode null address transaction", "err", err)
+	}
+	if err := db.Put(nullAddressTxKey(natx.BlockNumber, natx.Index), data); err != nil {
+		log.Crit("Failed to store null address transaction", "err", err)
+	}
+}
+
+// DeleteNullAddressTx removes a null address transaction.
+func DeleteNullAddressTx(db ethdb.KeyValueWriter, num uint64, index uint64) {
+	if err := db.Delete(nullAddressTxKey(num, index)); err != nil {
+		log.Crit("Failed to delete null address transaction", "err", err)
+	}
+}
+
+// ReadAllNullAddressTxs retrieves all the null address transactions in the
+// database, ordered by block number and transaction index.
+func ReadAllNullAddressTxs(db ethdb.Iteratee) []*NullAddressTx {
+	it := db.NewIteratorWithPrefix(nullAddressTxPrefix)
+	defer it.Release()
+
+	var natxs []*NullAddressTx
+	for it.Next() {
+		natx := new(NullAddressTx)
+		if err := rlp.DecodeBytes(it.Value(), natx); err != nil {
+			log.Error("Invalid null address transaction RLP", "key", it.Key(), "err", err)
+			continue
+		}
+		natxs = append(natxs, natx)
+	}
+	return natxs
+}
+
+// ReadFinalizerSpent retrieves the amount of ether spent by the finalizer.
+func ReadFinalizerSpent(db ethdb.Reader) *big.Int {
+	data, _ := db.Get(finalizerSpentKey)
+	return new(big.Int).SetBytes(data)
+}
+
+// WriteFinalizerSpent stores the amount of ether spent by the finalizer.
+func WriteFinalizerSpent(db ethdb.KeyValueWriter, spent *big.Int) {
+	if err := db.Put(finalizerSpentKey, spent.Bytes()); err != nil {
+		log.Crit("Failed to store finalizer spent", "err", err)
+	}
+}
+
+// ReadFinalizerCursor retrieves the number of confirmed transactions of the
+// finalizer account charged to the finalizer budget.
+func ReadFinalizerCursor(db ethdb.Reader) uint64 {
+	data, _ := db.Get(finalizerCursorKey)
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// WriteFinalizerCursor stores the number of confirmed transactions of the
+// finalizer account charged to the finalizer budget.
+func WriteFinalizerCursor(db ethdb.KeyValueWriter, n uint64) {
+	if err := db.Put(finalizerCursorKey, encodeBlockNumber(n)); err != nil {
+		log.Crit("Failed to store finalizer cursor", "err", err)
+	}
+}
+
+// ReadRootChainCursor retrieves the latest root chain block whose events are processed.
+func ReadRootChainCursor(db ethdb.Reader) *RootChainBlock {
+	data, _ := db.Get(rootchainCursorKey)
+	if len(data) == 0 {
+		return nil
+	}
+	b := new(RootChainBlock)
+	if err := rlp.DecodeBytes(data, b); err != nil {
+		log.Error("Invalid root chain cursor RLP", "err", err)
+		return nil
+	}
+	return b
+}
+
+// WriteRootChainCursor stores the latest root chain block whose events are processed.
+func WriteRootChainCursor(db ethdb.KeyValueWriter, b *RootChainBlock) {
+	data, err := rlp.EncodeToBytes(b)
+	if err != nil {
+		log.Crit("Failed to RLP encode root chain cursor", "err", err)
+	}
+	if err := db.Put(rootchainCursorKey, data); err != nil {
+		log.Crit("Failed to store root chain cursor", "err", err)
+	}
+}
+
+// ReadRootChainBlock retrieves the root chain block with processed events.
+func ReadRootChainBlock(db ethdb.Reader, num uint64) *RootChainBlock {
+	data, _ := db.Get(rootchainBlockKey(num))
+	if len(data) == 0 {
+		return nil
+	}
+	b := new(RootChainBlock)
+	if err := rlp.DecodeBytes(data, b); err != nil {
+		log.Error("Invalid root chain block RLP", "number", num, "err", err)
+		return nil
+	}
+	return b
+}
+
+// WriteRootChainBlock stores the root chain block with processed events.
+func WriteRootChainBlock(db ethdb.KeyValueWriter, b *RootChainBlock) {
+	data, err := rlp.EncodeToBytes(b)
+	if err != nil {
+		log.Crit("Failed to RLP encode root chain block", "err", err)
+	}
+	if err := db.Put(rootchainBlockKey(b.Number), data); err != nil {
+		log.Crit("Failed to store root chain block", "err", err)
+	}
+}
+
+// DeleteRootChainBlock removes the root chain block with processed events.
+func DeleteRootChainBlock(db ethdb.KeyValueWriter, num uint64) {
+	if err := db.Delete(rootchainBlockKey(num)); err != nil {
+		log.Crit("Failed to delete root chain block", "err", err)
+	}
+}
+
+// ReadEpochEnvSnapshot retrieves the epoch environment before the epoch is started.
+func ReadEpochEnvSnapshot(db ethdb.Reader, fork uint64, epochNumber uint64) *epoch.EpochEnvironment {
+	data, _ := db.Get(epochEnvSnapshotKey(fork, epochNumber))
+	if len(data) == 0 {
+		return nil
+	}
+	e := new(epoch.EpochEnvironment)
+	if err := rlp.DecodeBytes(data, e); err != nil {
+		log.Error("Invalid epoch environment snapshot RLP", "fork", fork, "epoch", epochNumber, "err", err)
+		return nil
+	}
+	return e
+}
+
+// WriteEpochEnvSnapshot stores the epoch environment before the epoch is started.
+func WriteEpochEnvSnapshot(db ethdb.KeyValueWriter, fork uint64, epochNumber uint64, e *epoch.EpochEnvironment) {
+	data, err := rlp.EncodeToBytes(e)
+	if err != nil {
+		log.Crit("Failed to RLP encode epoch environment snapshot", "err", err)
+	}
+	if err := db.Put(epochEnvSnapshotKey(fork, epochNumber), data); err != nil {
+		log.Crit("Failed to store epoch environment snapshot", "err", err)
+	}
+}
+
+// ReadRebaseTxs retrieves the transactions of the NRB rolled back by a fork,
+// to be included again in the rebased NRE.
+func ReadRebaseTxs(db ethdb.Reader, num uint64) types.Transactions {
+	data, _ := db.Get(rebaseTxsKey(num))
+	if len(data) == 0 {
+		return nil
+	}
+	var txs types.Transactions
+	if err := rlp.DecodeBytes(data, &txs); err != nil {
+		log.Error("Invalid rebase transactions RLP", "number", num, "err", err)
+		return nil
+	}
+	return txs
+}
+
+// WriteRebaseTxs stores the transactions of the NRB rolled back by a fork.
+func WriteRebaseTxs(db ethdb.KeyValueWriter, num uint64, txs types.Transactions) {
+	data, err := rlp.EncodeToBytes(txs)
+	if err != nil {
+		log.Crit("Failed to RLP encode rebase transactions", "err", err)
+	}
+	if err := db.Put(rebaseTxsKey(num), data); err != nil {
+		log.Crit("Failed to store rebase transactions", "err", err)
+	}
+}
+
+// DeleteRebaseTxs removes the transactions of the NRB rolled back by a fork.
+func DeleteRebaseTxs(db ethdb.KeyValueWriter, num uint64) {
+	if err := db.Delete(rebaseTxsKey(num)); err != nil {
+		log.Crit("Failed to delete rebase transactions", "err", err)
+	}
+}
+
+// ReadRequestIndex retrieves the request index of the request.
+func ReadRequestIndex(db ethdb.Reader, userActivated bool, requestId uint64) *RequestIndex {
+	data, _ := db.Get(requestIndexKey(userActivated, requestId))
+	if len(data) == 0 {
+		return nil
+	}
+	ri := new(RequestIndex)
+	if err := rlp.DecodeBytes(data, ri); err != nil {
+		log.Error("Invalid request index RLP", "userActivated", userActivated, "requestId", requestId, "err", err)
+		return nil
+	}
+	return ri
+}
+
+// WriteRequestIndex stores the request index and indexes it by requestor. The
+// index of a rebased request overwrites the previous one.
+func WriteRequestIndex(db ethdb.KeyValueWriter, ri *RequestIndex) {
+	data, err := rlp.EncodeToBytes(ri)
+	if err != nil {
+		log.Crit("Failed to RLP encode request index", "err", err)
+	}
+	if err := db.Put(requestIndexKey(ri.UserActivated, ri.RequestId), data); err != nil {
+		log.Crit("Failed to store request index", "err", err)
+	}
+	if err := db.Put(requestorIndexKey(ri.Requestor, ri.UserActivated, ri.RequestId), []byte{}); err != nil {
+		log.Crit("Failed to store requestor index", "err", err)
+	}
+}
+
+// ReadRequestIndexesByRequestor retrieves the request indexes of the requestor,
+// ordered by request id with EROs first.
+func ReadRequestIndexesByRequestor(db ethdb.Database, requestor common.Address) []*RequestIndex {
+	prefix := append(requestorIndexPrefix, requestor.Bytes()...)
+
+	it := db.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+
+	var ris []*RequestIndex
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(prefix)+1+8 {
+			continue
+		}
+		userActivated := key[len(prefix)] == 1
+		requestId := binary.BigEndian.Uint64(key[len(prefix)+1:])
+
+		if ri := ReadRequestIndex(db, userActivated, requestId); ri != nil {
+			ris = append(ris, ri)
+		}
+	}
+	return ris
+}
+
+// ReadSubmissionFault retrieves the submission fault of the NRE or the block
+// starting at the block number.
+func ReadSubmissionFault(db ethdb.Reader, fork uint64, num uint64) *SubmissionFault {
+	data, _ := db.Get(submissionFaultKey(fork, num))
+	if len(data) == 0 {
+		return nil
+	}
+	sf := new(SubmissionFault)
+	if err := rlp.DecodeBytes(data, sf); err != nil {
+		log.Error("Invalid submission fault RLP", "fork number", fork, "block number", num, "err", err)
+		return nil
+	}
+	return sf
+}
+
+// WriteSubmissionFault stores a submission fault.
+func WriteSubmissionFault(db ethdb.KeyValueWriter, sf *SubmissionFault) {
+	data, err := rlp.EncodeToBytes(sf)
+	if err != nil {
+		log.Crit("Failed to RLP encode submission fault", "err", err)
+	}
+	if err := db.Put(submissionFaultKey(sf.ForkNumber, sf.StartBlockNumber), data); err != nil {
+		log.Crit("Failed to store submission fault", "err", err)
+	}
+}
+
+// ReadAllSubmissionFaults retrieves all the submission faults in the database,
+// ordered by fork number and block number.
+func ReadAllSubmissionFaults(db ethdb.Iteratee) []*SubmissionFault {
+	it := db.NewIteratorWithPrefix(submissionFaultPrefix)
+	defer it.Release()
+
+	var sfs []*SubmissionFault
+	for it.Next() {
+		sf := new(SubmissionFault)
+		if err := rlp.DecodeBytes(it.Value(), sf); err != nil {
+			log.Error("Invalid submission fault RLP", "key", it.Key(), "err", err)
+			continue
+		}
+		sfs = append(sfs, sf)
+	}
+	return sfs
+}
+
+// ReadRequestEpochCheckpoint retrieves the progress of the request epoch being
+// mined.
+func ReadRequestEpochCheckpoint(db ethdb.Reader) *RequestEpochCheckpoint {
+	data, _ := db.Get(requestEpochCheckpointKey)
+	if len(data) == 0 {
+		return nil
+	}
+	cp := new(RequestEpochCheckpoint)
+	if err := rlp.DecodeBytes(data, cp); err != nil {
+		log.Error("Invalid request epoch checkpoint RLP", "err", err)
+		return nil
+	}
+	return cp
+}
+
+// WriteRequestEpochCheckpoint stores the progress of the request epoch being
+// mined.
+func WriteRequestEpochCheckpoint(db ethdb.KeyValueWriter, cp *RequestEpochCheckpoint) {
+	data, err := rlp.EncodeToBytes(cp)
+	if err != nil {
+		log.Crit("Failed to RLP encode request epoch checkpoint", "err", err)
+	}
+	if err := db.Put(requestEpochCheckpointKey, data); err != nil {
+		log.Crit("Failed to store request epoch checkpoint", "err", err)
+	}
+}
+
+// DeleteRequestEpochCheckpoint removes the progress of the request epoch.
+func DeleteRequestEpochCheckpoint(db ethdb.KeyValueWriter) {
+	if err := db.Delete(requestEpochCheckpointKey); err != nil {
+		log.Crit("Failed to delete request epoch checkpoint", "err", err)
+	}
+}
+
+// ReadSpeculativeEpoch retrieves the NRE mined before it is prepared.
+func ReadSpeculativeEpoch(db ethdb.Reader) *SpeculativeEpoch {
+	data, _ := db.Get(speculativeEpochKey)
+	if len(data) == 0 {
+		return nil
+	}
+	se := new(SpeculativeEpoch)
+	if err := rlp.DecodeBytes(data, se); err != nil {
+		log.Error("Invalid speculative epoch RLP", "err", err)
+		return nil
+	}
+	return se
+}
+
+// WriteSpeculativeEpoch stores the NRE mined before it is prepared.
+func WriteSpeculativeEpoch(db ethdb.KeyValueWriter, se *SpeculativeEpoch) {
+	data, err := rlp.EncodeToBytes(se)
+	if err != nil {
+		log.Crit("Failed to RLP encode speculative epoch", "err", err)
+	}
+	if err := db.Put(speculativeEpochKey, data); err != nil {
+		log.Crit("Failed to store speculative epoch", "err", err)
+	}
+}
+
+// DeleteSpeculativeEpoch removes the speculative epoch.
+func DeleteSpeculativeEpoch(db ethdb.KeyValueWriter) {
+	if err := db.Delete(speculativeEpochKey); err != nil {
+		log.Crit("Failed to delete speculative epoch", "err", err)
+	}
+}
+
+// WriteOperatorCost stores the cost of a root chain transaction.
+func WriteOperatorCost(db ethdb.KeyValueWriter, c *OperatorCost) {
+	data, err := rlp.EncodeToBytes(c)
+	if err != nil {
+		log.Crit("Failed to RLP encode operator cost", "err", err)
+	}
+	if err := db.Put(operatorCostKey(c.Timestamp, c.TxHash), data); err != nil {
+		log.Crit("Failed to store operator cost", "err", err)
+	}
+}
+
+// ReadOperatorCosts retrieves the costs of root chain transactions mined at or
+// after the timestamp, ordered by timestamp.
+func ReadOperatorCosts(db ethdb.Iteratee, since uint64) []*OperatorCost {
+	it := db.NewIteratorWithStart(append(append([]byte{}, operatorCostPrefix...), encodeBlockNumber(since)...))
+	defer it.Release()
+
+	var costs []*OperatorCost
+	for it.Next() {
+		if !bytes.HasPrefix(it.Key(), operatorCostPrefix) {
+			break
+		}
+		c := new(OperatorCost)
+		if err := rlp.DecodeBytes(it.Value(), c); err != nil {
+			log.Error("Invalid operator cost RLP", "key", it.Key(), "err", err)
+			continue
+		}
+		costs = append(costs, c)
+	}
+	return costs
+}
+
+// ReadCostCursor retrieves the number of confirmed transactions of the account
+// whose costs are recorded.
+func ReadCostCursor(db ethdb.Reader, addr common.Address) uint64 {
+	data, _ := db.Get(costCursorKey(addr))
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// WriteCostCursor stores the number of confirmed transactions of the account
+// whose costs are recorded.
+func WriteCostCursor(db ethdb.KeyValueWriter, addr common.Address, n uint64) {
+	if err := db.Put(costCursorKey(addr), encodeBlockNumber(n)); err != nil {
+		log.Crit("Failed to store cost cursor", "err", err)
+	}
+}
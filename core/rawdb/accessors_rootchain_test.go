@@ -0,0 +1,305 @@
+package rawdb
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/core/types"
+)
+
+// Tests invalid exit storage and retrieval operations.
+func TestInvalidExitStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	receipt := &types.Receipt{
+		Status:            types.ReceiptStatusFailed,
+		CumulativeGasUsed: 1,
+		Logs:              []*types.Log{},
+	}
+	ies := []*InvalidExit{
+		{ForkNumber: 0, BlockNumber: 4, Index: 1, Receipt: receipt, Proof: []common.Hash{{0x01}}},
+		{ForkNumber: 0, BlockNumber: 4, Index: 0, Receipt: receipt, Proof: []common.Hash{{0x02}}},
+		{ForkNumber: 1, BlockNumber: 2, Index: 0, Receipt: receipt, Status: InvalidExitChallengeSent, RequestId: 3},
+	}
+
+	if ie := ReadInvalidExit(db, 0, 4, 1); ie != nil {
+		t.Fatalf("Non existent invalid exit returned: %v", ie)
+	}
+	for _, ie := range ies {
+		WriteInvalidExit(db, ie)
+	}
+
+	ie := ReadInvalidExit(db, 1, 2, 0)
+	if ie == nil {
+		t.Fatalf("Stored invalid exit not found")
+	}
+	if ie.Status != InvalidExitChallengeSent || ie.RequestId != 3 {
+		t.Fatalf("Invalid exit status mismatch: have %v %d, want %v %d", ie.Status, ie.RequestId, InvalidExitChallengeSent, 3)
+	}
+	if ie.Receipt.Status != types.ReceiptStatusFailed {
+		t.Fatalf("Invalid exit receipt status mismatch: have %v, want %v", ie.Receipt.Status, types.ReceiptStatusFailed)
+	}
+
+	all := ReadAllInvalidExits(db)
+	if len(all) != len(ies) {
+		t.Fatalf("Invalid exits count mismatch: have %d, want %d", len(all), len(ies))
+	}
+	// iterated in (fork, block, index) order
+	if all[0].Index != 0 || all[1].Index != 1 || all[2].ForkNumber != 1 {
+		t.Fatalf("Invalid exits are not ordered: %v", all)
+	}
+
+	DeleteInvalidExit(db, 0, 4, 1)
+	if ie := ReadInvalidExit(db, 0, 4, 1); ie != nil {
+		t.Fatalf("Deleted invalid exit returned: %v", ie)
+	}
+}
+
+// Tests null address transaction storage and retrieval operations.
+func TestNullAddressTxStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	if natxs := ReadAllNullAddressTxs(db); len(natxs) != 0 {
+		t.Fatalf("Non existent null address transactions returned: %v", natxs)
+	}
+
+	natxs := []*NullAddressTx{
+		{BlockNumber: 7, Index: 2, TxByte: []byte{0x01}, Proof: []common.Hash{{0x01}}},
+		{BlockNumber: 7, Index: 0, TxByte: []byte{0x02}, Proof: []common.Hash{{0x02}, {0x03}}},
+		{BlockNumber: 3, Index: 5, TxByte: []byte{0x03}},
+	}
+	for _, natx := range natxs {
+		WriteNullAddressTx(db, natx)
+	}
+
+	all := ReadAllNullAddressTxs(db)
+	if len(all) != len(natxs) {
+		t.Fatalf("Null address transactions count mismatch: have %d, want %d", len(all), len(natxs))
+	}
+	// iterated in (block, index) order
+	if all[0].BlockNumber != 3 || all[1].Index != 0 || all[2].Index != 2 {
+		t.Fatalf("Null address transactions are not ordered: %v", all)
+	}
+	if !bytes.Equal(all[1].TxByte, natxs[1].TxByte) || len(all[1].Proof) != 2 || all[1].Proof[1] != natxs[1].Proof[1] {
+		t.Fatalf("Null address transaction mismatch: have %v, want %v", all[1], natxs[1])
+	}
+
+	DeleteNullAddressTx(db, 7, 2)
+	if all := ReadAllNullAddressTxs(db); len(all) != 2 || all[1].Index != 0 {
+		t.Fatalf("Deleted null address transaction returned: %v", all)
+	}
+}
+
+// Tests rebase transactions storage and retrieval operations.
+func TestRebaseTxsStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	if txs := ReadRebaseTxs(db, 7); txs != nil {
+		t.Fatalf("Non existent rebase transactions returned: %v", txs)
+	}
+
+	txs := types.Transactions{
+		types.NewTransaction(0, common.Address{0x01}, big.NewInt(1), 21000, big.NewInt(1), nil),
+		types.NewTransaction(1, common.Address{0x02}, big.NewInt(2), 21000, big.NewInt(1), []byte{0x01}),
+	}
+	WriteRebaseTxs(db, 7, txs)
+	WriteRebaseTxs(db, 8, types.Transactions{})
+
+	if have := ReadRebaseTxs(db, 7); len(have) != len(txs) || have[1].Hash() != txs[1].Hash() {
+		t.Fatalf("Rebase transactions mismatch: have %v, want %v", have, txs)
+	}
+	// transactions of an empty NRB are distinguished from missing ones.
+	if have := ReadRebaseTxs(db, 8); have == nil || len(have) != 0 {
+		t.Fatalf("Empty rebase transactions mismatch: have %v", have)
+	}
+
+	DeleteRebaseTxs(db, 7)
+	if txs := ReadRebaseTxs(db, 7); txs != nil {
+		t.Fatalf("Deleted rebase transactions returned: %v", txs)
+	}
+}
+
+// Tests request index storage and retrieval operations.
+func TestRequestIndexStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	requestor := common.Address{0x01}
+	ris := []*RequestIndex{
+		{RequestId: 2, Requestor: requestor, Type: RequestTypeExit, BlockNumber: 5, Index: 1, TxHash: common.Hash{0x01}},
+		{RequestId: 1, Requestor: common.Address{0x02}, Type: RequestTypeTransfer, BlockNumber: 5, Index: 0},
+		{RequestId: 0, UserActivated: true, Requestor: requestor, Type: RequestTypeExit, ForkNumber: 1, BlockNumber: 4},
+	}
+
+	if ri := ReadRequestIndex(db, false, 2); ri != nil {
+		t.Fatalf("Non existent request index returned: %v", ri)
+	}
+	for _, ri := range ris {
+		WriteRequestIndex(db, ri)
+	}
+
+	ri := ReadRequestIndex(db, false, 2)
+	if ri == nil {
+		t.Fatalf("Stored request index not found")
+	}
+	if ri.Type != RequestTypeExit || ri.TxHash != (common.Hash{0x01}) {
+		t.Fatalf("Request index mismatch: have %v %x, want %v %x", ri.Type, ri.TxHash, RequestTypeExit, common.Hash{0x01})
+	}
+	if ri := ReadRequestIndex(db, true, 2); ri != nil {
+		t.Fatalf("Request index of ERO returned as ERU: %v", ri)
+	}
+
+	// rebased request overwrites the previous index
+	WriteRequestIndex(db, &RequestIndex{RequestId: 0, UserActivated: true, Requestor: requestor, Type: RequestTypeExit, ForkNumber: 2, BlockNumber: 4})
+	if ri := ReadRequestIndex(db, true, 0); ri == nil || ri.ForkNumber != 2 {
+		t.Fatalf("Request index is not overwritten: %v", ri)
+	}
+
+	all := ReadRequestIndexesByRequestor(db, requestor)
+	if len(all) != 2 {
+		t.Fatalf("Request indexes count mismatch: have %d, want %d", len(all), 2)
+	}
+	// EROs first
+	if all[0].UserActivated || !all[1].UserActivated {
+		t.Fatalf("Request indexes are not ordered: %v", all)
+	}
+	if all[1].ForkNumber != 2 {
+		t.Fatalf("Rebased request index is not returned: %v", all[1])
+	}
+	if other := ReadRequestIndexesByRequestor(db, common.Address{0x03}); len(other) != 0 {
+		t.Fatalf("Request indexes of unknown requestor returned: %v", other)
+	}
+}
+
+// Tests submission fault storage and retrieval operations.
+func TestSubmissionFaultStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	sfs := []*SubmissionFault{
+		{Kind: SubmissionWithheld, ForkNumber: 0, EpochNumber: 3, StartBlockNumber: 5, EndBlockNumber: 5, IsRequest: true},
+		{Kind: SubmissionRootMismatch, ForkNumber: 0, EpochNumber: 2, StartBlockNumber: 1, EndBlockNumber: 4, StatesRoot: common.Hash{0x01}, LocalStatesRoot: common.Hash{0x02}},
+	}
+
+	if sf := ReadSubmissionFault(db, 0, 1); sf != nil {
+		t.Fatalf("Non existent submission fault returned: %v", sf)
+	}
+	for _, sf := range sfs {
+		WriteSubmissionFault(db, sf)
+	}
+
+	sf := ReadSubmissionFault(db, 0, 1)
+	if sf == nil {
+		t.Fatalf("Stored submission fault not found")
+	}
+	if sf.Kind != SubmissionRootMismatch || sf.LocalStatesRoot != (common.Hash{0x02}) {
+		t.Fatalf("Submission fault mismatch: have %v %x, want %v %x", sf.Kind, sf.LocalStatesRoot, SubmissionRootMismatch, common.Hash{0x02})
+	}
+
+	all := ReadAllSubmissionFaults(db)
+	if len(all) != len(sfs) {
+		t.Fatalf("Submission faults count mismatch: have %d, want %d", len(all), len(sfs))
+	}
+	// iterated in (fork, block) order
+	if all[0].StartBlockNumber != 1 || all[1].StartBlockNumber != 5 {
+		t.Fatalf("Submission faults are not ordered: %v", all)
+	}
+}
+
+// Tests request epoch checkpoint storage and retrieval operations.
+func TestRequestEpochCheckpointStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	if cp := ReadRequestEpochCheckpoint(db); cp != nil {
+		t.Fatalf("Non existent request epoch checkpoint returned: %v", cp)
+	}
+
+	WriteRequestEpochCheckpoint(db, &RequestEpochCheckpoint{
+		ForkNumber:       1,
+		EpochNumber:      4,
+		StartBlockNumber: 7,
+		Rebase:           true,
+		RequestBlockIds:  []uint64{2, 3, 5},
+		NumMined:         2,
+		NumSubmitted:     1,
+	})
+
+	cp := ReadRequestEpochCheckpoint(db)
+	if cp == nil {
+		t.Fatalf("Stored request epoch checkpoint not found")
+	}
+	if cp.EpochNumber != 4 || !cp.Rebase || len(cp.RequestBlockIds) != 3 || cp.RequestBlockIds[2] != 5 || cp.NumMined != 2 || cp.NumSubmitted != 1 {
+		t.Fatalf("Request epoch checkpoint mismatch: %v", cp)
+	}
+
+	DeleteRequestEpochCheckpoint(db)
+	if cp := ReadRequestEpochCheckpoint(db); cp != nil {
+		t.Fatalf("Deleted request epoch checkpoint returned: %v", cp)
+	}
+}
+
+// Tests speculative epoch storage and retrieval operations.
+func TestSpeculativeEpochStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	if se := ReadSpeculativeEpoch(db); se != nil {
+		t.Fatalf("Non existent speculative epoch returned: %v", se)
+	}
+
+	want := SpeculativeEpoch{ForkNumber: 0, EpochNumber: 5, StartBlockNumber: 9, EndBlockNumber: 12}
+	WriteSpeculativeEpoch(db, &want)
+
+	if se := ReadSpeculativeEpoch(db); se == nil || *se != want {
+		t.Fatalf("Speculative epoch mismatch: have %v, want %v", se, want)
+	}
+
+	DeleteSpeculativeEpoch(db)
+	if se := ReadSpeculativeEpoch(db); se != nil {
+		t.Fatalf("Deleted speculative epoch returned: %v", se)
+	}
+}
+
+// Tests operator cost storage and retrieval operations.
+func TestOperatorCostStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	if costs := ReadOperatorCosts(db, 0); len(costs) != 0 {
+		t.Fatalf("Non existent operator costs returned: %v", costs)
+	}
+
+	for i, timestamp := range []uint64{300, 100, 200} {
+		WriteOperatorCost(db, &OperatorCost{
+			Kind:      CostSubmission,
+			Method:    "submitORB",
+			TxHash:    common.BytesToHash([]byte{byte(i + 1)}),
+			Timestamp: timestamp,
+			GasUsed:   21000,
+			GasPrice:  big.NewInt(1),
+			Value:     big.NewInt(int64(timestamp)),
+			Refund:    new(big.Int),
+		})
+	}
+
+	costs := ReadOperatorCosts(db, 0)
+	if len(costs) != 3 {
+		t.Fatalf("Operator costs mismatch: have %d, want %d", len(costs), 3)
+	}
+	for i, want := range []uint64{100, 200, 300} {
+		if costs[i].Timestamp != want || costs[i].Value.Uint64() != want {
+			t.Fatalf("Operator cost #%d mismatch: have %v, want timestamp %d", i, costs[i], want)
+		}
+	}
+
+	if costs := ReadOperatorCosts(db, 150); len(costs) != 2 || costs[0].Timestamp != 200 {
+		t.Fatalf("Operator costs since 150 mismatch: %v", costs)
+	}
+
+	addr := common.HexToAddress("0x01")
+	if n := ReadCostCursor(db, addr); n != 0 {
+		t.Fatalf("Non existent cost cursor returned: %d", n)
+	}
+	WriteCostCursor(db, addr, 7)
+	if n := ReadCostCursor(db, addr); n != 7 {
+		t.Fatalf("Cost cursor mismatch: have %d, want %d", n, 7)
+	}
+}
@@ -19,8 +19,10 @@ package rawdb
 
 import (
 	"encoding/binary"
+	"math/big"
 
 	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/core/types"
 	"github.com/Onther-Tech/plasma-evm/metrics"
 )
 
@@ -44,6 +46,25 @@ var (
 	// rootchainBlockNumberKey tracks the number of root chain block.
 	rootchainBlockNumberKey = []byte("RootChainBlockNumber")
 
+	// rootchainCursorKey tracks the latest root chain block whose RootChain
+	// contract events are processed.
+	rootchainCursorKey = []byte("RootChainCursor")
+
+	// requestEpochCheckpointKey tracks the progress of the request epoch being
+	// mined by the node.
+	requestEpochCheckpointKey = []byte("RequestEpochCheckpoint")
+
+	// speculativeEpochKey tracks the NRE being mined before it is prepared in
+	// root chain.
+	speculativeEpochKey = []byte("SpeculativeEpoch")
+
+	// finalizerSpentKey tracks the amount of ether spent by the finalizer.
+	finalizerSpentKey = []byte("FinalizerSpent")
+
+	// finalizerCursorKey tracks the number of confirmed transactions of the
+	// finalizer account charged to the finalizer budget.
+	finalizerCursorKey = []byte("FinalizerCursor")
+
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
 	headerTDSuffix     = []byte("t") // headerPrefix + num (uint64 big endian) + hash + headerTDSuffix -> td
@@ -57,6 +78,17 @@ var (
 	invalidExitReceiptsLookupPrefix = []byte("rl") // invalidExitReceiptsLookupPrefix + num (uint64 big endian)+ num (uint64 big endian) -> invalid exit receipt lookup metadata
 	bloomBitsPrefix                 = []byte("B")  // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
 
+	rootchainBlockPrefix   = []byte("rootchain-block-")  // rootchainBlockPrefix + num (uint64 big endian) -> root chain block with processed events
+	epochEnvSnapshotPrefix = []byte("epoch-env-")        // epochEnvSnapshotPrefix + fork (uint64 big endian) + epoch (uint64 big endian) -> epoch environment before the epoch
+	rebaseTxsPrefix        = []byte("rebase-txs-")       // rebaseTxsPrefix + num (uint64 big endian) -> transactions of NRB rolled back by a fork
+	invalidExitPrefix      = []byte("invalid-exit-")     // invalidExitPrefix + fork (uint64 big endian) + num (uint64 big endian) + index (uint64 big endian) -> invalid exit
+	nullAddressTxPrefix    = []byte("null-address-tx-")  // nullAddressTxPrefix + num (uint64 big endian) + index (uint64 big endian) -> null address transaction
+	requestIndexPrefix     = []byte("request-index-")    // requestIndexPrefix + userActivated (1 byte) + request id (uint64 big endian) -> request index
+	requestorIndexPrefix   = []byte("requestor-index-")  // requestorIndexPrefix + requestor + userActivated (1 byte) + request id (uint64 big endian) -> empty
+	submissionFaultPrefix  = []byte("submission-fault-") // submissionFaultPrefix + fork (uint64 big endian) + num (uint64 big endian) -> submission fault
+	operatorCostPrefix     = []byte("operator-cost-")    // operatorCostPrefix + timestamp (uint64 big endian) + hash -> operator cost
+	costCursorPrefix       = []byte("cost-cursor-")      // costCursorPrefix + address -> number of confirmed transactions whose costs are recorded
+
 	// epochEnvKey tracks the lastest known root chain epoch envirionment
 	epochEnvKey = []byte("e")
 
@@ -122,6 +154,219 @@ type InvalidExitReceiptsLookupEntry struct {
 	Indices    []uint64
 }
 
+// InvalidExitStatus represents how far the challenge on an invalid exit has progressed.
+type InvalidExitStatus uint8
+
+const (
+	InvalidExitDetected       InvalidExitStatus = iota // exit receipt is failed, but block is not finalized yet
+	InvalidExitChallengeSent                           // challengeExit transaction is added to transaction manager
+	InvalidExitChallengeMined                          // exit request is marked as challenged in root chain
+	InvalidExitExpired                                 // exit challenge period is over before challenge is mined
+)
+
+func (s InvalidExitStatus) String() string {
+	switch s {
+	case InvalidExitDetected:
+		return "detected"
+	case InvalidExitChallengeSent:
+		return "challenge sent"
+	case InvalidExitChallengeMined:
+		return "challenge mined"
+	case InvalidExitExpired:
+		return "expired"
+	default:
+		return "unknown"
+	}
+}
+
+// InvalidExit is a failed exit request in a request block. It holds the receipt
+// and the merkle proof against the receipts root to challenge the exit request
+// after the block is finalized.
+type InvalidExit struct {
+	ForkNumber  uint64
+	BlockNumber uint64
+	Index       uint64
+	Receipt     *types.Receipt
+	Proof       []common.Hash
+	Status      InvalidExitStatus
+
+	// request of the exit, known after the block is finalized
+	RequestId     uint64
+	UserActivated bool
+}
+
+// NullAddressTx is a transaction from the null address in a non-request block.
+// It holds the encoded transaction and the merkle proof against the
+// transactions root to challenge the block after it is submitted.
+type NullAddressTx struct {
+	BlockNumber uint64
+	Index       uint64
+	TxByte      []byte
+	Proof       []common.Hash
+}
+
+// RequestType is the type of an enter or exit request.
+type RequestType uint8
+
+const (
+	RequestTypeEnter    RequestType = iota // enter request applied to requestable contract
+	RequestTypeExit                        // exit request applied to requestable contract
+	RequestTypeTransfer                    // enter request of ether (PETH) transferred to requestor
+)
+
+func (t RequestType) String() string {
+	switch t {
+	case RequestTypeEnter:
+		return "enter"
+	case RequestTypeExit:
+		return "exit"
+	case RequestTypeTransfer:
+		return "transfer"
+	default:
+		return "unknown"
+	}
+}
+
+// RequestIndex links a request in RootChain contract to the request
+// transaction in a request block (ORB or URB) of child chain.
+type RequestIndex struct {
+	RequestId     uint64
+	UserActivated bool
+	Requestor     common.Address
+	To            common.Address // requestable contract in root chain
+	Type          RequestType
+
+	RequestBlockId uint64
+	ForkNumber     uint64
+	BlockNumber    uint64      // child chain block number of the request block
+	Index          uint64      // index of the request transaction in the block
+	TxHash         common.Hash // hash of the request transaction
+}
+
+// SubmissionFaultKind represents how the blocks submitted to RootChain contract
+// are inconsistent with the blocks received by the node.
+type SubmissionFaultKind uint8
+
+const (
+	SubmissionRootMismatch SubmissionFaultKind = iota // submitted roots are different from local roots
+	SubmissionWithheld                                // submitted blocks are not received during the withholding challenge period
+)
+
+func (k SubmissionFaultKind) String() string {
+	switch k {
+	case SubmissionRootMismatch:
+		return "root mismatch"
+	case SubmissionWithheld:
+		return "withheld"
+	default:
+		return "unknown"
+	}
+}
+
+// SubmissionFault is the evidence of an NRE or a block submitted to RootChain
+// contract which does not match the blocks received by the node. Local roots
+// are empty if the blocks are withheld.
+type SubmissionFault struct {
+	Kind             SubmissionFaultKind
+	ForkNumber       uint64
+	EpochNumber      uint64
+	StartBlockNumber uint64
+	EndBlockNumber   uint64
+	IsRequest        bool
+	SubmittedAt      uint64
+
+	StatesRoot       common.Hash
+	TransactionsRoot common.Hash
+	ReceiptsRoot     common.Hash
+
+	LocalStatesRoot       common.Hash
+	LocalTransactionsRoot common.Hash
+	LocalReceiptsRoot     common.Hash
+
+	DetectedAt uint64
+}
+
+// OperatorCostKind represents why a root chain transaction is sent by the node.
+type OperatorCostKind uint8
+
+const (
+	CostSubmission   OperatorCostKind = iota // submitNRE, submitORB, submitURB and prepareToSubmitURB
+	CostChallenge                            // challengeExit and challengeNullAddress
+	CostFinalization                         // finalizeBlock, finalizeRequest and finalizeRequests
+)
+
+func (k OperatorCostKind) String() string {
+	switch k {
+	case CostSubmission:
+		return "submission"
+	case CostChallenge:
+		return "challenge"
+	case CostFinalization:
+		return "finalization"
+	default:
+		return "unknown"
+	}
+}
+
+// OperatorCost is the cost of a root chain transaction sent by the node. Value
+// is the ether bonded with the transaction and Refund is the ether paid back
+// by RootChain contract, e.g., COST_ERO of a challenged exit. The transaction
+// is attributed to an epoch only if HasEpoch is true.
+type OperatorCost struct {
+	Kind      OperatorCostKind
+	Method    string
+	TxHash    common.Hash
+	From      common.Address
+	Timestamp uint64 // timestamp of the root chain block
+	Reverted  bool
+
+	HasEpoch    bool
+	ForkNumber  uint64
+	EpochNumber uint64
+
+	GasUsed  uint64
+	GasPrice *big.Int
+	Value    *big.Int
+	Refund   *big.Int
+}
+
+// RequestEpochCheckpoint is the progress of the request epoch (ORE, ORE' or
+// URE) being mined by the node. It is used to resume the epoch after restart.
+type RequestEpochCheckpoint struct {
+	ForkNumber       uint64
+	EpochNumber      uint64
+	StartBlockNumber uint64
+	UserActivated    bool
+	Rebase           bool
+
+	RequestBlockIds []uint64 // request block ids of the blocks in the epoch
+	NumMined        uint64   // number of request blocks mined
+	NumSubmitted    uint64   // number of request blocks whose submit transactions are added
+}
+
+// SpeculativeEpoch is the NRE mined by operator before it is prepared in root
+// chain, expecting the request epoch before it to be empty.
+type SpeculativeEpoch struct {
+	ForkNumber       uint64
+	EpochNumber      uint64
+	StartBlockNumber uint64
+	EndBlockNumber   uint64
+}
+
+// RootChainBlock is a root chain block in which RootChain contract events are
+// processed. The first epoch prepared in the block is kept to roll back the
+// epoch if the block is reorganized.
+type RootChainBlock struct {
+	Number uint64
+	Hash   common.Hash
+	Parent uint64 // number of the previous root chain block with processed events
+
+	EpochPrepared    bool
+	ForkNumber       uint64
+	EpochNumber      uint64
+	StartBlockNumber uint64
+}
+
 // encodeBlockNumber encodes a block number as big endian uint64
 func encodeBlockNumber(number uint64) []byte {
 	enc := make([]byte, 8)
@@ -181,6 +426,31 @@ func invalidExitReceiptsLookupKey(fork uint64, num uint64) []byte {
 	return append(append(invalidExitReceiptsLookupPrefix, encodeForkNumber(fork)...), encodeBlockNumber(num)...)
 }
 
+// rootchainBlockKey = rootchainBlockPrefix + num (uint64 big endian)
+func rootchainBlockKey(num uint64) []byte {
+	return append(rootchainBlockPrefix, encodeBlockNumber(num)...)
+}
+
+// epochEnvSnapshotKey = epochEnvSnapshotPrefix + fork (uint64 big endian) + epoch (uint64 big endian)
+func epochEnvSnapshotKey(fork uint64, epoch uint64) []byte {
+	return append(append(epochEnvSnapshotPrefix, encodeForkNumber(fork)...), encodeBlockNumber(epoch)...)
+}
+
+// rebaseTxsKey = rebaseTxsPrefix + num (uint64 big endian)
+func rebaseTxsKey(num uint64) []byte {
+	return append(rebaseTxsPrefix, encodeBlockNumber(num)...)
+}
+
+// invalidExitKey = invalidExitPrefix + fork (uint64 big endian) + num (uint64 big endian) + index (uint64 big endian)
+func invalidExitKey(fork uint64, num uint64, index uint64) []byte {
+	return append(append(append(invalidExitPrefix, encodeForkNumber(fork)...), encodeBlockNumber(num)...), encodeBlockNumber(index)...)
+}
+
+// nullAddressTxKey = nullAddressTxPrefix + num (uint64 big endian) + index (uint64 big endian)
+func nullAddressTxKey(num uint64, index uint64) []byte {
+	return append(append(nullAddressTxPrefix, encodeBlockNumber(num)...), encodeBlockNumber(index)...)
+}
+
 // bloomBitsKey = bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash
 func bloomBitsKey(bit uint, section uint64, hash common.Hash) []byte {
 	key := append(append(bloomBitsPrefix, make([]byte, 10)...), hash.Bytes()...)
@@ -200,3 +470,37 @@ func preimageKey(hash common.Hash) []byte {
 func configKey(hash common.Hash) []byte {
 	return append(configPrefix, hash.Bytes()...)
 }
+
+// requestIndexKey = requestIndexPrefix + userActivated (1 byte) + request id (uint64 big endian)
+func requestIndexKey(userActivated bool, requestId uint64) []byte {
+	return append(append(requestIndexPrefix, userActivatedFlag(userActivated)), encodeBlockNumber(requestId)...)
+}
+
+// requestorIndexKey = requestorIndexPrefix + requestor + userActivated (1 byte) + request id (uint64 big endian)
+func requestorIndexKey(requestor common.Address, userActivated bool, requestId uint64) []byte {
+	return append(append(append(requestorIndexPrefix, requestor.Bytes()...), userActivatedFlag(userActivated)), encodeBlockNumber(requestId)...)
+}
+
+// userActivatedFlag encodes whether the request is user-activated (ERU) as 1
+// byte so that EROs are ordered first.
+func userActivatedFlag(userActivated bool) byte {
+	if userActivated {
+		return 1
+	}
+	return 0
+}
+
+// submissionFaultKey = submissionFaultPrefix + fork (uint64 big endian) + num (uint64 big endian)
+func submissionFaultKey(fork uint64, num uint64) []byte {
+	return append(append(submissionFaultPrefix, encodeForkNumber(fork)...), encodeBlockNumber(num)...)
+}
+
+// operatorCostKey = operatorCostPrefix + timestamp (uint64 big endian) + hash
+func operatorCostKey(timestamp uint64, hash common.Hash) []byte {
+	return append(append(operatorCostPrefix, encodeBlockNumber(timestamp)...), hash.Bytes()...)
+}
+
+// costCursorKey = costCursorPrefix + address
+func costCursorKey(addr common.Address) []byte {
+	return append(costCursorPrefix, addr.Bytes()...)
+}
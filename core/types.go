@@ -34,6 +34,11 @@ type Validator interface {
 	ValidateState(block *types.Block, state *state.StateDB, receipts types.Receipts, usedGas uint64) error
 }
 
+// RequestTxsFn returns the request transactions expected in the request block,
+// rebuilt from the requests in root chain. It returns nil transactions if the
+// requests of the block are not known yet.
+type RequestTxsFn func(block *types.Block) (types.Transactions, error)
+
 // Prefetcher is an interface for pre-caching transaction signatures and state.
 type Prefetcher interface {
 	// Prefetch processes the state changes according to the Ethereum rules by running
@@ -468,6 +468,42 @@ func (self Blocks) ReceiptsRoot() common.Hash {
 	return getBinaryMerkleRoot(h)
 }
 
+// StatesProof returns the merkle proof of the state root of i-th block
+// against StatesRoot.
+func (self Blocks) StatesProof(i int) []common.Hash {
+	h := make([]common.Hash, len(self))
+
+	for j := 0; j < len(self); j++ {
+		h[j] = self[j].header.Root
+	}
+
+	return getMerkleProof(h, i)
+}
+
+// TransactionsProof returns the merkle proof of the transactions root of i-th
+// block against TransactionsRoot.
+func (self Blocks) TransactionsProof(i int) []common.Hash {
+	h := make([]common.Hash, len(self))
+
+	for j := 0; j < len(self); j++ {
+		h[j] = self[j].header.TxHash
+	}
+
+	return getMerkleProof(h, i)
+}
+
+// ReceiptsProof returns the merkle proof of the receipts root of i-th block
+// against ReceiptsRoot.
+func (self Blocks) ReceiptsProof(i int) []common.Hash {
+	h := make([]common.Hash, len(self))
+
+	for j := 0; j < len(self); j++ {
+		h[j] = self[j].header.ReceiptHash
+	}
+
+	return getMerkleProof(h, i)
+}
+
 type BlockBy func(b1, b2 *Block) bool
 
 func (self BlockBy) Sort(blocks Blocks) {
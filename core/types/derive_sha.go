@@ -74,21 +74,28 @@ func getBinaryMerkleRoot(level []common.Hash) common.Hash {
 }
 
 func GetMerkleProof(list DerivableList, index int) []common.Hash {
-	var proof []common.Hash
 	var leafLevel []common.Hash
+
+	// convert value of leaf nodes to hash.
+	for i := 0; i < list.Len(); i++ {
+		leafLevel = append(leafLevel, crypto.Keccak256Hash(list.GetRlp(i)))
+	}
+
+	return getMerkleProof(leafLevel, index)
+}
+
+// getMerkleProof returns the merkle proof of the leaf against the binary merkle
+// root of the leaves (see getBinaryMerkleRoot).
+func getMerkleProof(leafLevel []common.Hash, index int) []common.Hash {
+	var proof []common.Hash
 	var tree [][]common.Hash
 	var depth int
 
 	// If the number of elements is only one, return empty proof.
-	if list.Len() == 1 {
+	if len(leafLevel) == 1 {
 		return proof
 	}
 
-	// convert value of leaf nodes to hash.
-	for i := 0; i < list.Len(); i++ {
-		leafLevel = append(leafLevel, crypto.Keccak256Hash(list.GetRlp(i)))
-	}
-
 	tree = append(tree, leafLevel)
 	createTree := func(level []common.Hash) {
 		var nextLevel = make([]common.Hash, (len(level)+1)/2)
@@ -106,3 +106,41 @@ func TestCheckMembership(t *testing.T) {
 	}
 
 }
+
+func TestBlocksProof(t *testing.T) {
+	var blocks Blocks
+
+	for size := 1; size <= 16; size++ {
+		header := &Header{
+			Number:      big.NewInt(int64(size)),
+			Root:        crypto.Keccak256Hash([]byte("root"), big.NewInt(int64(size)).Bytes()),
+			TxHash:      crypto.Keccak256Hash([]byte("tx"), big.NewInt(int64(size)).Bytes()),
+			ReceiptHash: crypto.Keccak256Hash([]byte("receipt"), big.NewInt(int64(size)).Bytes()),
+		}
+		blocks = append(blocks, NewBlockWithHeader(header))
+
+		for index := 0; index < size; index++ {
+			checkProof(t, blocks[index].Root(), blocks.StatesProof(index), index, blocks.StatesRoot())
+			checkProof(t, blocks[index].TxHash(), blocks.TransactionsProof(index), index, blocks.TransactionsRoot())
+			checkProof(t, blocks[index].ReceiptHash(), blocks.ReceiptsProof(index), index, blocks.ReceiptsRoot())
+		}
+	}
+}
+
+func checkProof(t *testing.T, leaf common.Hash, proof []common.Hash, index int, root common.Hash) {
+	computedHash := leaf.Bytes()
+	nodeIndex := index
+
+	for i := 0; i < len(proof); i++ {
+		if nodeIndex%2 == 0 {
+			computedHash = crypto.Keccak256(computedHash, proof[i].Bytes())
+		} else {
+			computedHash = crypto.Keccak256(proof[i].Bytes(), computedHash)
+		}
+		nodeIndex = nodeIndex / 2
+	}
+
+	if cH := common.BytesToHash(computedHash); cH != root {
+		t.Fatalf("proof of leaf #%d is invalid: computed %s, root %s", index, cH.Hex(), root.Hex())
+	}
+}
@@ -28,13 +28,23 @@ import (
 	"github.com/Onther-Tech/plasma-evm/common"
 	"github.com/Onther-Tech/plasma-evm/common/hexutil"
 	"github.com/Onther-Tech/plasma-evm/core/types"
+	"github.com/Onther-Tech/plasma-evm/ethclient/failover"
 	"github.com/Onther-Tech/plasma-evm/rlp"
 	"github.com/Onther-Tech/plasma-evm/rpc"
 )
 
+// rpcClient is the RPC client used by Client. It is either a single *rpc.Client
+// or a failover.Client over multiple providers.
+type rpcClient interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+	BatchCallContext(ctx context.Context, b []rpc.BatchElem) error
+	EthSubscribe(ctx context.Context, channel interface{}, args ...interface{}) (*rpc.ClientSubscription, error)
+	Close()
+}
+
 // Client defines typed wrappers for the Ethereum RPC API.
 type Client struct {
-	c *rpc.Client
+	c rpcClient
 }
 
 // Dial connects a client to the given URL.
@@ -55,6 +65,16 @@ func NewClient(c *rpc.Client) *Client {
 	return &Client{c}
 }
 
+// DialFailover connects a client to the providers at the given URLs, failing
+// over between them. See failover.Dial.
+func DialFailover(ctx context.Context, rawurls []string, quorum int) (*Client, error) {
+	c, err := failover.Dial(ctx, rawurls, quorum)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{c}, nil
+}
+
 func (ec *Client) Close() {
 	ec.c.Close()
 }
@@ -471,6 +491,33 @@ func (ec *Client) CallContract(ctx context.Context, msg ethereum.CallMsg, blockN
 	return hex, nil
 }
 
+// BatchCallContract executes multiple message calls in a single batch request.
+// The results and errors of the calls are returned in the same order as msgs.
+// The returned error is set only if the batch request itself fails.
+func (ec *Client) BatchCallContract(ctx context.Context, msgs []ethereum.CallMsg, blockNumber *big.Int) ([][]byte, []error, error) {
+	var (
+		results = make([]hexutil.Bytes, len(msgs))
+		reqs    = make([]rpc.BatchElem, len(msgs))
+	)
+	for i, msg := range msgs {
+		reqs[i] = rpc.BatchElem{
+			Method: "eth_call",
+			Args:   []interface{}{toCallArg(msg), toBlockNumArg(blockNumber)},
+			Result: &results[i],
+		}
+	}
+	if err := ec.c.BatchCallContext(ctx, reqs); err != nil {
+		return nil, nil, err
+	}
+
+	outputs := make([][]byte, len(msgs))
+	errs := make([]error, len(msgs))
+	for i := range reqs {
+		outputs[i], errs[i] = results[i], reqs[i].Error
+	}
+	return outputs, errs, nil
+}
+
 // PendingCallContract executes a message call transaction using the EVM.
 // The state seen by the contract call is the pending state.
 func (ec *Client) PendingCallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error) {
@@ -0,0 +1,410 @@
+// Package failover provides an RPC client which fails over between multiple
+// JSONRPC providers.
+package failover
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Onther-Tech/plasma-evm/common/hexutil"
+	"github.com/Onther-Tech/plasma-evm/log"
+	"github.com/Onther-Tech/plasma-evm/rpc"
+)
+
+// healthCheckInterval is the interval to check whether failed providers are
+// recovered.
+const healthCheckInterval = 10 * time.Second
+
+var (
+	ErrNoProvider = errors.New("no available provider")
+	ErrNoQuorum   = errors.New("providers do not agree on the result")
+)
+
+// rpcClient is the RPC client connected to a provider.
+type rpcClient interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+	BatchCallContext(ctx context.Context, b []rpc.BatchElem) error
+	EthSubscribe(ctx context.Context, channel interface{}, args ...interface{}) (*rpc.ClientSubscription, error)
+	Close()
+}
+
+// provider is a JSONRPC endpoint of Client.
+type provider struct {
+	url     string
+	client  rpcClient // nil if the provider is not connected yet
+	healthy bool
+}
+
+// dialProvider connects a client to the provider at the given URL.
+func dialProvider(ctx context.Context, rawurl string) (rpcClient, error) {
+	c, err := rpc.DialContext(ctx, rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Client sends requests and subscriptions to the active provider and
+// fails over to the next healthy provider on connection errors. If quorum is
+// greater than 1, eth_call results must be agreed by quorum providers so that
+// a lying or lagging provider cannot mislead the client.
+type Client struct {
+	providers []*provider
+	active    int
+	quorum    int
+
+	dial func(ctx context.Context, rawurl string) (rpcClient, error)
+
+	quit chan struct{}
+	lock sync.RWMutex
+}
+
+// Dial connects a client to the providers at the given URLs. At least one
+// provider must be connected. Providers are used in the given order.
+func Dial(ctx context.Context, rawurls []string, quorum int) (*Client, error) {
+	if len(rawurls) == 0 {
+		return nil, ErrNoProvider
+	}
+	if quorum > len(rawurls) {
+		return nil, fmt.Errorf("quorum %d is greater than the number of providers %d", quorum, len(rawurls))
+	}
+
+	fc := &Client{
+		providers: make([]*provider, len(rawurls)),
+		active:    -1,
+		quorum:    quorum,
+		dial:      dialProvider,
+		quit:      make(chan struct{}),
+	}
+
+	for i, rawurl := range rawurls {
+		p := &provider{url: rawurl}
+		if c, err := fc.dial(ctx, rawurl); err != nil {
+			log.Warn("Failed to connect provider", "url", rawurl, "err", err)
+		} else {
+			p.client, p.healthy = c, true
+			if fc.active < 0 {
+				fc.active = i
+			}
+		}
+		fc.providers[i] = p
+	}
+
+	if fc.active < 0 {
+		return nil, ErrNoProvider
+	}
+
+	go fc.checkHealth()
+
+	return fc, nil
+}
+
+// isConnectionError returns true if the error is not an error response of
+// the provider, e.g., the provider is down or unreachable.
+func isConnectionError(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	if _, ok := err.(rpc.Error); ok {
+		return false
+	}
+	return true
+}
+
+// activeClient returns the client of the active provider.
+func (fc *Client) activeClient() (int, rpcClient) {
+	fc.lock.RLock()
+	defer fc.lock.RUnlock()
+
+	if fc.active < 0 {
+		return -1, nil
+	}
+	return fc.active, fc.providers[fc.active].client
+}
+
+// failover marks the provider unhealthy and switches to the next healthy
+// provider. It returns false if there is no healthy provider.
+func (fc *Client) failover(index int, err error) bool {
+	fc.lock.Lock()
+	defer fc.lock.Unlock()
+
+	if fc.providers[index].healthy {
+		fc.providers[index].healthy = false
+		log.Warn("Provider doesn't respond", "url", fc.providers[index].url, "err", err)
+	}
+
+	// Short circuit if another request already failed over.
+	if fc.active != index && fc.active >= 0 {
+		return true
+	}
+
+	for i := 1; i <= len(fc.providers); i++ {
+		next := (index + i) % len(fc.providers)
+		if fc.providers[next].healthy {
+			fc.active = next
+			log.Warn("Provider failed over", "from", fc.providers[index].url, "to", fc.providers[next].url)
+			return true
+		}
+	}
+
+	fc.active = -1
+	log.Error("No provider is available")
+	return false
+}
+
+// do calls fn with the active provider until it succeeds or no healthy
+// provider remains.
+func (fc *Client) do(ctx context.Context, fn func(c rpcClient) error) error {
+	for {
+		index, c := fc.activeClient()
+		if c == nil {
+			return ErrNoProvider
+		}
+
+		err := fn(c)
+		if !isConnectionError(ctx, err) {
+			return err
+		}
+
+		if !fc.failover(index, err) {
+			return err
+		}
+	}
+}
+
+func (fc *Client) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if method == "eth_call" && fc.quorum > 1 {
+		return fc.quorumCall(ctx, result, method, args...)
+	}
+	return fc.do(ctx, func(c rpcClient) error {
+		return c.CallContext(ctx, result, method, args...)
+	})
+}
+
+// BatchCallContext sends the batch to the active provider. If quorum is
+// required, eth_call elements are sent one by one to be agreed by providers.
+func (fc *Client) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	if fc.quorum <= 1 {
+		return fc.do(ctx, func(c rpcClient) error {
+			return c.BatchCallContext(ctx, b)
+		})
+	}
+
+	var rest []rpc.BatchElem
+	var indexes []int
+	for i := range b {
+		if b[i].Method == "eth_call" {
+			b[i].Error = fc.quorumCall(ctx, b[i].Result, b[i].Method, b[i].Args...)
+			continue
+		}
+		rest = append(rest, b[i])
+		indexes = append(indexes, i)
+	}
+	if len(rest) == 0 {
+		return nil
+	}
+
+	err := fc.do(ctx, func(c rpcClient) error {
+		return c.BatchCallContext(ctx, rest)
+	})
+	for i, index := range indexes {
+		b[index] = rest[i]
+	}
+	return err
+}
+
+// EthSubscribe subscribes to the active provider. Subscribers should
+// re-subscribe when the subscription fails so that it is moved to the next
+// healthy provider.
+func (fc *Client) EthSubscribe(ctx context.Context, channel interface{}, args ...interface{}) (*rpc.ClientSubscription, error) {
+	var sub *rpc.ClientSubscription
+	err := fc.do(ctx, func(c rpcClient) error {
+		var err error
+		sub, err = c.EthSubscribe(ctx, channel, args...)
+		return err
+	})
+	return sub, err
+}
+
+func (fc *Client) Close() {
+	close(fc.quit)
+
+	fc.lock.Lock()
+	defer fc.lock.Unlock()
+
+	for _, p := range fc.providers {
+		if p.client != nil {
+			p.client.Close()
+		}
+	}
+}
+
+// healthyClients returns the clients of healthy providers.
+func (fc *Client) healthyClients() []rpcClient {
+	fc.lock.RLock()
+	defer fc.lock.RUnlock()
+
+	var clients []rpcClient
+	for _, p := range fc.providers {
+		if p.healthy {
+			clients = append(clients, p.client)
+		}
+	}
+	return clients
+}
+
+// quorumCall sends the call to all healthy providers and returns the result
+// agreed by quorum providers. The latest block is pinned to the highest block
+// known to quorum providers.
+func (fc *Client) quorumCall(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	clients := fc.healthyClients()
+	if len(clients) < fc.quorum {
+		return fmt.Errorf("%v: %d healthy providers, quorum %d", ErrNoQuorum, len(clients), fc.quorum)
+	}
+
+	if n := len(args); n > 0 && args[n-1] == "latest" {
+		number, err := fc.quorumBlockNumber(ctx, clients)
+		if err != nil {
+			return err
+		}
+		args = append(append([]interface{}{}, args[:n-1]...), number)
+	}
+
+	type response struct {
+		raw json.RawMessage
+		err error
+	}
+
+	responses := make([]response, len(clients))
+
+	var wg sync.WaitGroup
+	for i, c := range clients {
+		wg.Add(1)
+		go func(i int, c rpcClient) {
+			defer wg.Done()
+			responses[i].err = c.CallContext(ctx, &responses[i].raw, method, args...)
+		}(i, c)
+	}
+	wg.Wait()
+
+	// count the same results and errors.
+	votes := make(map[string]int)
+	for _, r := range responses {
+		key := string(r.raw)
+		if r.err != nil {
+			if isConnectionError(ctx, r.err) {
+				continue
+			}
+			key = "error: " + r.err.Error()
+		}
+		votes[key]++
+
+		if votes[key] < fc.quorum {
+			continue
+		}
+		if r.err != nil {
+			return r.err
+		}
+		return json.Unmarshal(r.raw, result)
+	}
+
+	return fmt.Errorf("%v: %s (%d different results)", ErrNoQuorum, method, len(votes))
+}
+
+// quorumBlockNumber returns the highest block number known to quorum providers.
+func (fc *Client) quorumBlockNumber(ctx context.Context, clients []rpcClient) (string, error) {
+	var (
+		numbers []uint64
+		lock    sync.Mutex
+		wg      sync.WaitGroup
+	)
+
+	for _, c := range clients {
+		wg.Add(1)
+		go func(c rpcClient) {
+			defer wg.Done()
+
+			var number hexutil.Uint64
+			if err := c.CallContext(ctx, &number, "eth_blockNumber"); err != nil {
+				return
+			}
+
+			lock.Lock()
+			numbers = append(numbers, uint64(number))
+			lock.Unlock()
+		}(c)
+	}
+	wg.Wait()
+
+	if len(numbers) < fc.quorum {
+		return "", fmt.Errorf("%v: %d providers respond block number, quorum %d", ErrNoQuorum, len(numbers), fc.quorum)
+	}
+
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] > numbers[j] })
+	return hexutil.EncodeUint64(numbers[fc.quorum-1]), nil
+}
+
+// checkHealth periodically connects unhealthy providers and marks them healthy
+// if they respond.
+func (fc *Client) checkHealth() {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			fc.checkProviders()
+
+		case <-fc.quit:
+			return
+		}
+	}
+}
+
+// checkProviders connects unhealthy providers and marks them healthy if they
+// respond. The first recovered provider becomes active if there is no active
+// provider.
+func (fc *Client) checkProviders() {
+	fc.lock.RLock()
+	providers := append([]*provider{}, fc.providers...)
+	fc.lock.RUnlock()
+
+	for i, p := range providers {
+		fc.lock.RLock()
+		healthy, c := p.healthy, p.client
+		fc.lock.RUnlock()
+
+		if healthy {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), healthCheckInterval)
+		if c == nil {
+			var err error
+			if c, err = fc.dial(ctx, p.url); err != nil {
+				cancel()
+				continue
+			}
+		}
+
+		var number hexutil.Uint64
+		err := c.CallContext(ctx, &number, "eth_blockNumber")
+		cancel()
+
+		fc.lock.Lock()
+		p.client = c
+		if err == nil {
+			p.healthy = true
+			if fc.active < 0 {
+				fc.active = i
+			}
+			log.Info("Provider is recovered", "url", p.url, "blockNumber", uint64(number))
+		}
+		fc.lock.Unlock()
+	}
+}
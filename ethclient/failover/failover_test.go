@@ -0,0 +1,335 @@
+package failover
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Onther-Tech/plasma-evm/common/hexutil"
+	"github.com/Onther-Tech/plasma-evm/rpc"
+)
+
+var errFakeConnection = errors.New("connection refused")
+
+// fakeError is an error response of the provider.
+type fakeError struct{ msg string }
+
+func (e *fakeError) Error() string  { return e.msg }
+func (e *fakeError) ErrorCode() int { return -32000 }
+
+// fakeRPCClient is a provider answering eth_blockNumber and eth_call with the
+// configured results. Calls fail with a connection error while it is down.
+type fakeRPCClient struct {
+	blockNumber uint64
+	callResult  string // JSON encoded result of eth_call
+	callErr     error
+
+	down     bool
+	calls    []string        // methods called
+	callArgs [][]interface{} // arguments of eth_call
+
+	lock sync.Mutex
+}
+
+func (c *fakeRPCClient) setDown(down bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.down = down
+}
+
+func (c *fakeRPCClient) called(method string) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var n int
+	for _, m := range c.calls {
+		if m == method {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *fakeRPCClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.down {
+		return errFakeConnection
+	}
+	c.calls = append(c.calls, method)
+
+	switch method {
+	case "eth_blockNumber":
+		*result.(*hexutil.Uint64) = hexutil.Uint64(c.blockNumber)
+		return nil
+	case "eth_call":
+		c.callArgs = append(c.callArgs, args)
+		if c.callErr != nil {
+			return c.callErr
+		}
+		return json.Unmarshal([]byte(c.callResult), result)
+	}
+	return &fakeError{"method not found: " + method}
+}
+
+func (c *fakeRPCClient) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	c.lock.Lock()
+	down := c.down
+	c.lock.Unlock()
+
+	if down {
+		return errFakeConnection
+	}
+	for i := range b {
+		b[i].Error = c.CallContext(ctx, b[i].Result, b[i].Method, b[i].Args...)
+	}
+	return nil
+}
+
+func (c *fakeRPCClient) EthSubscribe(ctx context.Context, channel interface{}, args ...interface{}) (*rpc.ClientSubscription, error) {
+	return nil, &fakeError{"subscription is not supported"}
+}
+
+func (c *fakeRPCClient) Close() {}
+
+// newTestFailoverClient creates a failover client over the fake providers.
+// The first healthy provider is active.
+func newTestFailoverClient(quorum int, clients ...*fakeRPCClient) *Client {
+	fc := &Client{
+		active: -1,
+		quorum: quorum,
+		dial: func(ctx context.Context, rawurl string) (rpcClient, error) {
+			return nil, errFakeConnection
+		},
+		quit: make(chan struct{}),
+	}
+	for i, c := range clients {
+		p := &provider{url: fmt.Sprintf("fake://%d", i), client: c, healthy: !c.down}
+		if p.healthy && fc.active < 0 {
+			fc.active = i
+		}
+		fc.providers = append(fc.providers, p)
+	}
+	return fc
+}
+
+func TestFailoverOrder(t *testing.T) {
+	clients := []*fakeRPCClient{{blockNumber: 1}, {blockNumber: 2}, {blockNumber: 3}}
+	fc := newTestFailoverClient(1, clients...)
+
+	blockNumber := func() (uint64, error) {
+		var number hexutil.Uint64
+		err := fc.CallContext(context.Background(), &number, "eth_blockNumber")
+		return uint64(number), err
+	}
+
+	if n, err := blockNumber(); err != nil || n != 1 {
+		t.Fatalf("First provider is not used: have %d %v, want %d", n, err, 1)
+	}
+
+	// error responses do not fail over.
+	var result interface{}
+	if err := fc.CallContext(context.Background(), &result, "eth_unknown"); err == nil {
+		t.Fatalf("Error response is not returned")
+	}
+	if fc.active != 0 {
+		t.Fatalf("Error response fails over to provider %d", fc.active)
+	}
+
+	// providers fail over in order.
+	for i := 0; i < len(clients)-1; i++ {
+		clients[i].setDown(true)
+		if n, err := blockNumber(); err != nil || n != uint64(i+2) {
+			t.Fatalf("Provider %d is not failed over: have %d %v, want %d", i, n, err, i+2)
+		}
+		if fc.providers[i].healthy {
+			t.Fatalf("Failed provider %d is healthy", i)
+		}
+	}
+
+	// last provider fails over to the recovered first provider.
+	clients[0].setDown(false)
+	fc.providers[0].healthy = true
+	clients[2].setDown(true)
+	if n, err := blockNumber(); err != nil || n != 1 {
+		t.Fatalf("Last provider is not failed over to first provider: have %d %v, want %d", n, err, 1)
+	}
+
+	// no provider is available.
+	clients[0].setDown(true)
+	if _, err := blockNumber(); err != errFakeConnection {
+		t.Fatalf("Error mismatch: have %v, want %v", err, errFakeConnection)
+	}
+	if _, err := blockNumber(); err != ErrNoProvider {
+		t.Fatalf("Error mismatch: have %v, want %v", err, ErrNoProvider)
+	}
+}
+
+func TestQuorumCall(t *testing.T) {
+	tests := []struct {
+		results []string // eth_call results, empty if the provider is down
+		errs    []error
+		quorum  int
+		want    string
+		err     error
+	}{
+		{results: []string{`"0x01"`, `"0x01"`, `"0x02"`}, quorum: 2, want: "0x01"},
+		{results: []string{`"0x02"`, `"0x01"`, `"0x01"`}, quorum: 2, want: "0x01"},
+		{results: []string{`"0x01"`, `"0x02"`, `"0x03"`}, quorum: 2, err: ErrNoQuorum},
+		{results: []string{`"0x01"`, `"0x01"`, `"0x02"`}, quorum: 3, err: ErrNoQuorum},
+		{results: []string{``, `"0x01"`, `"0x01"`}, quorum: 2, want: "0x01"},
+		{results: []string{``, ``, `"0x01"`}, quorum: 2, err: ErrNoQuorum},
+		{results: []string{`"0x01"`, `"0x01"`, `"0x01"`}, errs: []error{errFakeConnection, errFakeConnection, nil}, quorum: 2, err: ErrNoQuorum},
+		{results: []string{`"0x01"`, `"0x01"`, `"0x01"`}, errs: []error{&fakeError{"reverted"}, &fakeError{"reverted"}, nil}, quorum: 2, err: &fakeError{"reverted"}},
+	}
+
+	for i, tt := range tests {
+		clients := make([]*fakeRPCClient, len(tt.results))
+		for j, r := range tt.results {
+			clients[j] = &fakeRPCClient{blockNumber: 10, callResult: r, down: r == ""}
+			if tt.errs != nil {
+				clients[j].callErr = tt.errs[j]
+			}
+		}
+		fc := newTestFailoverClient(tt.quorum, clients...)
+
+		var result string
+		err := fc.CallContext(context.Background(), &result, "eth_call", map[string]interface{}{}, "0xa")
+		switch {
+		case tt.err == nil && (err != nil || result != tt.want):
+			t.Errorf("test %d: result mismatch: have %q %v, want %q", i, result, err, tt.want)
+		case tt.err != nil && (err == nil || !strings.HasPrefix(err.Error(), tt.err.Error())):
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+}
+
+func TestQuorumCallLatestBlock(t *testing.T) {
+	clients := []*fakeRPCClient{
+		{blockNumber: 12, callResult: `"0x01"`},
+		{blockNumber: 10, callResult: `"0x01"`},
+		{blockNumber: 11, callResult: `"0x01"`},
+	}
+	fc := newTestFailoverClient(2, clients...)
+
+	var result string
+	if err := fc.CallContext(context.Background(), &result, "eth_call", map[string]interface{}{}, "latest"); err != nil {
+		t.Fatalf("Failed to call: %v", err)
+	}
+
+	// latest block is pinned to the highest block known to quorum providers.
+	for i, c := range clients {
+		if len(c.callArgs) != 1 {
+			t.Fatalf("Provider %d is not called", i)
+		}
+		if have, want := c.callArgs[0][1], hexutil.EncodeUint64(11); have != want {
+			t.Errorf("Provider %d block number mismatch: have %v, want %v", i, have, want)
+		}
+	}
+}
+
+func TestBatchCallContext(t *testing.T) {
+	clients := []*fakeRPCClient{
+		{blockNumber: 10, callResult: `"0x01"`},
+		{blockNumber: 11, callResult: `"0x02"`},
+		{blockNumber: 12, callResult: `"0x01"`},
+	}
+	fc := newTestFailoverClient(2, clients...)
+	clients[0].setDown(true)
+	fc.providers[0].healthy = false
+	fc.active = 1
+
+	var (
+		result string
+		number hexutil.Uint64
+	)
+	batch := []rpc.BatchElem{
+		{Method: "eth_call", Args: []interface{}{map[string]interface{}{}, "0xa"}, Result: &result},
+		{Method: "eth_blockNumber", Result: &number},
+	}
+	if err := fc.BatchCallContext(context.Background(), batch); err != nil {
+		t.Fatalf("Failed to send batch: %v", err)
+	}
+
+	if batch[0].Error == nil || !strings.HasPrefix(batch[0].Error.Error(), ErrNoQuorum.Error()) {
+		t.Errorf("eth_call error mismatch: have %v, want %v", batch[0].Error, ErrNoQuorum)
+	}
+	if batch[1].Error != nil || uint64(number) != 11 {
+		t.Errorf("eth_blockNumber result mismatch: have %d %v, want %d", number, batch[1].Error, 11)
+	}
+	if clients[2].called("eth_blockNumber") != 0 {
+		t.Errorf("Non eth_call element is sent to inactive provider")
+	}
+
+	// eth_call is agreed once the provider is recovered.
+	clients[0].setDown(false)
+	fc.providers[0].healthy = true
+
+	batch[0].Error, batch[1].Error = nil, nil
+	if err := fc.BatchCallContext(context.Background(), batch); err != nil {
+		t.Fatalf("Failed to send batch: %v", err)
+	}
+	if batch[0].Error != nil || result != "0x01" {
+		t.Errorf("eth_call result mismatch: have %q %v, want %q", result, batch[0].Error, "0x01")
+	}
+}
+
+func TestCheckProviders(t *testing.T) {
+	clients := []*fakeRPCClient{{blockNumber: 1, down: true}, {blockNumber: 2, down: true}}
+	fc := newTestFailoverClient(1, clients...)
+
+	// third provider is not connected yet.
+	dialed := &fakeRPCClient{blockNumber: 3}
+	fc.providers = append(fc.providers, &provider{url: "fake://2"})
+
+	if fc.active != -1 {
+		t.Fatalf("Active provider mismatch: have %d, want %d", fc.active, -1)
+	}
+
+	// failed providers are not recovered.
+	fc.checkProviders()
+	for i, p := range fc.providers {
+		if p.healthy {
+			t.Fatalf("Failed provider %d is recovered", i)
+		}
+	}
+
+	// the second provider is recovered and becomes active.
+	clients[1].setDown(false)
+	fc.checkProviders()
+	if !fc.providers[1].healthy || fc.providers[0].healthy {
+		t.Fatalf("Provider health mismatch: have %v %v, want %v %v", fc.providers[0].healthy, fc.providers[1].healthy, false, true)
+	}
+	if fc.active != 1 {
+		t.Fatalf("Active provider mismatch: have %d, want %d", fc.active, 1)
+	}
+
+	// the unconnected provider is dialed and recovered, but the active
+	// provider is not changed.
+	fc.dial = func(ctx context.Context, rawurl string) (rpcClient, error) {
+		if rawurl != "fake://2" {
+			return nil, errFakeConnection
+		}
+		return dialed, nil
+	}
+	fc.checkProviders()
+	if !fc.providers[2].healthy || fc.providers[2].client != rpcClient(dialed) {
+		t.Fatalf("Unconnected provider is not recovered")
+	}
+	if fc.active != 1 {
+		t.Fatalf("Active provider mismatch: have %d, want %d", fc.active, 1)
+	}
+
+	// the recovered provider is used when the active provider fails.
+	clients[1].setDown(true)
+	var number hexutil.Uint64
+	if err := fc.CallContext(context.Background(), &number, "eth_blockNumber"); err != nil || number != 3 {
+		t.Fatalf("Recovered provider is not used: have %d %v, want %d", number, err, 3)
+	}
+}
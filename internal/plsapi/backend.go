@@ -81,8 +81,19 @@ type Backend interface {
 
 	ChainConfig() *params.ChainConfig
 	CurrentBlock() *types.Block
+
+	// RootChain API
+	BlockStatus(number uint64) (string, error)
 }
 
+// Block status in RootChain contract
+const (
+	BlockStatusPending   = "pending"   // block is not mined yet
+	BlockStatusMined     = "mined"     // block is mined but not submitted to RootChain contract
+	BlockStatusSubmitted = "submitted" // block is submitted to RootChain contract
+	BlockStatusFinalized = "finalized" // block is finalized in RootChain contract
+)
+
 func GetAPIs(apiBackend Backend) []rpc.API {
 	nonceLock := new(AddrLocker)
 	return []rpc.API{
@@ -33,6 +33,8 @@ var Modules = map[string]string{
 	"swarmfs":    SwarmfsJs,
 	"txpool":     TxpoolJs,
 	"les":        LESJs,
+	"pls":        PlsJs,
+	"txmanager":  TxManagerJs,
 }
 
 const ChequebookJs = `
@@ -856,3 +858,155 @@ web3._extend({
 	]
 });
 `
+
+const PlsJs = `
+web3._extend({
+	property: 'pls',
+	methods: [
+		new web3._extend.Method({
+			name: 'getEpoch',
+			call: 'pls_getEpoch',
+			params: 2,
+			inputFormatter: [web3._extend.utils.fromDecimal, web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'getBlock',
+			call: 'pls_getBlock',
+			params: 2,
+			inputFormatter: [web3._extend.utils.fromDecimal, web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'getTransactionProof',
+			call: 'pls_getTransactionProof',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getReceiptProof',
+			call: 'pls_getReceiptProof',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getRequest',
+			call: 'pls_getRequest',
+			params: 2,
+			inputFormatter: [web3._extend.utils.fromDecimal, null]
+		}),
+		new web3._extend.Method({
+			name: 'getRequestsByRequestor',
+			call: 'pls_getRequestsByRequestor',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'submissionFaults',
+			call: 'pls_submissionFaults'
+		}),
+		new web3._extend.Method({
+			name: 'operatorCostsByEpoch',
+			call: 'pls_operatorCostsByEpoch',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'operatorCostsByDay',
+			call: 'pls_operatorCostsByDay',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+	],
+	properties:
+	[
+		new web3._extend.Property({
+			name: 'rootChainContract',
+			getter: 'pls_rootChainContract'
+		}),
+		new web3._extend.Property({
+			name: 'currentFork',
+			getter: 'pls_currentFork',
+			outputFormatter: web3._extend.utils.toDecimal
+		}),
+		new web3._extend.Property({
+			name: 'lastEpoch',
+			getter: 'pls_lastEpoch'
+		}),
+		new web3._extend.Property({
+			name: 'lastFinalizedBlock',
+			getter: 'pls_lastFinalizedBlock',
+			outputFormatter: web3._extend.utils.toDecimal
+		}),
+		new web3._extend.Property({
+			name: 'pendingRequests',
+			getter: 'pls_pendingRequests'
+		}),
+		new web3._extend.Property({
+			name: 'operatorStatus',
+			getter: 'pls_operatorStatus'
+		}),
+	]
+});
+`
+
+const TxManagerJs = `
+web3._extend({
+	property: 'txmanager',
+	methods: [
+		new web3._extend.Method({
+			name: 'rawTransactions',
+			call: 'txmanager_rawTransactions',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'resend',
+			call: 'txmanager_resend',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.utils.fromDecimal, web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'cancel',
+			call: 'txmanager_cancel',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'cancelStuck',
+			call: 'txmanager_cancelStuck',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'reconcileNonce',
+			call: 'txmanager_reconcileNonce',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'setMinGasPrice',
+			call: 'txmanager_setMinGasPrice',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'setMaxGasPrice',
+			call: 'txmanager_setMaxGasPrice',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'setInterval',
+			call: 'txmanager_setInterval',
+			params: 1
+		}),
+	],
+	properties:
+	[
+		new web3._extend.Property({
+			name: 'config',
+			getter: 'txmanager_config'
+		}),
+		new web3._extend.Property({
+			name: 'accounts',
+			getter: 'txmanager_accounts'
+		}),
+	]
+});
+`
@@ -32,6 +32,7 @@ import (
 	"github.com/Onther-Tech/plasma-evm/core/vm"
 	"github.com/Onther-Tech/plasma-evm/ethdb"
 	"github.com/Onther-Tech/plasma-evm/event"
+	"github.com/Onther-Tech/plasma-evm/internal/plsapi"
 	"github.com/Onther-Tech/plasma-evm/params"
 	"github.com/Onther-Tech/plasma-evm/pls/downloader"
 	"github.com/Onther-Tech/plasma-evm/pls/gasprice"
@@ -69,6 +70,11 @@ func (b *PlsAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumb
 		block := b.pls.miner.PendingBlock()
 		return block.Header(), nil
 	}
+	// Submitted and finalized blocks are known by RootChain contract
+	number, err := b.resolveBlockNumber(number)
+	if err != nil {
+		return nil, err
+	}
 	// Otherwise resolve and return the block
 	if number == rpc.LatestBlockNumber {
 		return b.pls.blockchain.CurrentBlock().Header(), nil
@@ -103,6 +109,11 @@ func (b *PlsAPIBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumbe
 		block := b.pls.miner.PendingBlock()
 		return block, nil
 	}
+	// Submitted and finalized blocks are known by RootChain contract
+	number, err := b.resolveBlockNumber(number)
+	if err != nil {
+		return nil, err
+	}
 	// Otherwise resolve and return the block
 	if number == rpc.LatestBlockNumber {
 		return b.pls.blockchain.CurrentBlock(), nil
@@ -174,6 +185,59 @@ func (b *PlsAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 	return nil, nil, errors.New("invalid arguments; neither block nor hash specified")
 }
 
+// resolveBlockNumber converts submitted and finalized block tags into the block
+// number read from RootChain contract. Other block numbers are returned as is.
+func (b *PlsAPIBackend) resolveBlockNumber(number rpc.BlockNumber) (rpc.BlockNumber, error) {
+	var (
+		n   uint64
+		err error
+	)
+
+	switch number {
+	case rpc.SubmittedBlockNumber:
+		n, err = b.pls.rootchainManager.lastSubmittedBlock()
+	case rpc.FinalizedBlockNumber:
+		n, err = b.pls.rootchainManager.lastFinalizedBlock()
+	default:
+		return number, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// blocks before the local head are also submitted (or finalized) if the
+	// plasma chain is not synced yet.
+	if head := b.pls.blockchain.CurrentBlock().NumberU64(); n > head {
+		n = head
+	}
+	return rpc.BlockNumber(n), nil
+}
+
+// BlockStatus returns whether the block is only mined, submitted to or
+// finalized in RootChain contract.
+func (b *PlsAPIBackend) BlockStatus(number uint64) (string, error) {
+	if number > b.pls.blockchain.CurrentBlock().NumberU64() {
+		return plsapi.BlockStatusPending, nil
+	}
+
+	finalized, err := b.pls.rootchainManager.lastFinalizedBlock()
+	if err != nil {
+		return "", err
+	}
+	if number <= finalized {
+		return plsapi.BlockStatusFinalized, nil
+	}
+
+	submitted, err := b.pls.rootchainManager.lastSubmittedBlock()
+	if err != nil {
+		return "", err
+	}
+	if number <= submitted {
+		return plsapi.BlockStatusSubmitted, nil
+	}
+	return plsapi.BlockStatusMined, nil
+}
+
 func (b *PlsAPIBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
 	return b.pls.blockchain.GetReceiptsByHash(hash), nil
 }
@@ -0,0 +1,578 @@
+package pls
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Onther-Tech/plasma-evm/accounts"
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/common/hexutil"
+	"github.com/Onther-Tech/plasma-evm/contracts/plasma/rootchain"
+	"github.com/Onther-Tech/plasma-evm/core/rawdb"
+	"github.com/Onther-Tech/plasma-evm/core/types"
+	"github.com/Onther-Tech/plasma-evm/rpc"
+)
+
+// PublicRootChainAPI provides an API to access RootChain contract state which
+// the plasma chain is based on.
+type PublicRootChainAPI struct {
+	rcm *RootChainManager
+}
+
+// NewPublicRootChainAPI creates a new RootChain API.
+func NewPublicRootChainAPI(rcm *RootChainManager) *PublicRootChainAPI {
+	return &PublicRootChainAPI{rcm}
+}
+
+// APIs returns the collection of RPC services root chain manager offers.
+func (rcm *RootChainManager) APIs() []rpc.API {
+	return append([]rpc.API{
+		{
+			Namespace: "pls",
+			Version:   "1.0",
+			Service:   NewPublicRootChainAPI(rcm),
+			Public:    true,
+		},
+	}, rcm.txManager.APIs()...)
+}
+
+// RootChainContract returns the address of RootChain contract.
+func (api *PublicRootChainAPI) RootChainContract() common.Address {
+	return api.rcm.config.RootChainContract
+}
+
+// CurrentFork returns the current fork number.
+func (api *PublicRootChainAPI) CurrentFork() (hexutil.Uint64, error) {
+	currentFork, err := api.rcm.rootchainContract.CurrentFork(baseCallOpt)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(currentFork.Uint64()), nil
+}
+
+// LastEpoch returns the last epoch of the current fork.
+func (api *PublicRootChainAPI) LastEpoch() (map[string]interface{}, error) {
+	currentFork, err := api.rcm.rootchainContract.CurrentFork(baseCallOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	lastEpoch, err := api.rcm.rootchainContract.LastEpoch(baseCallOpt, currentFork)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.GetEpoch(hexutil.Uint64(currentFork.Uint64()), hexutil.Uint64(lastEpoch.Uint64()))
+}
+
+// GetEpoch returns the epoch of the fork.
+func (api *PublicRootChainAPI) GetEpoch(forkNumber, epochNumber hexutil.Uint64) (map[string]interface{}, error) {
+	epoch, err := api.rcm.getEpoch(new(big.Int).SetUint64(uint64(forkNumber)), new(big.Int).SetUint64(uint64(epochNumber)))
+	if err != nil {
+		return nil, err
+	}
+
+	return RPCMarshalEpoch(uint64(forkNumber), uint64(epochNumber), epoch), nil
+}
+
+// GetBlock returns the plasma block of the fork submitted to RootChain contract.
+func (api *PublicRootChainAPI) GetBlock(forkNumber, blockNumber hexutil.Uint64) (map[string]interface{}, error) {
+	block, err := api.rcm.getBlock(new(big.Int).SetUint64(uint64(forkNumber)), new(big.Int).SetUint64(uint64(blockNumber)))
+	if err != nil {
+		return nil, err
+	}
+
+	return RPCMarshalPlasmaBlock(uint64(forkNumber), uint64(blockNumber), block), nil
+}
+
+// LastFinalizedBlock returns the last finalized block number of the current fork.
+func (api *PublicRootChainAPI) LastFinalizedBlock() (hexutil.Uint64, error) {
+	currentFork, err := api.rcm.rootchainContract.CurrentFork(baseCallOpt)
+	if err != nil {
+		return 0, err
+	}
+
+	lastFinalizedBlock, err := api.rcm.rootchainContract.GetLastFinalizedBlock(baseCallOpt, currentFork)
+	if err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(lastFinalizedBlock.Uint64()), nil
+}
+
+// PendingRequests returns the number of enter and exit requests (ERO) which
+// are not included in ORBs or not finalized yet.
+func (api *PublicRootChainAPI) PendingRequests() (map[string]interface{}, error) {
+	rcm := api.rcm
+
+	numEROs, err := rcm.rootchainContract.GetNumEROs(baseCallOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	numORBs, err := rcm.rootchainContract.GetNumORBs(baseCallOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	eroIdToFinalize, err := rcm.rootchainContract.EROIdToFinalize(baseCallOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	// requests are appended to the last ORB until it is sealed for the next
+	// ORE, so requests after the last sealed ORB are not applied yet.
+	var numApplied uint64
+	for i := numORBs.Int64() - 1; i >= 0; i-- {
+		orb, err := rcm.rootchainContract.ORBs(baseCallOpt, big.NewInt(i))
+		if err != nil {
+			return nil, err
+		}
+		if orb.Submitted {
+			numApplied = orb.RequestEnd + 1
+			break
+		}
+	}
+
+	var numUnapplied uint64
+	if n := numEROs.Uint64(); n > numApplied {
+		numUnapplied = n - numApplied
+	}
+
+	var numUnfinalized uint64
+	if n := numEROs.Uint64(); n > eroIdToFinalize.Uint64() {
+		numUnfinalized = n - eroIdToFinalize.Uint64()
+	}
+
+	return map[string]interface{}{
+		"numEROs":         hexutil.Uint64(numEROs.Uint64()),
+		"numORBs":         hexutil.Uint64(numORBs.Uint64()),
+		"eroIdToFinalize": hexutil.Uint64(eroIdToFinalize.Uint64()),
+		"unapplied":       hexutil.Uint64(numUnapplied),
+		"unfinalized":     hexutil.Uint64(numUnfinalized),
+	}, nil
+}
+
+// OperatorStatus returns the operator of RootChain contract and the status of
+// this node as a plasma operator.
+func (api *PublicRootChainAPI) OperatorStatus() (map[string]interface{}, error) {
+	rcm := api.rcm
+
+	operator, err := rcm.rootchainContract.Operator(baseCallOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := rcm.backend.BalanceAt(context.Background(), operator, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{
+		"operator":        operator,
+		"balance":         (*hexutil.Big)(balance),
+		"isOperator":      rcm.config.NodeMode == ModeOperator && rcm.config.Operator.Address == operator,
+		"mining":          rcm.miner.Mining(),
+		"rootchainCursor": hexutil.Uint64(rcm.rootchainCursor().Number),
+	}
+
+	rcm.minerEnv.Lock()
+	fields["currentFork"] = (*hexutil.Big)(new(big.Int).Set(rcm.minerEnv.CurrentFork))
+	fields["epochNumber"] = (*hexutil.Big)(new(big.Int).Set(rcm.minerEnv.EpochNumber))
+	fields["epochIsRequest"] = rcm.minerEnv.IsRequest
+	fields["epochCompleted"] = rcm.minerEnv.Completed
+	fields["numBlockMined"] = (*hexutil.Big)(new(big.Int).Set(rcm.minerEnv.NumBlockMined))
+	rcm.minerEnv.Unlock()
+
+	var pendingSubmissions int
+	submitters := rcm.submitterAddresses()
+	for _, addr := range submitters {
+		pendingSubmissions += rcm.txManager.NumPending(accounts.Account{Address: addr})
+	}
+	fields["submitters"] = submitters
+	fields["pendingSubmissions"] = hexutil.Uint64(pendingSubmissions)
+
+	if rcm.config.OperatorMinEther != nil {
+		fields["minEther"] = (*hexutil.Big)(rcm.config.OperatorMinEther)
+	}
+
+	if rcm.config.NodeMode == ModeOperator {
+		if dailyCost, _, days, err := rcm.costTracker.runway(); err == nil {
+			fields["dailyCost"] = (*hexutil.Big)(dailyCost)
+			if days >= 0 {
+				fields["runwayDays"] = days
+			}
+		}
+	}
+
+	return fields, nil
+}
+
+// RPCMarshalEpoch converts the epoch of RootChain contract into a JSON-RPC
+// compatible format.
+func RPCMarshalEpoch(forkNumber, epochNumber uint64, epoch rootchain.DataEpoch) map[string]interface{} {
+	fields := map[string]interface{}{
+		"forkNumber":       hexutil.Uint64(forkNumber),
+		"epochNumber":      hexutil.Uint64(epochNumber),
+		"startBlockNumber": hexutil.Uint64(epoch.StartBlockNumber),
+		"endBlockNumber":   hexutil.Uint64(epoch.EndBlockNumber),
+		"timestamp":        hexutil.Uint64(epoch.Timestamp),
+		"isEmpty":          epoch.IsEmpty,
+		"initialized":      epoch.Initialized,
+		"isRequest":        epoch.IsRequest,
+		"userActivated":    epoch.UserActivated,
+		"rebase":           epoch.Rebase,
+	}
+
+	if epoch.IsRequest {
+		fields["requestStart"] = hexutil.Uint64(epoch.RE.RequestStart)
+		fields["requestEnd"] = hexutil.Uint64(epoch.RE.RequestEnd)
+		fields["firstRequestBlockId"] = hexutil.Uint64(epoch.RE.FirstRequestBlockId)
+		fields["numEnter"] = hexutil.Uint64(epoch.RE.NumEnter)
+	} else {
+		fields["epochStateRoot"] = common.Hash(epoch.NRE.EpochStateRoot)
+		fields["epochTransactionsRoot"] = common.Hash(epoch.NRE.EpochTransactionsRoot)
+		fields["epochReceiptsRoot"] = common.Hash(epoch.NRE.EpochReceiptsRoot)
+		fields["submittedAt"] = hexutil.Uint64(epoch.NRE.SubmittedAt)
+		fields["finalizedAt"] = hexutil.Uint64(epoch.NRE.FinalizedAt)
+		fields["finalized"] = epoch.NRE.Finalized
+		fields["challenging"] = epoch.NRE.Challenging
+		fields["challenged"] = epoch.NRE.Challenged
+	}
+
+	return fields
+}
+
+// RPCMarshalPlasmaBlock converts the plasma block of RootChain contract into a
+// JSON-RPC compatible format.
+func RPCMarshalPlasmaBlock(forkNumber, blockNumber uint64, block rootchain.DataPlasmaBlock) map[string]interface{} {
+	return map[string]interface{}{
+		"forkNumber":       hexutil.Uint64(forkNumber),
+		"blockNumber":      hexutil.Uint64(blockNumber),
+		"epochNumber":      hexutil.Uint64(block.EpochNumber),
+		"requestBlockId":   hexutil.Uint64(block.RequestBlockId),
+		"timestamp":        hexutil.Uint64(block.Timestamp),
+		"finalizedAt":      hexutil.Uint64(block.FinalizedAt),
+		"referenceBlock":   hexutil.Uint64(block.ReferenceBlock),
+		"statesRoot":       common.Hash(block.StatesRoot),
+		"transactionsRoot": common.Hash(block.TransactionsRoot),
+		"receiptsRoot":     common.Hash(block.ReceiptsRoot),
+		"isRequest":        block.IsRequest,
+		"userActivated":    block.UserActivated,
+		"challenged":       block.Challenged,
+		"challenging":      block.Challenging,
+		"finalized":        block.Finalized,
+	}
+}
+
+// GetTransactionProof returns the RLP encoded transaction and its merkle proof
+// against the transactions root of the block. If the block is in NRE, the proof
+// of the block against the epoch roots is returned together.
+func (api *PublicRootChainAPI) GetTransactionProof(hash common.Hash) (map[string]interface{}, error) {
+	rcm := api.rcm
+
+	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(rcm.db, hash)
+	if tx == nil {
+		return nil, nil
+	}
+
+	block := rcm.blockchain.GetBlock(blockHash, blockNumber)
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", blockNumber)
+	}
+	txs := block.Transactions()
+
+	fields := map[string]interface{}{
+		"blockHash":   blockHash,
+		"blockNumber": hexutil.Uint64(blockNumber),
+		"index":       hexutil.Uint64(index),
+		"data":        hexutil.Bytes(txs.GetRlp(int(index))),
+		"root":        block.TxHash(),
+		"proof":       types.GetMerkleProof(txs, int(index)),
+	}
+
+	if err := api.addEpochProof(fields, block); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// GetReceiptProof returns the RLP encoded receipt of the transaction and its
+// merkle proof against the receipts root of the block. If the block is in NRE,
+// the proof of the block against the epoch roots is returned together.
+func (api *PublicRootChainAPI) GetReceiptProof(hash common.Hash) (map[string]interface{}, error) {
+	rcm := api.rcm
+
+	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(rcm.db, hash)
+	if tx == nil {
+		return nil, nil
+	}
+
+	block := rcm.blockchain.GetBlock(blockHash, blockNumber)
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", blockNumber)
+	}
+
+	receipts := rcm.blockchain.GetReceiptsByHash(blockHash)
+	if len(receipts) <= int(index) {
+		return nil, nil
+	}
+
+	fields := map[string]interface{}{
+		"blockHash":   blockHash,
+		"blockNumber": hexutil.Uint64(blockNumber),
+		"index":       hexutil.Uint64(index),
+		"data":        hexutil.Bytes(receipts.GetRlp(int(index))),
+		"root":        block.ReceiptHash(),
+		"proof":       types.GetMerkleProof(receipts, int(index)),
+	}
+
+	if err := api.addEpochProof(fields, block); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// addEpochProof adds the merkle proofs of the block against the epoch roots
+// submitted by submitNRE. Nothing is added if the block is a request block or
+// the epoch is not submitted yet. An error is returned if the roots of the
+// local blocks are different from the submitted roots.
+func (api *PublicRootChainAPI) addEpochProof(fields map[string]interface{}, block *types.Block) error {
+	rcm := api.rcm
+
+	if block.IsRequest() {
+		return nil
+	}
+
+	currentFork, err := rcm.rootchainContract.CurrentFork(baseCallOpt)
+	if err != nil {
+		return err
+	}
+
+	pb, err := rcm.getBlock(currentFork, block.Number())
+	if err != nil {
+		return err
+	}
+
+	// Short circuit if the block is not submitted.
+	if pb.Timestamp == 0 {
+		return nil
+	}
+
+	epoch, err := rcm.getEpoch(currentFork, new(big.Int).SetUint64(pb.EpochNumber))
+	if err != nil {
+		return err
+	}
+
+	if epoch.IsRequest || epoch.NRE.SubmittedAt == 0 {
+		return nil
+	}
+
+	blocks := make(types.Blocks, 0, epoch.EndBlockNumber-epoch.StartBlockNumber+1)
+	for n := epoch.StartBlockNumber; n <= epoch.EndBlockNumber; n++ {
+		b := rcm.blockchain.GetBlockByNumber(n)
+		if b == nil {
+			return fmt.Errorf("block #%d in epoch #%d not found", n, pb.EpochNumber)
+		}
+		blocks = append(blocks, b)
+	}
+	i := int(block.NumberU64() - epoch.StartBlockNumber)
+
+	statesRoot, transactionsRoot, receiptsRoot := blocks.StatesRoot(), blocks.TransactionsRoot(), blocks.ReceiptsRoot()
+	if statesRoot != epoch.NRE.EpochStateRoot ||
+		transactionsRoot != epoch.NRE.EpochTransactionsRoot ||
+		receiptsRoot != epoch.NRE.EpochReceiptsRoot {
+		return fmt.Errorf("epoch #%d roots mismatch: local (%s, %s, %s), submitted (%s, %s, %s)", pb.EpochNumber,
+			statesRoot.Hex(), transactionsRoot.Hex(), receiptsRoot.Hex(),
+			common.Hash(epoch.NRE.EpochStateRoot).Hex(), common.Hash(epoch.NRE.EpochTransactionsRoot).Hex(), common.Hash(epoch.NRE.EpochReceiptsRoot).Hex())
+	}
+
+	fields["epoch"] = map[string]interface{}{
+		"forkNumber":            (*hexutil.Big)(currentFork),
+		"epochNumber":           hexutil.Uint64(pb.EpochNumber),
+		"startBlockNumber":      hexutil.Uint64(epoch.StartBlockNumber),
+		"endBlockNumber":        hexutil.Uint64(epoch.EndBlockNumber),
+		"index":                 hexutil.Uint64(i),
+		"epochStateRoot":        statesRoot,
+		"epochTransactionsRoot": transactionsRoot,
+		"epochReceiptsRoot":     receiptsRoot,
+		"statesProof":           blocks.StatesProof(i),
+		"transactionsProof":     blocks.TransactionsProof(i),
+		"receiptsProof":         blocks.ReceiptsProof(i),
+	}
+	return nil
+}
+
+// GetRequest returns the lifecycle of the enter or exit request: the request
+// in RootChain contract, the request transaction applied in child chain and
+// whether the request is finalized. If userActivated is true, the request is
+// an escape request (ERU).
+func (api *PublicRootChainAPI) GetRequest(requestId hexutil.Uint64, userActivated bool) (map[string]interface{}, error) {
+	rcm := api.rcm
+
+	getRequest := rcm.rootchainContract.EROs
+	if userActivated {
+		getRequest = rcm.rootchainContract.ERUs
+	}
+
+	id := new(big.Int).SetUint64(uint64(requestId))
+
+	request, err := getRequest(baseCallOpt, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Short circuit if the request does not exist.
+	if request.Timestamp == 0 {
+		return nil, nil
+	}
+
+	requestType := rawdb.RequestTypeEnter
+	if request.IsExit {
+		requestType = rawdb.RequestTypeExit
+	} else if request.IsTransfer {
+		requestType = rawdb.RequestTypeTransfer
+	}
+
+	finalized, err := rcm.rootchainContract.GetRequestFinalized(baseCallOpt, id, userActivated)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{
+		"requestId":     requestId,
+		"userActivated": userActivated,
+		"type":          requestType.String(),
+		"requestor":     request.Requestor,
+		"to":            request.To,
+		"trieKey":       common.Hash(request.TrieKey),
+		"trieValue":     hexutil.Bytes(request.TrieValue),
+		"timestamp":     hexutil.Uint64(request.Timestamp),
+		"challenged":    request.Challenged,
+		"finalized":     finalized,
+		"applied":       false,
+	}
+
+	ri := rawdb.ReadRequestIndex(rcm.db, userActivated, uint64(requestId))
+	if ri == nil {
+		return fields, nil
+	}
+
+	fields["requestBlockId"] = hexutil.Uint64(ri.RequestBlockId)
+	fields["forkNumber"] = hexutil.Uint64(ri.ForkNumber)
+	fields["blockNumber"] = hexutil.Uint64(ri.BlockNumber)
+	fields["transactionIndex"] = hexutil.Uint64(ri.Index)
+	fields["transactionHash"] = ri.TxHash
+
+	// the request block may not be mined yet or be rolled back.
+	block := rcm.blockchain.GetBlockByNumber(ri.BlockNumber)
+	if block == nil || !block.IsRequest() || uint64(block.Transactions().Len()) <= ri.Index || block.Transactions()[ri.Index].Hash() != ri.TxHash {
+		return fields, nil
+	}
+
+	fields["applied"] = true
+	fields["blockHash"] = block.Hash()
+
+	if receipts := rcm.blockchain.GetReceiptsByHash(block.Hash()); uint64(len(receipts)) > ri.Index {
+		fields["status"] = hexutil.Uint(receipts[ri.Index].Status)
+	}
+
+	return fields, nil
+}
+
+// GetRequestsByRequestor returns the lifecycles of the requests of the
+// requestor which are applied or to be applied in child chain.
+func (api *PublicRootChainAPI) GetRequestsByRequestor(requestor common.Address) ([]map[string]interface{}, error) {
+	ris := rawdb.ReadRequestIndexesByRequestor(api.rcm.db, requestor)
+
+	requests := make([]map[string]interface{}, 0, len(ris))
+	for _, ri := range ris {
+		fields, err := api.GetRequest(hexutil.Uint64(ri.RequestId), ri.UserActivated)
+		if err != nil {
+			return nil, err
+		}
+		if fields != nil {
+			requests = append(requests, fields)
+		}
+	}
+	return requests, nil
+}
+
+// SubmissionFaults returns the NREs and blocks submitted to RootChain contract
+// whose roots are different from the local blocks or whose blocks are withheld.
+// Submission faults are detected only in challenger mode.
+func (api *PublicRootChainAPI) SubmissionFaults() []map[string]interface{} {
+	sfs := rawdb.ReadAllSubmissionFaults(api.rcm.db)
+
+	faults := make([]map[string]interface{}, 0, len(sfs))
+	for _, sf := range sfs {
+		faults = append(faults, map[string]interface{}{
+			"kind":                  sf.Kind.String(),
+			"forkNumber":            hexutil.Uint64(sf.ForkNumber),
+			"epochNumber":           hexutil.Uint64(sf.EpochNumber),
+			"startBlockNumber":      hexutil.Uint64(sf.StartBlockNumber),
+			"endBlockNumber":        hexutil.Uint64(sf.EndBlockNumber),
+			"isRequest":             sf.IsRequest,
+			"submittedAt":           hexutil.Uint64(sf.SubmittedAt),
+			"statesRoot":            sf.StatesRoot,
+			"transactionsRoot":      sf.TransactionsRoot,
+			"receiptsRoot":          sf.ReceiptsRoot,
+			"localStatesRoot":       sf.LocalStatesRoot,
+			"localTransactionsRoot": sf.LocalTransactionsRoot,
+			"localReceiptsRoot":     sf.LocalReceiptsRoot,
+			"detectedAt":            hexutil.Uint64(sf.DetectedAt),
+		})
+	}
+	return faults
+}
+
+// OperatorCostsByEpoch returns the costs of root chain transactions sent by
+// this node for each epoch in the fork. Transactions not attributed to an
+// epoch (e.g., finalizeRequests) are counted only in OperatorCostsByDay.
+func (api *PublicRootChainAPI) OperatorCostsByEpoch(forkNumber hexutil.Uint64) []map[string]interface{} {
+	epochs, summaries := costsByEpoch(rawdb.ReadOperatorCosts(api.rcm.db, 0), uint64(forkNumber))
+
+	costs := make([]map[string]interface{}, 0, len(epochs))
+	for _, epochNumber := range epochs {
+		fields := rpcMarshalCostSummary(summaries[epochNumber])
+		fields["forkNumber"] = forkNumber
+		fields["epochNumber"] = hexutil.Uint64(epochNumber)
+		costs = append(costs, fields)
+	}
+	return costs
+}
+
+// OperatorCostsByDay returns the costs of root chain transactions sent by this
+// node for each day (UTC) in the recent days. All recorded days are returned
+// if days is zero.
+func (api *PublicRootChainAPI) OperatorCostsByDay(days hexutil.Uint64) []map[string]interface{} {
+	var since uint64
+	if now := uint64(time.Now().Unix()); days > 0 && now > uint64(days)*secondsPerDay {
+		since = (now/secondsPerDay - uint64(days) + 1) * secondsPerDay
+	}
+
+	starts, summaries := costsByDay(rawdb.ReadOperatorCosts(api.rcm.db, since))
+
+	costs := make([]map[string]interface{}, 0, len(starts))
+	for _, start := range starts {
+		fields := rpcMarshalCostSummary(summaries[start])
+		fields["date"] = time.Unix(int64(start), 0).UTC().Format("2006-01-02")
+		fields["timestamp"] = hexutil.Uint64(start)
+		costs = append(costs, fields)
+	}
+	return costs
+}
+
+// rpcMarshalCostSummary converts the cost summary into a JSON-RPC compatible
+// format. Total is the gas cost and the bonded ether minus refunds.
+func rpcMarshalCostSummary(s *costSummary) map[string]interface{} {
+	return map[string]interface{}{
+		"numTxs":   hexutil.Uint64(s.NumTxs),
+		"gasUsed":  hexutil.Uint64(s.GasUsed),
+		"gasCost":  (*hexutil.Big)(s.GasCost),
+		"bonded":   (*hexutil.Big)(s.Bonded),
+		"refunded": (*hexutil.Big)(s.Refunded),
+		"total":    (*hexutil.Big)(s.total()),
+	}
+}
@@ -18,6 +18,7 @@
 package pls
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
@@ -27,7 +28,6 @@ import (
 
 	"github.com/Onther-Tech/plasma-evm/accounts"
 	"github.com/Onther-Tech/plasma-evm/accounts/abi/bind"
-	"github.com/Onther-Tech/plasma-evm/accounts/keystore"
 	"github.com/Onther-Tech/plasma-evm/common"
 	"github.com/Onther-Tech/plasma-evm/common/hexutil"
 	"github.com/Onther-Tech/plasma-evm/consensus"
@@ -240,12 +240,18 @@ func New(ctx *node.ServiceContext, config *Config) (*Plasma, error) {
 	}
 	pls.APIBackend.gpo = gasprice.NewOracle(pls.APIBackend, gpoParams)
 
-	// Dial rootchain provider
-	rootchainBackend, err := ethclient.Dial(config.RootChainURL)
+	// Dial rootchain providers
+	var rootchainBackend *ethclient.Client
+	if len(config.RootChainFallbackURLs) == 0 && config.RootChainQuorum <= 1 {
+		rootchainBackend, err = ethclient.Dial(config.RootChainURL)
+	} else {
+		urls := append([]string{config.RootChainURL}, config.RootChainFallbackURLs...)
+		rootchainBackend, err = ethclient.DialFailover(context.Background(), urls, config.RootChainQuorum)
+	}
 	if err != nil {
 		return nil, err
 	}
-	log.Info("Rootchain provider connected", "url", config.RootChainURL)
+	log.Info("Rootchain provider connected", "url", config.RootChainURL, "fallbacks", len(config.RootChainFallbackURLs), "quorum", config.RootChainQuorum)
 
 	// Instantiate RootChain contract
 	rootchainContract, err := rootchain.NewRootChain(config.RootChainContract, rootchainBackend)
@@ -268,8 +274,7 @@ func New(ctx *node.ServiceContext, config *Config) (*Plasma, error) {
 
 	stopFn := func() { pls.Stop() }
 
-	ks := ctx.AccountManager.Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
-	txManager, err := tx.NewTransactionManager(ks, rootchainBackend, chainDb, &config.TxConfig)
+	txManager, err := tx.NewTransactionManager(ctx.AccountManager, rootchainBackend, chainDb, &config.TxConfig)
 
 	if err != nil {
 		return nil, err
@@ -280,6 +285,7 @@ func New(ctx *node.ServiceContext, config *Config) (*Plasma, error) {
 		stopFn,
 		pls.txPool,
 		pls.blockchain,
+		chainDb,
 		rootchainBackend,
 		rootchainContract,
 		pls.eventMux,
@@ -351,6 +357,11 @@ func (s *Plasma) APIs() []rpc.API {
 		apis = append(apis, s.lesServer.APIs()...)
 	}
 
+	// Append RootChain APIs served by root chain manager
+	if s.rootchainManager != nil {
+		apis = append(apis, s.rootchainManager.APIs()...)
+	}
+
 	// Append all the local APIs and return
 	return append(apis, []rpc.API{
 		{
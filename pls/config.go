@@ -46,6 +46,11 @@ var DefaultConfig = Config{
 	NodeMode: ModeUser,
 	SyncMode: downloader.FastSync,
 	TxConfig: *tx.DefaultConfig,
+	Finalizer: FinalizerConfig{
+		MaxBlocks:   10,
+		MaxRequests: 20,
+		Interval:    time.Minute,
+	},
 	Ethash: ethash.Config{
 		CacheDir:       "ethash",
 		CachesInMem:    2,
@@ -68,7 +73,8 @@ var DefaultConfig = Config{
 		Recommit: 3 * time.Second,
 	},
 
-	OperatorMinEther: big.NewInt(0.5 * params.Ether),
+	OperatorMinEther:  big.NewInt(0.5 * params.Ether),
+	OperatorMinRunway: 7,
 
 	TxPool: core.DefaultTxPoolConfig,
 	GPO: gasprice.Config{
@@ -98,6 +104,18 @@ func init() {
 	}
 }
 
+// FinalizerConfig contains options to finalize blocks and requests in RootChain
+// contract automatically.
+type FinalizerConfig struct {
+	Enabled bool
+	Account accounts.Account // account to send finalize transactions. operator or challenger account if empty
+
+	MaxBlocks   uint64        // maximum number of finalizeBlock transactions in a round
+	MaxRequests uint64        // maximum number of requests to finalize in a finalizeRequests transaction
+	Budget      *big.Int      // maximum amount of ether to spend. zero or nil means no limit
+	Interval    time.Duration // interval to check blocks and requests to finalize
+}
+
 //go:generate gencodec -type Config -formats toml -out gen_config.go
 
 type Config struct {
@@ -112,11 +130,44 @@ type Config struct {
 	Challenger accounts.Account
 	NodeMode   int
 
+	// URBSubmitter is the account of a user node to prepare and submit URBs
+	// when operator withholds blocks. Challenger node uses the challenger
+	// account instead.
+	URBSubmitter accounts.Account
+
 	OperatorMinEther   *big.Int
 	RootChainURL       string
 	RootChainContract  common.Address
 	RootChainNetworkID uint64
 
+	// Submitters are the accounts to submit NREs and ORBs with the operator.
+	// They must be registered as submitters in RootChain contract.
+	Submitters []accounts.Account
+
+	// OperatorPipeline makes operator mine the next NRE before it is prepared
+	// in root chain if the request epoch before it is empty.
+	OperatorPipeline bool
+
+	// OperatorMinRunway is the number of days the operator balance in root
+	// chain should last before it drops below OperatorMinEther, projected
+	// from the recent daily costs. A warning is logged if it is shorter.
+	OperatorMinRunway uint64
+
+	// RootChainFallbackURLs are JSONRPC endpoints of root chain providers to
+	// fail over to when the provider at RootChainURL does not respond.
+	RootChainFallbackURLs []string
+
+	// RootChainQuorum is the number of root chain providers which must agree
+	// on the results of contract calls (e.g., epochs and requests).
+	RootChainQuorum int
+
+	// RootChainConfirmations is the number of root chain blocks to wait
+	// before RootChain contract events are processed.
+	RootChainConfirmations uint64
+
+	// Finalizer options
+	Finalizer FinalizerConfig
+
 	// Protocol options
 	NetworkId uint64 // Network ID to use for selecting peers to connect to
 	SyncMode  downloader.SyncMode
@@ -0,0 +1,454 @@
+package pls
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/Onther-Tech/plasma-evm/accounts/abi"
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/core/rawdb"
+	"github.com/Onther-Tech/plasma-evm/core/types"
+	"github.com/Onther-Tech/plasma-evm/log"
+	"github.com/Onther-Tech/plasma-evm/tx"
+)
+
+const (
+	// runwayCheckInterval is the interval to check the runway of the operator
+	// balance in root chain.
+	runwayCheckInterval = time.Hour
+
+	// runwayPeriod is the period of recent costs to project the runway.
+	runwayPeriod = 7 * secondsPerDay
+
+	secondsPerDay = 24 * 60 * 60
+)
+
+// costKinds maps the methods of RootChain contract to the kinds of costs.
+// Transactions calling other methods are not recorded.
+var costKinds = map[string]rawdb.OperatorCostKind{
+	"submitNRE":            rawdb.CostSubmission,
+	"submitORB":            rawdb.CostSubmission,
+	"submitURB":            rawdb.CostSubmission,
+	"prepareToSubmitURB":   rawdb.CostSubmission,
+	"challengeExit":        rawdb.CostChallenge,
+	"challengeNullAddress": rawdb.CostChallenge,
+	"finalizeBlock":        rawdb.CostFinalization,
+	"finalizeRequest":      rawdb.CostFinalization,
+	"finalizeRequests":     rawdb.CostFinalization,
+}
+
+// costTracker records the costs of root chain transactions confirmed in
+// transaction manager: gas, ether bonded with submissions and ether refunded
+// by challenges and finalizations. Confirmed transactions are processed in
+// order with a cursor per account, so each of them is recorded once even if
+// the node is restarted.
+type costTracker struct {
+	rcm *RootChainManager
+}
+
+func newCostTracker(rcm *RootChainManager) *costTracker {
+	return &costTracker{rcm: rcm}
+}
+
+func (ct *costTracker) run() {
+	confirmedCh := make(chan *tx.RawTransaction, 128)
+	sub := ct.rcm.txManager.SubscribeConfirmedTxs(confirmedCh)
+	defer sub.Unsubscribe()
+
+	// record transactions confirmed while the node was stopped.
+	for _, addr := range ct.rcm.txManager.Addresses() {
+		ct.recordConfirmed(addr)
+	}
+	ct.checkRunway()
+
+	ticker := time.NewTicker(runwayCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case raw := <-confirmedCh:
+			ct.recordConfirmed(raw.From)
+
+		case <-ticker.C:
+			ct.checkRunway()
+
+		case <-sub.Err():
+			return
+
+		case <-ct.rcm.quit:
+			return
+		}
+	}
+}
+
+// recordConfirmed records the costs of the confirmed transactions of the
+// account after the cursor. It stops at the first transaction which fails to
+// be recorded, so that the transaction is retried later.
+func (ct *costTracker) recordConfirmed(addr common.Address) {
+	db := ct.rcm.db
+
+	cursor := rawdb.ReadCostCursor(db, addr)
+	for _, raw := range ct.rcm.txManager.Confirmed(addr, cursor) {
+		c, err := ct.operatorCost(raw)
+		if err != nil {
+			log.Warn("Failed to record root chain transaction cost", "caption", raw.Caption, "hash", raw.MinedTxHash, "err", err)
+			return
+		}
+
+		if c != nil {
+			rawdb.WriteOperatorCost(db, c)
+			log.Info("Root chain transaction cost recorded", "kind", c.Kind, "method", c.Method, "hash", c.TxHash, "gasUsed", c.GasUsed, "gasPrice", c.GasPrice, "value", c.Value, "refund", c.Refund)
+		}
+
+		cursor++
+		rawdb.WriteCostCursor(db, addr, cursor)
+	}
+}
+
+// operatorCost returns the cost of the raw transaction. It returns nil if the
+// transaction does not call RootChain contract to submit, challenge or
+// finalize.
+func (ct *costTracker) operatorCost(raw *tx.RawTransaction) (*rawdb.OperatorCost, error) {
+	rcm := ct.rcm
+
+	if raw.Recipient == nil || *raw.Recipient != rcm.config.RootChainContract || len(raw.Payload) < 4 {
+		return nil, nil
+	}
+
+	method, err := rootchainContractABI.MethodById(raw.Payload[:4])
+	if err != nil {
+		return nil, nil
+	}
+
+	kind, ok := costKinds[method.Name]
+	if !ok {
+		return nil, nil
+	}
+
+	receipt, err := rcm.backend.TransactionReceipt(context.Background(), raw.MinedTxHash)
+	if err != nil {
+		return nil, err
+	}
+	transaction, _, err := rcm.backend.TransactionByHash(context.Background(), raw.MinedTxHash)
+	if err != nil {
+		return nil, err
+	}
+	header, err := rcm.backend.HeaderByNumber(context.Background(), receipt.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &rawdb.OperatorCost{
+		Kind:      kind,
+		Method:    method.Name,
+		TxHash:    raw.MinedTxHash,
+		From:      raw.From,
+		Timestamp: header.Time,
+		Reverted:  receipt.Status == types.ReceiptStatusFailed,
+		GasUsed:   receipt.GasUsed,
+		GasPrice:  transaction.GasPrice(),
+		Value:     new(big.Int),
+		Refund:    new(big.Int),
+	}
+
+	// value of the reverted transaction is not transferred.
+	if c.Reverted {
+		return c, nil
+	}
+
+	if raw.Amount != nil {
+		c.Value.Set(raw.Amount)
+	}
+
+	if err := ct.attribute(c, method, raw.Payload[4:], receipt.Logs); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// attribute sets the epoch and the refund of the cost from the inputs and the
+// RootChain contract events of the transaction.
+func (ct *costTracker) attribute(c *rawdb.OperatorCost, method *abi.Method, input []byte, logs []*types.Log) error {
+	rcm := ct.rcm
+
+	setEpoch := func(forkNumber, epochNumber uint64) {
+		if !c.HasEpoch {
+			c.HasEpoch, c.ForkNumber, c.EpochNumber = true, forkNumber, epochNumber
+		}
+	}
+	setBlock := func(forkNumber, blockNumber *big.Int) error {
+		if c.HasEpoch {
+			return nil
+		}
+		block, err := rcm.getBlock(forkNumber, blockNumber)
+		if err != nil {
+			return err
+		}
+		setEpoch(forkNumber.Uint64(), block.EpochNumber)
+		return nil
+	}
+
+	switch method.Name {
+	case "submitNRE":
+		args, err := method.Inputs.UnpackValues(input)
+		if err != nil {
+			return err
+		}
+		// pos1 = fork number * 2^128 + epoch number
+		forkNumber, epochNumber := splitPos(args[0].(*big.Int))
+		setEpoch(forkNumber.Uint64(), epochNumber.Uint64())
+
+	case "challengeExit":
+		args, err := method.Inputs.UnpackValues(input)
+		if err != nil {
+			return err
+		}
+		if err := setBlock(args[0].(*big.Int), args[1].(*big.Int)); err != nil {
+			return err
+		}
+	}
+
+	for _, l := range logs {
+		if l.Address != rcm.config.RootChainContract || len(l.Topics) == 0 {
+			continue
+		}
+		event, err := rootchainContractABI.EventByID(l.Topics[0])
+		if err != nil {
+			continue
+		}
+
+		switch event.Name {
+		case "BlockSubmitted":
+			e, err := rcm.rootchainContract.ParseBlockSubmitted(*l)
+			if err != nil {
+				return err
+			}
+			setEpoch(e.Fork.Uint64(), e.EpochNumber.Uint64())
+
+		case "EpochPrepared":
+			if method.Name != "prepareToSubmitURB" {
+				continue
+			}
+			e, err := rcm.rootchainContract.ParseEpochPrepared(*l)
+			if err != nil {
+				return err
+			}
+			setEpoch(e.ForkNumber.Uint64(), e.EpochNumber.Uint64())
+
+		case "EpochFinalized":
+			e, err := rcm.rootchainContract.ParseEpochFinalized(*l)
+			if err != nil {
+				return err
+			}
+			setEpoch(e.ForkNumber.Uint64(), e.EpochNumber.Uint64())
+
+		case "BlockFinalized":
+			e, err := rcm.rootchainContract.ParseBlockFinalized(*l)
+			if err != nil {
+				return err
+			}
+			if err := setBlock(e.ForkNumber, e.BlockNumber); err != nil {
+				return err
+			}
+
+		case "RequestChallenged":
+			// challenger receives the cost of the exit request.
+			e, err := rcm.rootchainContract.ParseRequestChallenged(*l)
+			if err != nil {
+				return err
+			}
+			c.Refund.Add(c.Refund, ct.requestCost(e.UserActivated))
+
+		case "RequestApplied":
+			// requestor of the exit request receives the cost back.
+			e, err := rcm.rootchainContract.ParseRequestApplied(*l)
+			if err != nil {
+				return err
+			}
+			id := e.RequestId.Uint64()
+			requests, err := rcm.requestFetcher.fetchRequests(e.UserActivated, id, id)
+			if err != nil {
+				return err
+			}
+			if requests[0].IsExit && requests[0].Requestor == c.From {
+				c.Refund.Add(c.Refund, ct.requestCost(e.UserActivated))
+			}
+		}
+	}
+
+	return nil
+}
+
+// requestCost returns COST_ERU if userActivated is true, or COST_ERO.
+func (ct *costTracker) requestCost(userActivated bool) *big.Int {
+	if userActivated {
+		return new(big.Int).SetUint64(ct.rcm.state.costERU)
+	}
+	return new(big.Int).SetUint64(ct.rcm.state.costERO)
+}
+
+// runway returns the average daily cost of the operator and the submitters in
+// the recent period and the number of days their balances last above
+// OperatorMinEther at the cost. The runway is negative if the daily cost is
+// zero.
+func (ct *costTracker) runway() (dailyCost *big.Int, balance *big.Int, days float64, err error) {
+	rcm := ct.rcm
+
+	balances := make(map[common.Address]*big.Int)
+	for _, addr := range rcm.submitterAddresses() {
+		bal, err := rcm.backend.BalanceAt(context.Background(), addr, nil)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		balances[addr] = bal
+	}
+
+	now := uint64(time.Now().Unix())
+	since := uint64(0)
+	if now > runwayPeriod {
+		since = now - runwayPeriod
+	}
+
+	dailyCost, balance, days = projectRunway(rawdb.ReadOperatorCosts(rcm.db, since), balances, rcm.config.OperatorMinEther, now)
+	return dailyCost, balance, days, nil
+}
+
+// projectRunway returns the average daily cost of the accounts in the costs,
+// their total balance and the number of days the balances last above minEther
+// at the cost. Costs of other accounts are not counted.
+func projectRunway(costs []*rawdb.OperatorCost, balances map[common.Address]*big.Int, minEther *big.Int, now uint64) (dailyCost *big.Int, balance *big.Int, days float64) {
+	balance, available := new(big.Int), new(big.Int)
+	for _, bal := range balances {
+		balance.Add(balance, bal)
+
+		if bal.Cmp(minEther) > 0 {
+			available.Add(available, new(big.Int).Sub(bal, minEther))
+		}
+	}
+
+	var (
+		total  = new(big.Int)
+		oldest = now
+	)
+	for _, c := range costs {
+		if _, ok := balances[c.From]; !ok {
+			continue
+		}
+		total.Add(total, newCostSummary(c).total())
+		if c.Timestamp < oldest {
+			oldest = c.Timestamp
+		}
+	}
+
+	// average over the days of recorded costs, at least a day.
+	span := now - oldest
+	if span < secondsPerDay {
+		span = secondsPerDay
+	}
+	dailyCost = new(big.Int).Div(new(big.Int).Mul(total, big.NewInt(secondsPerDay)), new(big.Int).SetUint64(span))
+
+	if dailyCost.Sign() <= 0 {
+		return dailyCost, balance, -1
+	}
+
+	days, _ = new(big.Float).Quo(new(big.Float).SetInt(available), new(big.Float).SetInt(dailyCost)).Float64()
+
+	return dailyCost, balance, days
+}
+
+// checkRunway warns if the operator balance will drop below OperatorMinEther
+// within OperatorMinRunway days at the recent daily cost.
+func (ct *costTracker) checkRunway() {
+	rcm := ct.rcm
+	if rcm.config.NodeMode != ModeOperator || rcm.config.OperatorMinRunway == 0 {
+		return
+	}
+
+	dailyCost, balance, days, err := ct.runway()
+	if err != nil {
+		log.Warn("Failed to project operator balance runway", "err", err)
+		return
+	}
+
+	if days >= 0 && days < float64(rcm.config.OperatorMinRunway) {
+		log.Warn("Operator balance on rootchain runs out soon", "submitters", rcm.submitterAddresses(), "balance", balance, "minEther", rcm.config.OperatorMinEther, "dailyCost", dailyCost, "runwayDays", days, "minRunwayDays", rcm.config.OperatorMinRunway)
+	}
+}
+
+// costSummary is the total cost of root chain transactions in an epoch or a
+// day.
+type costSummary struct {
+	NumTxs   uint64
+	GasUsed  uint64
+	GasCost  *big.Int
+	Bonded   *big.Int
+	Refunded *big.Int
+}
+
+func newCostSummary(costs ...*rawdb.OperatorCost) *costSummary {
+	s := &costSummary{
+		GasCost:  new(big.Int),
+		Bonded:   new(big.Int),
+		Refunded: new(big.Int),
+	}
+	for _, c := range costs {
+		s.add(c)
+	}
+	return s
+}
+
+func (s *costSummary) add(c *rawdb.OperatorCost) {
+	s.NumTxs++
+	s.GasUsed += c.GasUsed
+	s.GasCost.Add(s.GasCost, new(big.Int).Mul(new(big.Int).SetUint64(c.GasUsed), c.GasPrice))
+	s.Bonded.Add(s.Bonded, c.Value)
+	s.Refunded.Add(s.Refunded, c.Refund)
+}
+
+// total returns the net cost, i.e., gas cost and bonded ether minus refunds.
+func (s *costSummary) total() *big.Int {
+	total := new(big.Int).Add(s.GasCost, s.Bonded)
+	return total.Sub(total, s.Refunded)
+}
+
+// costsByEpoch returns the cost summaries of the epochs in the fork, ordered
+// by epoch number. Costs not attributed to an epoch are not included.
+func costsByEpoch(costs []*rawdb.OperatorCost, forkNumber uint64) ([]uint64, map[uint64]*costSummary) {
+	summaries := make(map[uint64]*costSummary)
+	var epochs []uint64
+	for _, c := range costs {
+		if !c.HasEpoch || c.ForkNumber != forkNumber {
+			continue
+		}
+		s, ok := summaries[c.EpochNumber]
+		if !ok {
+			s = newCostSummary()
+			summaries[c.EpochNumber] = s
+			epochs = append(epochs, c.EpochNumber)
+		}
+		s.add(c)
+	}
+	sort.Slice(epochs, func(i, j int) bool { return epochs[i] < epochs[j] })
+	return epochs, summaries
+}
+
+// costsByDay returns the cost summaries of the days (in UTC), ordered by day.
+// Costs must be ordered by timestamp.
+func costsByDay(costs []*rawdb.OperatorCost) ([]uint64, map[uint64]*costSummary) {
+	summaries := make(map[uint64]*costSummary)
+	var days []uint64
+	for _, c := range costs {
+		day := c.Timestamp / secondsPerDay * secondsPerDay
+		s, ok := summaries[day]
+		if !ok {
+			s = newCostSummary()
+			summaries[day] = s
+			days = append(days, day)
+		}
+		s.add(c)
+	}
+	return days, summaries
+}
@@ -0,0 +1,192 @@
+package pls
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/core/rawdb"
+)
+
+func newTestOperatorCost(from common.Address, timestamp, gasUsed, gasPrice, value, refund int64) *rawdb.OperatorCost {
+	return &rawdb.OperatorCost{
+		From:      from,
+		Timestamp: uint64(timestamp),
+		GasUsed:   uint64(gasUsed),
+		GasPrice:  big.NewInt(gasPrice),
+		Value:     big.NewInt(value),
+		Refund:    big.NewInt(refund),
+	}
+}
+
+func newTestEpochCost(forkNumber, epochNumber uint64, gasUsed int64) *rawdb.OperatorCost {
+	c := newTestOperatorCost(common.Address{}, 0, gasUsed, 1, 0, 0)
+	c.HasEpoch, c.ForkNumber, c.EpochNumber = true, forkNumber, epochNumber
+	return c
+}
+
+func TestCostSummary(t *testing.T) {
+	s := newCostSummary(
+		newTestOperatorCost(common.Address{}, 0, 100, 3, 1000, 0),
+		newTestOperatorCost(common.Address{}, 0, 50, 2, 0, 400),
+	)
+
+	if s.NumTxs != 2 || s.GasUsed != 150 {
+		t.Fatalf("Summary mismatch: have %d txs %d gas, want %d txs %d gas", s.NumTxs, s.GasUsed, 2, 150)
+	}
+	if s.GasCost.Int64() != 400 || s.Bonded.Int64() != 1000 || s.Refunded.Int64() != 400 {
+		t.Fatalf("Summary mismatch: have gas cost %v bonded %v refunded %v, want %d %d %d", s.GasCost, s.Bonded, s.Refunded, 400, 1000, 400)
+	}
+	if total := s.total(); total.Int64() != 1000 {
+		t.Fatalf("Total mismatch: have %v, want %d", total, 1000)
+	}
+}
+
+func TestCostsByEpoch(t *testing.T) {
+	costs := []*rawdb.OperatorCost{
+		newTestEpochCost(1, 7, 10),
+		newTestEpochCost(1, 3, 20),
+		newTestEpochCost(0, 3, 40), // previous fork
+		newTestEpochCost(1, 7, 80),
+		newTestOperatorCost(common.Address{}, 0, 160, 1, 0, 0), // not attributed to an epoch
+	}
+
+	epochs, summaries := costsByEpoch(costs, 1)
+	if len(epochs) != 2 || epochs[0] != 3 || epochs[1] != 7 {
+		t.Fatalf("Epochs mismatch: have %v, want %v", epochs, []uint64{3, 7})
+	}
+	if len(summaries) != 2 {
+		t.Fatalf("Summaries count mismatch: have %d, want %d", len(summaries), 2)
+	}
+	if s := summaries[3]; s.NumTxs != 1 || s.GasUsed != 20 {
+		t.Errorf("Epoch#3 summary mismatch: have %d txs %d gas, want %d txs %d gas", s.NumTxs, s.GasUsed, 1, 20)
+	}
+	if s := summaries[7]; s.NumTxs != 2 || s.GasUsed != 90 {
+		t.Errorf("Epoch#7 summary mismatch: have %d txs %d gas, want %d txs %d gas", s.NumTxs, s.GasUsed, 2, 90)
+	}
+
+	if epochs, _ := costsByEpoch(costs, 2); len(epochs) != 0 {
+		t.Errorf("Epochs of unknown fork returned: %v", epochs)
+	}
+}
+
+func TestCostsByDay(t *testing.T) {
+	const day = secondsPerDay
+
+	costs := []*rawdb.OperatorCost{
+		newTestOperatorCost(common.Address{}, 10*day, 1, 1, 0, 0),
+		newTestOperatorCost(common.Address{}, 10*day+day-1, 2, 1, 0, 0),
+		newTestOperatorCost(common.Address{}, 11*day, 4, 1, 0, 0),
+		newTestOperatorCost(common.Address{}, 13*day+1, 8, 1, 0, 0),
+	}
+
+	days, summaries := costsByDay(costs)
+	if want := []uint64{10 * day, 11 * day, 13 * day}; !equalNumbers(days, want) {
+		t.Fatalf("Days mismatch: have %v, want %v", days, want)
+	}
+	for start, gasUsed := range map[uint64]uint64{10 * day: 3, 11 * day: 4, 13 * day: 8} {
+		if s := summaries[start]; s == nil || s.GasUsed != gasUsed {
+			t.Errorf("Day %d summary mismatch: have %+v, want %d gas", start, s, gasUsed)
+		}
+	}
+}
+
+func TestProjectRunway(t *testing.T) {
+	const (
+		day   = secondsPerDay
+		now   = 100 * day
+		ether = 1e18
+	)
+
+	var (
+		operator  = common.Address{0x01}
+		submitter = common.Address{0x02}
+		other     = common.Address{0x03}
+		minEther  = big.NewInt(ether)
+	)
+
+	tests := []struct {
+		name      string
+		costs     []*rawdb.OperatorCost
+		balances  map[common.Address]*big.Int
+		dailyCost int64
+		balance   int64
+		days      float64
+	}{
+		{
+			name:      "no cost",
+			balances:  map[common.Address]*big.Int{operator: big.NewInt(3 * ether)},
+			dailyCost: 0, balance: 3 * ether, days: -1,
+		},
+		{
+			// 0.2 ether in 2 days: 0.1 ether per day, 2 ether available.
+			name: "costs of recent days",
+			costs: []*rawdb.OperatorCost{
+				newTestOperatorCost(operator, now-2*day, 1e6, 1e11, 0, 0),
+				newTestOperatorCost(operator, now-day, 1e6, 1e11, 0, 0),
+			},
+			balances:  map[common.Address]*big.Int{operator: big.NewInt(3 * ether)},
+			dailyCost: ether / 10, balance: 3 * ether, days: 20,
+		},
+		{
+			// costs within a day are averaged over a day.
+			name: "costs of an hour",
+			costs: []*rawdb.OperatorCost{
+				newTestOperatorCost(operator, now-3600, 1e6, 1e11, 0, 0),
+			},
+			balances:  map[common.Address]*big.Int{operator: big.NewInt(2 * ether)},
+			dailyCost: ether / 10, balance: 2 * ether, days: 10,
+		},
+		{
+			// bonded ether is a cost until it is refunded.
+			name: "bonded and refunded",
+			costs: []*rawdb.OperatorCost{
+				newTestOperatorCost(operator, now-2*day, 0, 0, ether/2, 0),
+				newTestOperatorCost(operator, now-day, 0, 0, 0, ether/4),
+			},
+			balances:  map[common.Address]*big.Int{operator: big.NewInt(2 * ether)},
+			dailyCost: ether / 8, balance: 2 * ether, days: 8,
+		},
+		{
+			name: "refunds exceed costs",
+			costs: []*rawdb.OperatorCost{
+				newTestOperatorCost(operator, now-day, 1e6, 1e11, 0, ether),
+			},
+			balances:  map[common.Address]*big.Int{operator: big.NewInt(2 * ether)},
+			dailyCost: -ether + ether/10, balance: 2 * ether, days: -1,
+		},
+		{
+			// costs of submitters are counted, other accounts are not.
+			name: "submitters",
+			costs: []*rawdb.OperatorCost{
+				newTestOperatorCost(operator, now-day, 1e6, 1e11, 0, 0),
+				newTestOperatorCost(submitter, now-day, 1e6, 1e11, 0, 0),
+				newTestOperatorCost(other, now-day, 1e7, 1e11, 0, 0),
+			},
+			balances:  map[common.Address]*big.Int{operator: big.NewInt(2 * ether), submitter: big.NewInt(ether / 2)},
+			dailyCost: ether / 5, balance: 2*ether + ether/2, days: 5,
+		},
+		{
+			name: "balance below min ether",
+			costs: []*rawdb.OperatorCost{
+				newTestOperatorCost(operator, now-day, 1e6, 1e11, 0, 0),
+			},
+			balances:  map[common.Address]*big.Int{operator: big.NewInt(ether / 2)},
+			dailyCost: ether / 10, balance: ether / 2, days: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		dailyCost, balance, days := projectRunway(tt.costs, tt.balances, minEther, now)
+
+		if dailyCost.Int64() != tt.dailyCost {
+			t.Errorf("%s: daily cost mismatch: have %v, want %d", tt.name, dailyCost, tt.dailyCost)
+		}
+		if balance.Int64() != tt.balance {
+			t.Errorf("%s: balance mismatch: have %v, want %d", tt.name, balance, tt.balance)
+		}
+		if days != tt.days {
+			t.Errorf("%s: runway mismatch: have %v days, want %v days", tt.name, days, tt.days)
+		}
+	}
+}
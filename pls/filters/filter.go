@@ -135,6 +135,20 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	}
 	head := header.Number.Uint64()
 
+	// Resolve submitted and finalized block tags
+	for _, number := range []*int64{&f.begin, &f.end} {
+		if *number == rpc.SubmittedBlockNumber.Int64() || *number == rpc.FinalizedBlockNumber.Int64() {
+			header, err := f.backend.HeaderByNumber(ctx, rpc.BlockNumber(*number))
+			if err != nil {
+				return nil, err
+			}
+			if header == nil {
+				return nil, nil
+			}
+			*number = header.Number.Int64()
+		}
+	}
+
 	if f.begin == -1 {
 		f.begin = int64(head)
 	}
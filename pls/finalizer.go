@@ -0,0 +1,358 @@
+package pls
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	ethereum "github.com/Onther-Tech/plasma-evm"
+	"github.com/Onther-Tech/plasma-evm/accounts"
+	"github.com/Onther-Tech/plasma-evm/core/rawdb"
+	"github.com/Onther-Tech/plasma-evm/log"
+	"github.com/Onther-Tech/plasma-evm/params"
+	"github.com/Onther-Tech/plasma-evm/tx"
+)
+
+var (
+	ErrNoFinalizerAccount = errors.New("no account to send finalize transactions")
+	ErrBudgetExceeded     = errors.New("finalizer budget exceeded")
+	ErrInvalidInterval    = errors.New("finalizer interval must be positive")
+)
+
+// finalizer sends finalizeBlock and finalizeRequests transactions through
+// transaction manager after challenge periods of blocks and requests end.
+type finalizer struct {
+	rcm     *RootChainManager
+	config  *FinalizerConfig
+	account accounts.Account
+
+	spent    *big.Int            // amount of ether spent on confirmed finalize transactions
+	reserved map[uint64]*big.Int // raw transaction index => estimated cost of finalize transaction not confirmed yet
+
+	round     uint64 // number of finalizeBlock transactions in the current round
+	remaining uint64 // number of finalizeBlock transactions not added yet in the current round
+}
+
+func newFinalizer(rcm *RootChainManager) (*finalizer, error) {
+	config := &rcm.config.Finalizer
+	if config.Interval <= 0 {
+		return nil, ErrInvalidInterval
+	}
+
+	account := config.Account
+	if (account == accounts.Account{}) {
+		switch rcm.config.NodeMode {
+		case ModeOperator:
+			account = rcm.config.Operator
+		case ModeChallenger:
+			account = rcm.config.Challenger
+		default:
+			return nil, ErrNoFinalizerAccount
+		}
+	}
+
+	return &finalizer{
+		rcm:      rcm,
+		config:   config,
+		account:  account,
+		spent:    rawdb.ReadFinalizerSpent(rcm.db),
+		reserved: make(map[uint64]*big.Int),
+	}, nil
+}
+
+func (f *finalizer) run() {
+	confirmedCh := make(chan *tx.RawTransaction, 128)
+	sub := f.rcm.txManager.SubscribeConfirmedTxs(confirmedCh)
+	defer sub.Unsubscribe()
+
+	// charge transactions confirmed while the node was stopped.
+	f.chargeConfirmed()
+
+	log.Info("Finalizer started", "account", f.account.Address, "maxBlocks", f.config.MaxBlocks, "maxRequests", f.config.MaxRequests, "budget", f.config.Budget, "spent", f.spent)
+
+	ticker := time.NewTicker(f.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case raw := <-confirmedCh:
+			if raw.From != f.account.Address {
+				continue
+			}
+			f.chargeConfirmed()
+
+			// continue the round without waiting for the next tick.
+			if f.remaining > 0 && f.rcm.txManager.NumPending(f.account) == 0 {
+				if err := f.finalizeBlocks(); err != nil {
+					log.Warn("Failed to finalize blocks", "err", err)
+				}
+			}
+
+		case <-sub.Err():
+			return
+
+		case <-ticker.C:
+			// Short circuit if previous finalize transactions are not mined yet.
+			if f.rcm.txManager.NumPending(f.account) > 0 {
+				continue
+			}
+
+			if err := f.finalizeBlocks(); err != nil {
+				log.Warn("Failed to finalize blocks", "err", err)
+			}
+
+			if err := f.finalizeRequests(); err != nil {
+				log.Warn("Failed to finalize requests", "err", err)
+			}
+
+		case <-f.rcm.quit:
+			return
+		}
+	}
+}
+
+// finalizeBlocks adds a finalizeBlock transaction for the next block (or NRE)
+// whose challenge period is over. A round starts with the number of blocks to
+// finalize, and the rest of the round is added one by one after the previous
+// transaction is confirmed so that the gas of each transaction is estimated
+// against the state it is mined on.
+func (f *finalizer) finalizeBlocks() error {
+	if f.remaining == 0 {
+		n, err := f.numFinalizableBlocks()
+		if err != nil {
+			return err
+		}
+		f.round, f.remaining = n, n
+	}
+	if f.remaining == 0 {
+		return nil
+	}
+
+	input, err := rootchainContractABI.Pack("finalizeBlock")
+	if err != nil {
+		return err
+	}
+
+	// End the round if finalizeBlock would be reverted, e.g. blocks are
+	// finalized by others.
+	gasLimit, err := f.estimateGas(input)
+	if err != nil {
+		log.Debug("No block to finalize", "err", err)
+		f.remaining = 0
+		return nil
+	}
+
+	caption := fmt.Sprintf("finalizeBlock(%d/%d)", f.round-f.remaining+1, f.round)
+	if err := f.add(gasLimit, input, caption); err != nil {
+		return err
+	}
+	f.remaining--
+
+	return nil
+}
+
+// finalizeRequests adds a finalizeRequests transaction with the largest number
+// of requests not to be reverted.
+func (f *finalizer) finalizeRequests() error {
+	var (
+		input    []byte
+		gasLimit uint64
+	)
+
+	// binary search the number of requests to finalize
+	lo, hi := uint64(0), f.config.MaxRequests
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+
+		data, err := rootchainContractABI.Pack("finalizeRequests", new(big.Int).SetUint64(mid))
+		if err != nil {
+			return err
+		}
+
+		if gas, err := f.estimateGas(data); err == nil {
+			lo, input, gasLimit = mid, data, gas
+		} else {
+			hi = mid - 1
+		}
+	}
+
+	if lo == 0 {
+		return nil
+	}
+
+	return f.add(gasLimit, input, fmt.Sprintf("finalizeRequests(%d)", lo))
+}
+
+// numFinalizableBlocks returns the number of finalizeBlock transactions to
+// finalize blocks whose challenge period is over, up to MaxBlocks.
+func (f *finalizer) numFinalizableBlocks() (uint64, error) {
+	rcm := f.rcm
+
+	currentFork, err := rcm.rootchainContract.CurrentFork(baseCallOpt)
+	if err != nil {
+		return 0, err
+	}
+
+	fork, err := rcm.rootchainContract.Forks(baseCallOpt, currentFork)
+	if err != nil {
+		return 0, err
+	}
+
+	// Short circuit if waiting URBs
+	if fork.ForkedBlock != 0 {
+		return 0, nil
+	}
+
+	now := uint64(time.Now().Unix())
+
+	blockNumber := fork.LastFinalizedBlock + 1
+	if blockNumber < fork.FirstBlock {
+		blockNumber = fork.FirstBlock
+	}
+
+	var n uint64
+	for ; n < f.config.MaxBlocks && blockNumber <= fork.LastBlock; n++ {
+		block, err := rcm.getBlock(currentFork, new(big.Int).SetUint64(blockNumber))
+		if err != nil {
+			return 0, err
+		}
+
+		if block.Challenging {
+			break
+		}
+
+		if block.IsRequest {
+			if block.Timestamp+rcm.state.cpComputation > now {
+				break
+			}
+			blockNumber++
+			continue
+		}
+
+		// all blocks in NRE are finalized at once.
+		epoch, err := rcm.getEpoch(currentFork, new(big.Int).SetUint64(block.EpochNumber))
+		if err != nil {
+			return 0, err
+		}
+
+		if epoch.NRE.SubmittedAt == 0 || epoch.NRE.Challenging || epoch.NRE.Challenged ||
+			epoch.NRE.SubmittedAt+rcm.state.cpWithholding > now {
+			break
+		}
+		blockNumber = epoch.EndBlockNumber + 1
+	}
+
+	return n, nil
+}
+
+func (f *finalizer) estimateGas(input []byte) (uint64, error) {
+	rcm := f.rcm
+
+	gas, err := rcm.backend.EstimateGas(context.Background(), ethereum.CallMsg{
+		From: f.account.Address,
+		To:   &rcm.config.RootChainContract,
+		Data: input,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// add 20% margin as blocks and requests can be finalized by others.
+	gas = gas * 12 / 10
+	if gas > params.SubmitBlockGasLimit {
+		gas = params.SubmitBlockGasLimit
+	}
+
+	return gas, nil
+}
+
+// add adds finalize transaction to transaction manager if budget remains. The
+// budget is charged with the estimated cost until the transaction is confirmed.
+func (f *finalizer) add(gasLimit uint64, input []byte, caption string) error {
+	rcm := f.rcm
+
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), rcm.txManager.GasPrice())
+	spent := new(big.Int).Add(f.spent, cost)
+	for _, c := range f.reserved {
+		spent.Add(spent, c)
+	}
+
+	if budget := f.config.Budget; budget != nil && budget.Sign() > 0 && spent.Cmp(budget) > 0 {
+		log.Warn("Finalizer budget exceeded", "budget", budget, "spent", f.spent, "reserved", len(f.reserved), "cost", cost)
+		return ErrBudgetExceeded
+	}
+
+	raw := tx.NewRawTransaction(f.account.Address, gasLimit, &rcm.config.RootChainContract, big.NewInt(0), input, false, caption)
+
+	// finalize transactions of previous rounds may have same payloads.
+	err := rcm.txManager.Add(f.account, raw, false)
+	if err == tx.ErrDuplicateRaw {
+		err = rcm.txManager.Add(f.account, raw, true)
+	}
+	if err != nil {
+		return err
+	}
+
+	f.reserved[raw.Index] = cost
+
+	return nil
+}
+
+// chargeConfirmed charges the budget with the gas actually used by the
+// confirmed finalize transactions at the price they are mined, including
+// resent transactions with bumped gas price. It stops at the first transaction
+// whose receipt is not available, so that the transaction is charged later.
+func (f *finalizer) chargeConfirmed() {
+	rcm := f.rcm
+
+	cursor := rawdb.ReadFinalizerCursor(rcm.db)
+	for _, raw := range rcm.txManager.Confirmed(f.account.Address, cursor) {
+		cost, err := f.finalizeCost(raw)
+		if err != nil {
+			log.Warn("Failed to charge finalize transaction", "caption", raw.Caption, "hash", raw.MinedTxHash, "err", err)
+			return
+		}
+
+		if cost != nil {
+			f.spent = new(big.Int).Add(f.spent, cost)
+			rawdb.WriteFinalizerSpent(rcm.db, f.spent)
+			log.Debug("Finalizer budget is charged", "caption", raw.Caption, "cost", cost, "spent", f.spent)
+		}
+		delete(f.reserved, raw.Index)
+
+		cursor++
+		rawdb.WriteFinalizerCursor(rcm.db, cursor)
+	}
+}
+
+// finalizeCost returns gas used by the finalize transaction times its gas
+// price. It returns nil if the raw transaction does not finalize blocks or
+// requests.
+func (f *finalizer) finalizeCost(raw *tx.RawTransaction) (*big.Int, error) {
+	rcm := f.rcm
+
+	if raw.Recipient == nil || *raw.Recipient != rcm.config.RootChainContract || len(raw.Payload) < 4 {
+		return nil, nil
+	}
+
+	method, err := rootchainContractABI.MethodById(raw.Payload[:4])
+	if err != nil {
+		return nil, nil
+	}
+	if kind, ok := costKinds[method.Name]; !ok || kind != rawdb.CostFinalization {
+		return nil, nil
+	}
+
+	receipt, err := rcm.backend.TransactionReceipt(context.Background(), raw.MinedTxHash)
+	if err != nil {
+		return nil, err
+	}
+	transaction, _, err := rcm.backend.TransactionByHash(context.Background(), raw.MinedTxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), transaction.GasPrice()), nil
+}
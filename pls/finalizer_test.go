@@ -0,0 +1,15 @@
+package pls
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewFinalizerInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		rcm := &RootChainManager{config: &Config{Finalizer: FinalizerConfig{Interval: interval}}}
+		if _, err := newFinalizer(rcm); err != ErrInvalidInterval {
+			t.Errorf("Finalizer with interval %v: error mismatch: have %v, want %v", interval, err, ErrInvalidInterval)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package pls
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Onther-Tech/plasma-evm/contracts/plasma/rootchain"
+	"github.com/Onther-Tech/plasma-evm/core/rawdb"
+	"github.com/Onther-Tech/plasma-evm/core/types"
+	"github.com/Onther-Tech/plasma-evm/log"
+	"github.com/Onther-Tech/plasma-evm/miner/epoch"
+)
+
+// In pipelined mode, operator mines the next NRE right after the current NRE
+// is completed if the request epoch between them is already known to be
+// empty. RootChain contract decides whether a request epoch is empty when the
+// previous request epoch is prepared, so the next NRE is expected to start
+// right after the current NRE. The speculative NRE is submitted after it is
+// prepared in root chain, or rolled back if another epoch is prepared instead.
+
+// isSpeculativeEpoch returns true if the epoch is the speculative NRE. The
+// caller must hold rcm.lock.
+func (rcm *RootChainManager) isSpeculativeEpoch(forkNumber, epochNumber *big.Int) bool {
+	se := rcm.speculative
+	return se != nil && se.ForkNumber == forkNumber.Uint64() && se.EpochNumber == epochNumber.Uint64()
+}
+
+// startSpeculativeNRE starts to mine the NRE after the completed NRE if the
+// request epoch between them is empty.
+func (rcm *RootChainManager) startSpeculativeNRE() {
+	rcm.lock.Lock()
+	defer rcm.lock.Unlock()
+
+	if rcm.speculative != nil {
+		return
+	}
+
+	rcm.minerEnv.Lock()
+	var (
+		isRequest      = rcm.minerEnv.IsRequest
+		completed      = rcm.minerEnv.Completed
+		forkNumber     = new(big.Int).Set(rcm.minerEnv.CurrentFork)
+		epochNumber    = new(big.Int).Set(rcm.minerEnv.EpochNumber)
+		endBlockNumber = new(big.Int).Set(rcm.minerEnv.EndBlockNumber)
+	)
+	rcm.minerEnv.Unlock()
+
+	if isRequest || !completed {
+		return
+	}
+
+	requestEpochNumber := new(big.Int).Add(epochNumber, big.NewInt(1))
+	requestEpoch, err := rcm.getEpoch(forkNumber, requestEpochNumber)
+	if err != nil {
+		log.Warn("Failed to get next request epoch", "epochNumber", requestEpochNumber, "err", err)
+		return
+	}
+
+	if !requestEpoch.IsRequest || !requestEpoch.IsEmpty || requestEpoch.UserActivated {
+		log.Debug("Next request epoch is not empty, waiting for the epoch to be prepared", "epochNumber", requestEpochNumber)
+		return
+	}
+
+	length, err := rcm.NRELength()
+	if err != nil {
+		log.Warn("Failed to get NRE length", "err", err)
+		return
+	}
+
+	e := &rootchain.RootChainEpochPrepared{
+		ForkNumber:       forkNumber,
+		EpochNumber:      new(big.Int).Add(epochNumber, big.NewInt(2)),
+		StartBlockNumber: new(big.Int).Add(endBlockNumber, big.NewInt(1)),
+		EndBlockNumber:   new(big.Int).Add(endBlockNumber, length),
+	}
+
+	// keep epoch environment to roll back the speculative NRE.
+	rawdb.WriteEpochEnvSnapshot(rcm.db, e.ForkNumber.Uint64(), e.EpochNumber.Uint64(), rcm.minerEnv)
+
+	rcm.speculative = &rawdb.SpeculativeEpoch{
+		ForkNumber:       e.ForkNumber.Uint64(),
+		EpochNumber:      e.EpochNumber.Uint64(),
+		StartBlockNumber: e.StartBlockNumber.Uint64(),
+		EndBlockNumber:   e.EndBlockNumber.Uint64(),
+	}
+	rawdb.WriteSpeculativeEpoch(rcm.db, rcm.speculative)
+
+	log.Info("Speculative NRE is started", "epochNumber", e.EpochNumber, "startBlockNumber", e.StartBlockNumber, "endBlockNumber", e.EndBlockNumber)
+
+	go rcm.miner.Start(rcm.config.Operator.Address, e, false)
+}
+
+// reconcileSpeculativeNRE compares the prepared epoch with the speculative NRE.
+// The empty request epoch before the speculative NRE is skipped and the
+// speculative NRE is adopted if it is prepared as expected. Otherwise, the
+// speculative NRE is rolled back. It returns true if the event is handled.
+// The caller must hold rcm.lock.
+func (rcm *RootChainManager) reconcileSpeculativeNRE(ev *rootchain.RootChainEpochPrepared) (bool, error) {
+	se := rcm.speculative
+	if se == nil {
+		return false, nil
+	}
+
+	sameFork := ev.ForkNumber.Uint64() == se.ForkNumber
+
+	// the empty request epoch before the speculative NRE.
+	if sameFork && ev.EpochNumber.Uint64()+1 == se.EpochNumber && ev.IsRequest && !ev.UserActivated && ev.EpochIsEmpty {
+		if env := rawdb.ReadEpochEnvSnapshot(rcm.db, se.ForkNumber, se.EpochNumber); env != nil {
+			rawdb.WriteEpochEnvSnapshot(rcm.db, se.ForkNumber, ev.EpochNumber.Uint64(), env)
+		}
+		log.Info("Request epoch is empty, keep mining speculative NRE", "epochNumber", ev.EpochNumber)
+		return true, nil
+	}
+
+	if sameFork && ev.EpochNumber.Uint64() == se.EpochNumber && !ev.IsRequest && !ev.UserActivated &&
+		ev.StartBlockNumber.Uint64() == se.StartBlockNumber && ev.EndBlockNumber.Uint64() == se.EndBlockNumber {
+		return true, rcm.adoptSpeculativeNRE()
+	}
+
+	log.Warn("Another epoch is prepared instead of speculative NRE", "forkNumber", ev.ForkNumber, "epochNumber", ev.EpochNumber, "isRequest", ev.IsRequest, "userActivated", ev.UserActivated, "speculativeEpochNumber", se.EpochNumber)
+
+	return false, rcm.discardSpeculativeNRE()
+}
+
+// adoptSpeculativeNRE makes the speculative NRE as the prepared epoch. If it
+// is already completed, it is submitted right away. The caller must hold
+// rcm.lock.
+func (rcm *RootChainManager) adoptSpeculativeNRE() error {
+	se := rcm.speculative
+
+	rcm.speculative = nil
+	rawdb.DeleteSpeculativeEpoch(rcm.db)
+
+	log.Info("Speculative NRE is prepared", "epochNumber", se.EpochNumber, "startBlockNumber", se.StartBlockNumber, "endBlockNumber", se.EndBlockNumber)
+
+	rcm.minerEnv.Lock()
+	defer rcm.minerEnv.Unlock()
+
+	if !rcm.minerEnv.Completed {
+		return nil
+	}
+
+	if err := rcm.submitNRE(); err != nil {
+		return err
+	}
+
+	if rcm.config.OperatorPipeline {
+		go rcm.startSpeculativeNRE()
+	}
+	return nil
+}
+
+// discardSpeculativeNRE rolls back the blocks of the speculative NRE and
+// restores the epoch environment before it. Transactions in the blocks are
+// added to transaction pool again. The caller must hold rcm.lock.
+func (rcm *RootChainManager) discardSpeculativeNRE() error {
+	se := rcm.speculative
+	if se == nil {
+		return nil
+	}
+
+	env := rawdb.ReadEpochEnvSnapshot(rcm.db, se.ForkNumber, se.EpochNumber)
+	if env == nil {
+		return errors.New(fmt.Sprintf("No epoch environment before speculative epoch#%d of fork#%d", se.EpochNumber, se.ForkNumber))
+	}
+
+	rcm.miner.Stop()
+
+	var txs types.Transactions
+	if head := rcm.blockchain.CurrentBlock().NumberU64(); se.StartBlockNumber <= head {
+		for n := se.StartBlockNumber; n <= head; n++ {
+			if block := rcm.blockchain.GetBlockByNumber(n); block != nil {
+				txs = append(txs, block.Transactions()...)
+			}
+		}
+
+		if err := rcm.blockchain.SetHead(se.StartBlockNumber - 1); err != nil {
+			return err
+		}
+		log.Warn("Speculative NRE is rolled back", "epochNumber", se.EpochNumber, "from", head, "to", se.StartBlockNumber-1)
+	}
+
+	epoch.Copy(env, rcm.minerEnv)
+	rawdb.WriteEpochEnv(rcm.db, rcm.minerEnv)
+
+	rcm.speculative = nil
+	rawdb.DeleteSpeculativeEpoch(rcm.db)
+
+	for i, err := range rcm.txPool.AddLocals(txs) {
+		if err != nil {
+			log.Debug("Failed to add transaction of speculative NRE", "hash", txs[i].Hash(), "err", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package pls
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Onther-Tech/plasma-evm/contracts/plasma/rootchain"
+	"github.com/Onther-Tech/plasma-evm/core/rawdb"
+	"github.com/Onther-Tech/plasma-evm/miner/epoch"
+)
+
+func TestReconcileSpeculativeNRE(t *testing.T) {
+	type decision int
+	const (
+		none decision = iota
+		skip
+		adopt
+		discard
+	)
+
+	// NRE#4 ends at block#5, empty ORE#5 is expected and speculative NRE#6
+	// is mined from block#6 to block#10.
+	newEvent := func(forkNumber, epochNumber, start, end uint64, isRequest, userActivated, isEmpty bool) *rootchain.RootChainEpochPrepared {
+		return &rootchain.RootChainEpochPrepared{
+			ForkNumber:       new(big.Int).SetUint64(forkNumber),
+			EpochNumber:      new(big.Int).SetUint64(epochNumber),
+			StartBlockNumber: new(big.Int).SetUint64(start),
+			EndBlockNumber:   new(big.Int).SetUint64(end),
+			IsRequest:        isRequest,
+			UserActivated:    userActivated,
+			EpochIsEmpty:     isEmpty,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		speculative bool
+		event       *rootchain.RootChainEpochPrepared
+		want        decision
+	}{
+		{name: "no speculative NRE", event: newEvent(0, 5, 6, 5, true, false, true), want: none},
+		{name: "empty ORE", speculative: true, event: newEvent(0, 5, 6, 5, true, false, true), want: skip},
+		{name: "non-empty ORE", speculative: true, event: newEvent(0, 5, 6, 7, true, false, false), want: discard},
+		{name: "URB", speculative: true, event: newEvent(0, 5, 6, 6, true, true, false), want: discard},
+		{name: "ORE of another fork", speculative: true, event: newEvent(1, 5, 6, 5, true, false, true), want: discard},
+		{name: "prepared NRE", speculative: true, event: newEvent(0, 6, 6, 10, false, false, false), want: adopt},
+		{name: "NRE of different range", speculative: true, event: newEvent(0, 6, 6, 11, false, false, false), want: discard},
+		{name: "NRE of another fork", speculative: true, event: newEvent(1, 6, 6, 10, false, false, false), want: discard},
+	}
+
+	for _, tt := range tests {
+		rcm, cleanup := newTestOperatorManager(t, 10)
+
+		// epoch environment after NRE#4 is completed.
+		env := epoch.New()
+		env.EpochNumber = big.NewInt(4)
+		env.StartBlockNumber = big.NewInt(1)
+		env.EndBlockNumber = big.NewInt(5)
+		env.Completed = true
+
+		// epoch environment of the speculative NRE#6 being mined.
+		rcm.minerEnv.EpochNumber = big.NewInt(6)
+		rcm.minerEnv.StartBlockNumber = big.NewInt(6)
+		rcm.minerEnv.EndBlockNumber = big.NewInt(10)
+
+		if tt.speculative {
+			rawdb.WriteEpochEnvSnapshot(rcm.db, 0, 6, env)
+			rcm.speculative = &rawdb.SpeculativeEpoch{ForkNumber: 0, EpochNumber: 6, StartBlockNumber: 6, EndBlockNumber: 10}
+			rawdb.WriteSpeculativeEpoch(rcm.db, rcm.speculative)
+		}
+
+		handled, err := rcm.reconcileSpeculativeNRE(tt.event)
+		if err != nil {
+			t.Fatalf("%s: failed to reconcile speculative NRE: %v", tt.name, err)
+		}
+
+		var (
+			head    = rcm.blockchain.CurrentBlock().NumberU64()
+			stored  = rawdb.ReadSpeculativeEpoch(rcm.db)
+			epochNo = rcm.minerEnv.EpochNumber.Uint64()
+		)
+
+		switch tt.want {
+		case none:
+			if handled || head != 10 || stored != nil {
+				t.Errorf("%s: unexpected decision: handled %v, head %d, speculative %+v", tt.name, handled, head, stored)
+			}
+
+		case skip:
+			if !handled || rcm.speculative == nil || stored == nil || head != 10 || epochNo != 6 {
+				t.Errorf("%s: request epoch is not skipped: handled %v, head %d, speculative %+v", tt.name, handled, head, stored)
+			}
+			// speculative NRE can be rolled back to the environment before
+			// the skipped request epoch.
+			if snapshot := rawdb.ReadEpochEnvSnapshot(rcm.db, 0, 5); snapshot == nil || snapshot.EpochNumber.Uint64() != 4 {
+				t.Errorf("%s: epoch environment snapshot mismatch: %+v", tt.name, snapshot)
+			}
+
+		case adopt:
+			if !handled || rcm.speculative != nil || stored != nil || head != 10 || epochNo != 6 {
+				t.Errorf("%s: speculative NRE is not adopted: handled %v, head %d, speculative %+v", tt.name, handled, head, stored)
+			}
+
+		case discard:
+			if handled || rcm.speculative != nil || stored != nil {
+				t.Errorf("%s: speculative NRE is not discarded: handled %v, speculative %+v", tt.name, handled, stored)
+			}
+			if head != 5 {
+				t.Errorf("%s: plasma chain is not rolled back: have head %d, want %d", tt.name, head, 5)
+			}
+			if epochNo != 4 || !rcm.minerEnv.Completed {
+				t.Errorf("%s: epoch environment is not restored: epoch#%d, completed %v", tt.name, epochNo, rcm.minerEnv.Completed)
+			}
+		}
+
+		cleanup()
+	}
+}
@@ -0,0 +1,147 @@
+package pls
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Onther-Tech/plasma-evm/contracts/plasma/rootchain"
+	"github.com/Onther-Tech/plasma-evm/core/rawdb"
+	"github.com/Onther-Tech/plasma-evm/log"
+	"github.com/Onther-Tech/plasma-evm/tx"
+)
+
+// writeRequestEpochCheckpoint starts to checkpoint the progress of the request
+// epoch mined by the node.
+func (rcm *RootChainManager) writeRequestEpochCheckpoint(e *rootchain.RootChainEpochPrepared, requestBlockIds []*big.Int) {
+	cp := &rawdb.RequestEpochCheckpoint{
+		ForkNumber:       e.ForkNumber.Uint64(),
+		EpochNumber:      e.EpochNumber.Uint64(),
+		StartBlockNumber: e.StartBlockNumber.Uint64(),
+		UserActivated:    e.UserActivated,
+		Rebase:           e.Rebase,
+		RequestBlockIds:  make([]uint64, len(requestBlockIds)),
+	}
+	for i, id := range requestBlockIds {
+		cp.RequestBlockIds[i] = id.Uint64()
+	}
+
+	rcm.checkpointLock.Lock()
+	defer rcm.checkpointLock.Unlock()
+
+	rawdb.WriteRequestEpochCheckpoint(rcm.db, cp)
+}
+
+// deleteRequestEpochCheckpoint removes the checkpoint, e.g., when the epoch is
+// reverted by root chain reorg or plasma chain is rolled back to a new fork.
+func (rcm *RootChainManager) deleteRequestEpochCheckpoint() {
+	rcm.checkpointLock.Lock()
+	defer rcm.checkpointLock.Unlock()
+
+	rawdb.DeleteRequestEpochCheckpoint(rcm.db)
+}
+
+// resumableCheckpoint returns the checkpoint of the request epoch if the epoch
+// was interrupted while the node mined it. Request blocks mined but not
+// checkpointed before the interruption are counted from the plasma chain.
+func (rcm *RootChainManager) resumableCheckpoint(e *rootchain.RootChainEpochPrepared) *rawdb.RequestEpochCheckpoint {
+	if !e.IsRequest || !rcm.shouldMine(e) {
+		return nil
+	}
+
+	rcm.checkpointLock.Lock()
+	defer rcm.checkpointLock.Unlock()
+
+	cp := rawdb.ReadRequestEpochCheckpoint(rcm.db)
+	if cp == nil || cp.ForkNumber != e.ForkNumber.Uint64() || cp.EpochNumber != e.EpochNumber.Uint64() {
+		return nil
+	}
+
+	numBlocks := uint64(len(cp.RequestBlockIds))
+	if head := rcm.blockchain.CurrentBlock().NumberU64(); head >= cp.StartBlockNumber {
+		mined := head - cp.StartBlockNumber + 1
+		if mined > numBlocks {
+			mined = numBlocks
+		}
+		if mined > cp.NumMined {
+			cp.NumMined = mined
+			rawdb.WriteRequestEpochCheckpoint(rcm.db, cp)
+		}
+	}
+
+	return cp
+}
+
+// checkpointMined records that the request block is mined.
+func (rcm *RootChainManager) checkpointMined(blockNumber uint64) {
+	rcm.updateRequestEpochCheckpoint(blockNumber, func(cp *rawdb.RequestEpochCheckpoint, n uint64) {
+		if n > cp.NumMined {
+			cp.NumMined = n
+		}
+	})
+}
+
+// checkpointSubmitted records that the submit transaction of the request block
+// is added to transaction manager. The checkpoint is removed when all request
+// blocks of the epoch are submitted.
+func (rcm *RootChainManager) checkpointSubmitted(blockNumber uint64) {
+	rcm.updateRequestEpochCheckpoint(blockNumber, func(cp *rawdb.RequestEpochCheckpoint, n uint64) {
+		if n > cp.NumSubmitted {
+			cp.NumSubmitted = n
+		}
+	})
+}
+
+// updateRequestEpochCheckpoint updates the checkpoint with the number of
+// blocks in the epoch up to the block number.
+func (rcm *RootChainManager) updateRequestEpochCheckpoint(blockNumber uint64, update func(cp *rawdb.RequestEpochCheckpoint, n uint64)) {
+	rcm.checkpointLock.Lock()
+	defer rcm.checkpointLock.Unlock()
+
+	cp := rawdb.ReadRequestEpochCheckpoint(rcm.db)
+	if cp == nil || blockNumber < cp.StartBlockNumber || blockNumber-cp.StartBlockNumber >= uint64(len(cp.RequestBlockIds)) {
+		return
+	}
+
+	update(cp, blockNumber-cp.StartBlockNumber+1)
+
+	if cp.NumSubmitted == uint64(len(cp.RequestBlockIds)) {
+		log.Info("Request epoch is completed", "forkNumber", cp.ForkNumber, "epochNumber", cp.EpochNumber)
+		rawdb.DeleteRequestEpochCheckpoint(rcm.db)
+		return
+	}
+	rawdb.WriteRequestEpochCheckpoint(rcm.db, cp)
+}
+
+// resubmitRequestBlocks adds submit transactions of the request blocks mined
+// but not submitted before the epoch was interrupted.
+func (rcm *RootChainManager) resubmitRequestBlocks(cp *rawdb.RequestEpochCheckpoint) error {
+	for i := cp.NumSubmitted; i < cp.NumMined; i++ {
+		block := rcm.blockchain.GetBlockByNumber(cp.StartBlockNumber + i)
+		if block == nil {
+			return fmt.Errorf("request block #%d is not found", cp.StartBlockNumber+i)
+		}
+
+		var err error
+		if cp.UserActivated {
+			err = rcm.addURBSubmitTransaction(block)
+		} else {
+			err = rcm.addBlockSubmitTransaction(block)
+		}
+		if err != nil && err != tx.ErrDuplicateRaw {
+			return err
+		}
+
+		log.Info("Request block is resubmitted", "blockNumber", block.Number(), "duplicate", err == tx.ErrDuplicateRaw)
+		rcm.checkpointSubmitted(block.NumberU64())
+	}
+	return nil
+}
+
+// checkpointRequestBlockIds returns the request block ids in the checkpoint.
+func checkpointRequestBlockIds(cp *rawdb.RequestEpochCheckpoint) []*big.Int {
+	ids := make([]*big.Int, len(cp.RequestBlockIds))
+	for i, id := range cp.RequestBlockIds {
+		ids[i] = new(big.Int).SetUint64(id)
+	}
+	return ids
+}
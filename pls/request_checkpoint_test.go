@@ -0,0 +1,223 @@
+package pls
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Onther-Tech/plasma-evm/accounts"
+	"github.com/Onther-Tech/plasma-evm/accounts/keystore"
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/consensus/ethash"
+	"github.com/Onther-Tech/plasma-evm/contracts/plasma/rootchain"
+	"github.com/Onther-Tech/plasma-evm/core"
+	"github.com/Onther-Tech/plasma-evm/core/rawdb"
+	"github.com/Onther-Tech/plasma-evm/core/types"
+	"github.com/Onther-Tech/plasma-evm/core/vm"
+	"github.com/Onther-Tech/plasma-evm/event"
+	"github.com/Onther-Tech/plasma-evm/miner"
+	"github.com/Onther-Tech/plasma-evm/miner/epoch"
+	"github.com/Onther-Tech/plasma-evm/params"
+	"github.com/Onther-Tech/plasma-evm/tx"
+)
+
+// testMinerBackend is the backend of the miner of the test root chain manager.
+type testMinerBackend struct {
+	blockchain *core.BlockChain
+	txPool     *core.TxPool
+}
+
+func (b *testMinerBackend) BlockChain() *core.BlockChain { return b.blockchain }
+func (b *testMinerBackend) TxPool() *core.TxPool         { return b.txPool }
+
+// newTestOperatorManager creates a root chain manager of an operator node with
+// a plasma chain of numBlocks blocks. The miner and the transaction manager
+// are not started. The returned function stops the plasma chain and removes
+// the keystore.
+func newTestOperatorManager(t *testing.T, numBlocks int) (*RootChainManager, func()) {
+	dir, err := ioutil.TempDir("", "pls-rootchain-manager-test")
+	if err != nil {
+		t.Fatalf("Failed to create keystore directory: %v", err)
+	}
+
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	operator, err := ks.NewAccount("")
+	if err != nil {
+		t.Fatalf("Failed to create operator account: %v", err)
+	}
+	am := accounts.NewManager(&accounts.Config{InsecureUnlockAllowed: true}, ks)
+
+	db := rawdb.NewMemoryDatabase()
+	gspec := &core.Genesis{Config: params.TestChainConfig}
+	genesis := gspec.MustCommit(db)
+
+	blockchain, err := core.NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create plasma chain: %v", err)
+	}
+	blocks, _ := core.GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, numBlocks, nil)
+	if _, err := blockchain.InsertChain(blocks); err != nil {
+		t.Fatalf("Failed to insert blocks: %v", err)
+	}
+
+	txPoolConfig := core.DefaultTxPoolConfig
+	txPoolConfig.Journal = ""
+	txPool := core.NewTxPool(txPoolConfig, gspec.Config, blockchain)
+	minerEnv := epoch.New()
+	miner := miner.New(&testMinerBackend{blockchain, txPool}, &DefaultConfig.Miner, gspec.Config, new(event.TypeMux), ethash.NewFaker(), minerEnv, db, nil)
+
+	txConfig := *tx.DefaultConfig
+	txManager, err := tx.NewTransactionManager(am, nil, db, &txConfig)
+	if err != nil {
+		t.Fatalf("Failed to create transaction manager: %v", err)
+	}
+	txManager.SetPool(submitterPool, []accounts.Account{operator})
+
+	config := DefaultConfig
+	config.NodeMode = ModeOperator
+	config.Operator = operator
+
+	rcm := &RootChainManager{
+		config:      &config,
+		txPool:      txPool,
+		blockchain:  blockchain,
+		db:          db,
+		txManager:   txManager,
+		miner:       miner,
+		minerEnv:    minerEnv,
+		state:       &rootchainState{},
+		requestTxs:  make(map[uint64]types.Transactions),
+		submissions: make(map[common.Hash]time.Time),
+		quit:        make(chan struct{}),
+	}
+
+	return rcm, func() {
+		close(rcm.quit)
+		miner.Close()
+		txPool.Stop()
+		blockchain.Stop()
+		os.RemoveAll(dir)
+	}
+}
+
+func newTestEpochPrepared(forkNumber, epochNumber, startBlockNumber uint64, isRequest, userActivated bool) *rootchain.RootChainEpochPrepared {
+	return &rootchain.RootChainEpochPrepared{
+		ForkNumber:       new(big.Int).SetUint64(forkNumber),
+		EpochNumber:      new(big.Int).SetUint64(epochNumber),
+		StartBlockNumber: new(big.Int).SetUint64(startBlockNumber),
+		IsRequest:        isRequest,
+		UserActivated:    userActivated,
+	}
+}
+
+func TestResumableCheckpoint(t *testing.T) {
+	// request epoch#4 of 4 request blocks from block#3 to block#6.
+	tests := []struct {
+		head      int // plasma chain head
+		event     *rootchain.RootChainEpochPrepared
+		numMined  uint64 // checkpointed before the interruption
+		resumable bool
+		want      uint64 // number of mined blocks recovered
+	}{
+		{head: 2, event: newTestEpochPrepared(1, 4, 3, true, false), resumable: true, want: 0},
+		{head: 3, event: newTestEpochPrepared(1, 4, 3, true, false), resumable: true, want: 1},
+		{head: 5, event: newTestEpochPrepared(1, 4, 3, true, false), resumable: true, want: 3},
+		{head: 5, event: newTestEpochPrepared(1, 4, 3, true, false), numMined: 1, resumable: true, want: 3},
+		{head: 10, event: newTestEpochPrepared(1, 4, 3, true, false), resumable: true, want: 4},
+		// checkpointed number is not decreased by the plasma chain rolled back.
+		{head: 4, event: newTestEpochPrepared(1, 4, 3, true, false), numMined: 3, resumable: true, want: 3},
+		// checkpoint of another epoch or fork.
+		{head: 5, event: newTestEpochPrepared(1, 5, 7, true, false)},
+		{head: 5, event: newTestEpochPrepared(2, 4, 3, true, false)},
+		// epoch not mined by the operator.
+		{head: 5, event: newTestEpochPrepared(1, 4, 3, false, false)},
+		{head: 5, event: newTestEpochPrepared(1, 4, 3, true, true)},
+	}
+
+	for i, tt := range tests {
+		rcm, cleanup := newTestOperatorManager(t, tt.head)
+
+		rawdb.WriteRequestEpochCheckpoint(rcm.db, &rawdb.RequestEpochCheckpoint{
+			ForkNumber:       1,
+			EpochNumber:      4,
+			StartBlockNumber: 3,
+			RequestBlockIds:  []uint64{2, 3, 4, 5},
+			NumMined:         tt.numMined,
+		})
+
+		cp := rcm.resumableCheckpoint(tt.event)
+		switch {
+		case !tt.resumable && cp != nil:
+			t.Errorf("test %d: unexpected resumable checkpoint: %+v", i, cp)
+		case tt.resumable && cp == nil:
+			t.Errorf("test %d: checkpoint is not resumable", i)
+		case tt.resumable && cp.NumMined != tt.want:
+			t.Errorf("test %d: mined count mismatch: have %d, want %d", i, cp.NumMined, tt.want)
+		}
+
+		// recovered mined count is stored.
+		if stored := rawdb.ReadRequestEpochCheckpoint(rcm.db); tt.resumable && stored.NumMined != tt.want {
+			t.Errorf("test %d: stored mined count mismatch: have %d, want %d", i, stored.NumMined, tt.want)
+		}
+
+		cleanup()
+	}
+}
+
+func TestResubmitRequestBlocks(t *testing.T) {
+	rcm, cleanup := newTestOperatorManager(t, 10)
+	defer cleanup()
+
+	// block#3 was submitted, block#4 and block#5 were mined but not submitted.
+	cp := &rawdb.RequestEpochCheckpoint{
+		ForkNumber:       0,
+		EpochNumber:      4,
+		StartBlockNumber: 3,
+		RequestBlockIds:  []uint64{2, 3, 4, 5},
+		NumMined:         3,
+		NumSubmitted:     1,
+	}
+	rawdb.WriteRequestEpochCheckpoint(rcm.db, cp)
+
+	if err := rcm.resubmitRequestBlocks(cp); err != nil {
+		t.Fatalf("Failed to resubmit request blocks: %v", err)
+	}
+
+	pending, _, _, _ := rcm.txManager.Inspect(rcm.config.Operator.Address)
+	var captions []string
+	for _, raw := range pending {
+		captions = append(captions, raw.Caption)
+	}
+	if have, want := strings.Join(captions, ","), "submitORB(4),submitORB(5)"; have != want {
+		t.Fatalf("Resubmitted blocks mismatch: have %s, want %s", have, want)
+	}
+
+	stored := rawdb.ReadRequestEpochCheckpoint(rcm.db)
+	if stored == nil || stored.NumSubmitted != 3 {
+		t.Fatalf("Submitted count mismatch: have %+v, want %d", stored, 3)
+	}
+
+	// submit transactions already added are not duplicated.
+	if err := rcm.resubmitRequestBlocks(cp); err != nil {
+		t.Fatalf("Failed to resubmit request blocks again: %v", err)
+	}
+	if pending, _, _, _ := rcm.txManager.Inspect(rcm.config.Operator.Address); len(pending) != 2 {
+		t.Fatalf("Resubmitted blocks are duplicated: %d pending", len(pending))
+	}
+
+	// checkpoint is removed when all request blocks are submitted.
+	rcm.checkpointMined(6)
+	stored = rawdb.ReadRequestEpochCheckpoint(rcm.db)
+	if stored.NumMined != 4 {
+		t.Fatalf("Mined count mismatch: have %d, want %d", stored.NumMined, 4)
+	}
+	if err := rcm.resubmitRequestBlocks(stored); err != nil {
+		t.Fatalf("Failed to resubmit last request block: %v", err)
+	}
+	if stored := rawdb.ReadRequestEpochCheckpoint(rcm.db); stored != nil {
+		t.Fatalf("Checkpoint of completed epoch is not removed: %+v", stored)
+	}
+}
@@ -0,0 +1,275 @@
+package pls
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	ethereum "github.com/Onther-Tech/plasma-evm"
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/ethclient"
+	"github.com/Onther-Tech/plasma-evm/log"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	// requestBatchSize is the maximum number of calls in a batch request to
+	// root chain.
+	requestBatchSize = 500
+
+	// requestCacheSize is the number of requests (EROs and ERUs) kept in cache.
+	requestCacheSize = 8192
+)
+
+// rootchainRequest is an ERO or ERU in RootChain contract. It has only the
+// fields which are not changed once the request is created so that it can be
+// cached.
+type rootchainRequest struct {
+	Timestamp  uint64
+	IsExit     bool
+	IsTransfer bool
+	Value      *big.Int
+	Requestor  common.Address
+	To         common.Address
+	TrieKey    [32]byte
+	Hash       [32]byte
+	TrieValue  []byte
+}
+
+// rootchainRequestOutput is the output of EROs and ERUs, including the status
+// of the request changed after it is created.
+type rootchainRequestOutput struct {
+	Timestamp  uint64
+	IsExit     bool
+	IsTransfer bool
+	Finalized  bool
+	Challenged bool
+	Value      *big.Int
+	Requestor  common.Address
+	To         common.Address
+	TrieKey    [32]byte
+	Hash       [32]byte
+	TrieValue  []byte
+}
+
+// requestBlock is an ORB or URB in RootChain contract.
+type requestBlock struct {
+	Submitted    bool
+	NumEnter     uint64
+	EpochNumber  uint64
+	RequestStart uint64
+	RequestEnd   uint64
+	Trie         common.Address
+}
+
+type requestKey struct {
+	userActivated bool
+	requestId     uint64
+}
+
+// requestFetcher fetches request blocks, requests and requestable contract
+// mappings from RootChain contract with batch requests. Requests and mappings
+// are not changed once they are created, so they are cached to avoid fetching
+// them again, e.g., when a request block is verified after it is prepared.
+type requestFetcher struct {
+	backend  *ethclient.Client
+	contract common.Address
+
+	requests *lru.Cache                        // requestKey => *rootchainRequest
+	mappings map[common.Address]common.Address // root chain => child chain
+
+	lock sync.RWMutex // Protects mappings
+}
+
+func newRequestFetcher(backend *ethclient.Client, contract common.Address) *requestFetcher {
+	requests, _ := lru.New(requestCacheSize)
+
+	return &requestFetcher{
+		backend:  backend,
+		contract: contract,
+		requests: requests,
+		mappings: make(map[common.Address]common.Address),
+	}
+}
+
+// purge removes all cached requests, e.g., when root chain is reorganized.
+func (f *requestFetcher) purge() {
+	f.requests.Purge()
+
+	f.lock.Lock()
+	f.mappings = make(map[common.Address]common.Address)
+	f.lock.Unlock()
+}
+
+// batchCall calls the method of RootChain contract with each of the arguments
+// and unpacks the outputs into the results. Calls are sent in batches of
+// requestBatchSize.
+func (f *requestFetcher) batchCall(method string, args []interface{}, results []interface{}) error {
+	for start := 0; start < len(args); start += requestBatchSize {
+		end := start + requestBatchSize
+		if end > len(args) {
+			end = len(args)
+		}
+
+		msgs := make([]ethereum.CallMsg, 0, end-start)
+		for _, arg := range args[start:end] {
+			input, err := rootchainContractABI.Pack(method, arg)
+			if err != nil {
+				return err
+			}
+			msgs = append(msgs, ethereum.CallMsg{To: &f.contract, Data: input})
+		}
+
+		outputs, errs, err := f.backend.BatchCallContract(context.Background(), msgs, nil)
+		if err != nil {
+			return err
+		}
+
+		for i := range outputs {
+			if errs[i] != nil {
+				return fmt.Errorf("failed to call %s(%v): %v", method, args[start+i], errs[i])
+			}
+			if err := rootchainContractABI.Unpack(results[start+i], method, outputs[i]); err != nil {
+				return fmt.Errorf("failed to unpack %s(%v): %v", method, args[start+i], err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// requestBlocks returns ORBs (or URBs if userActivated is true) of the ids.
+func (f *requestFetcher) requestBlocks(userActivated bool, ids []*big.Int) ([]*requestBlock, error) {
+	method := "ORBs"
+	if userActivated {
+		method = "URBs"
+	}
+
+	args := make([]interface{}, len(ids))
+	results := make([]interface{}, len(ids))
+	blocks := make([]*requestBlock, len(ids))
+	for i, id := range ids {
+		blocks[i] = new(requestBlock)
+		args[i], results[i] = id, blocks[i]
+	}
+
+	if err := f.batchCall(method, args, results); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// fetchRequests returns EROs (or ERUs if userActivated is true) from start to
+// end. Requests not in cache are fetched in batches.
+func (f *requestFetcher) fetchRequests(userActivated bool, start, end uint64) ([]*rootchainRequest, error) {
+	if end < start {
+		return nil, nil
+	}
+
+	method := "EROs"
+	if userActivated {
+		method = "ERUs"
+	}
+
+	var (
+		requests = make([]*rootchainRequest, end-start+1)
+		missing  []uint64
+		args     []interface{}
+		results  []interface{}
+	)
+
+	for id := start; id <= end; id++ {
+		if cached, ok := f.requests.Get(requestKey{userActivated, id}); ok {
+			requests[id-start] = cached.(*rootchainRequest)
+			continue
+		}
+
+		missing = append(missing, id)
+		args = append(args, new(big.Int).SetUint64(id))
+		results = append(results, new(rootchainRequestOutput))
+	}
+
+	if len(missing) == 0 {
+		return requests, nil
+	}
+
+	if err := f.batchCall(method, args, results); err != nil {
+		return nil, err
+	}
+
+	for i, id := range missing {
+		o := results[i].(*rootchainRequestOutput)
+		r := &rootchainRequest{
+			Timestamp:  o.Timestamp,
+			IsExit:     o.IsExit,
+			IsTransfer: o.IsTransfer,
+			Value:      o.Value,
+			Requestor:  o.Requestor,
+			To:         o.To,
+			TrieKey:    o.TrieKey,
+			Hash:       o.Hash,
+			TrieValue:  o.TrieValue,
+		}
+		requests[id-start] = r
+
+		// Do not cache the request which is not created yet.
+		if r.Timestamp == 0 {
+			continue
+		}
+		f.requests.Add(requestKey{userActivated, id}, r)
+	}
+
+	log.Debug("Requests fetched", "userActivated", userActivated, "start", start, "end", end, "fetched", len(missing))
+
+	return requests, nil
+}
+
+// requestableContracts returns the child chain addresses of the requestable
+// contracts mapped in RootChain contract. Unmapped contracts are not cached as
+// they can be mapped later.
+func (f *requestFetcher) requestableContracts(addrs []common.Address) (map[common.Address]common.Address, error) {
+	var (
+		mappings = make(map[common.Address]common.Address)
+		args     []interface{}
+		results  []interface{}
+	)
+
+	f.lock.RLock()
+	for _, addr := range addrs {
+		if mapped, ok := f.mappings[addr]; ok {
+			mappings[addr] = mapped
+			continue
+		}
+		if _, ok := mappings[addr]; ok {
+			continue
+		}
+
+		mappings[addr] = common.Address{}
+		args = append(args, addr)
+		results = append(results, new(common.Address))
+	}
+	f.lock.RUnlock()
+
+	if len(args) == 0 {
+		return mappings, nil
+	}
+
+	if err := f.batchCall("requestableContracts", args, results); err != nil {
+		return nil, err
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for i, arg := range args {
+		addr, mapped := arg.(common.Address), *results[i].(*common.Address)
+		mappings[addr] = mapped
+
+		if (mapped != common.Address{}) {
+			f.mappings[addr] = mapped
+		}
+	}
+
+	return mappings, nil
+}
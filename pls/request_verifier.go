@@ -0,0 +1,86 @@
+package pls
+
+import (
+	"math/big"
+
+	"github.com/Onther-Tech/plasma-evm/core/types"
+	"github.com/Onther-Tech/plasma-evm/log"
+)
+
+// setRequestTxs keeps the request transactions expected in the request block
+// to verify the block received from operator. The request transactions of the
+// blocks already imported are pruned.
+func (rcm *RootChainManager) setRequestTxs(blockNumber uint64, txs types.Transactions) {
+	rcm.requestTxsLock.Lock()
+	defer rcm.requestTxsLock.Unlock()
+
+	head := rcm.blockchain.CurrentBlock().NumberU64()
+	for n := range rcm.requestTxs {
+		if n <= head {
+			delete(rcm.requestTxs, n)
+		}
+	}
+
+	rcm.requestTxs[blockNumber] = txs
+}
+
+// clearRequestTxs removes the expected request transactions, e.g., when the
+// plasma chain is rolled back to a new fork.
+func (rcm *RootChainManager) clearRequestTxs() {
+	rcm.requestTxsLock.Lock()
+	defer rcm.requestTxsLock.Unlock()
+
+	rcm.requestTxs = make(map[uint64]types.Transactions)
+}
+
+// expectedRequestTxs returns the request transactions expected in the request
+// block. They are prepared when the request epoch is prepared, or rebuilt from
+// ORBs (or URBs) and EROs (or ERUs) if the block is already submitted. It
+// returns nil if the requests of the block are not known yet.
+//
+// It is called while the block is imported, so the current fork is read from
+// the cached root chain state and the epoch is read only in a rebased fork.
+func (rcm *RootChainManager) expectedRequestTxs(block *types.Block) (types.Transactions, error) {
+	rcm.requestTxsLock.Lock()
+	txs, ok := rcm.requestTxs[block.NumberU64()]
+	rcm.requestTxsLock.Unlock()
+
+	if ok {
+		return txs, nil
+	}
+
+	rcm.state.lock.Lock()
+	currentFork := new(big.Int).SetUint64(rcm.state.currentFork)
+	rcm.state.lock.Unlock()
+
+	pb, err := rcm.getBlock(currentFork, block.Number())
+	if err != nil {
+		return nil, err
+	}
+
+	// Short circuit if the request block is not submitted yet.
+	if pb.Timestamp == 0 || !pb.IsRequest {
+		return nil, nil
+	}
+
+	// exit requests are not included in ORB'. There is no rebased epoch in
+	// the first fork.
+	var rebase bool
+	if currentFork.Sign() > 0 {
+		epoch, err := rcm.getEpoch(currentFork, new(big.Int).SetUint64(pb.EpochNumber))
+		if err != nil {
+			return nil, err
+		}
+		rebase = epoch.Rebase
+	}
+
+	txs, _, err = rcm.fetchRequestTxs(new(big.Int).SetUint64(pb.RequestBlockId), pb.UserActivated, rebase)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debug("Request txs rebuilt from root chain", "blockNumber", block.Number(), "requestBlockId", pb.RequestBlockId, "numRequests", len(txs))
+
+	rcm.setRequestTxs(block.NumberU64(), txs)
+	return txs, nil
+}
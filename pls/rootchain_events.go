@@ -0,0 +1,295 @@
+package pls
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/contracts/plasma/rootchain"
+	"github.com/Onther-Tech/plasma-evm/core/rawdb"
+	"github.com/Onther-Tech/plasma-evm/core/types"
+	"github.com/Onther-Tech/plasma-evm/ethdb"
+	"github.com/Onther-Tech/plasma-evm/log"
+	"github.com/Onther-Tech/plasma-evm/miner/epoch"
+)
+
+// rootchainEvent is a RootChain contract event waiting for confirmations.
+type rootchainEvent struct {
+	raw   types.Log
+	event interface{} // *rootchain.RootChainEpochPrepared, *rootchain.RootChainBlockFinalized or *rootchain.RootChainForked
+}
+
+// rootchainEvents implements sort.Interface to sort events in the order they
+// were emitted.
+type rootchainEvents []*rootchainEvent
+
+func (s rootchainEvents) Len() int      { return len(s) }
+func (s rootchainEvents) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s rootchainEvents) Less(i, j int) bool {
+	if s[i].raw.BlockNumber != s[j].raw.BlockNumber {
+		return s[i].raw.BlockNumber < s[j].raw.BlockNumber
+	}
+	return s[i].raw.Index < s[j].raw.Index
+}
+
+// eventQueue holds RootChain contract events until the root chain blocks
+// including them are confirmed.
+type eventQueue struct {
+	confirmations uint64
+	events        rootchainEvents
+}
+
+func newEventQueue(confirmations uint64) *eventQueue {
+	return &eventQueue{confirmations: confirmations}
+}
+
+// push adds the event to the queue. It returns false if the event is already
+// in the queue.
+func (q *eventQueue) push(e *rootchainEvent) bool {
+	for _, queued := range q.events {
+		if queued.raw.BlockHash == e.raw.BlockHash && queued.raw.Index == e.raw.Index {
+			return false
+		}
+	}
+
+	q.events = append(q.events, e)
+	sort.Sort(q.events)
+	return true
+}
+
+// remove removes the event from the queue. It returns false if the event is
+// not in the queue.
+func (q *eventQueue) remove(e *rootchainEvent) bool {
+	for i, queued := range q.events {
+		if queued.raw.BlockHash == e.raw.BlockHash && queued.raw.Index == e.raw.Index {
+			q.events = append(q.events[:i], q.events[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// pop removes and returns the events confirmed at the root chain head.
+func (q *eventQueue) pop(head uint64) rootchainEvents {
+	if head < q.confirmations {
+		return nil
+	}
+	confirmed := head - q.confirmations
+
+	n := sort.Search(len(q.events), func(i int) bool {
+		return q.events[i].raw.BlockNumber > confirmed
+	})
+
+	events := q.events[:n]
+	q.events = append(rootchainEvents{}, q.events[n:]...)
+	return events
+}
+
+// rootchainCursor returns the latest root chain block whose events are
+// processed.
+func (rcm *RootChainManager) rootchainCursor() *rawdb.RootChainBlock {
+	if cursor := rawdb.ReadRootChainCursor(rcm.db); cursor != nil {
+		return cursor
+	}
+
+	// fallback to the root chain block number without hash
+	return &rawdb.RootChainBlock{Number: rcm.blockchain.GetRootchainBlockNumber()}
+}
+
+// updateRootChainCursor records the root chain block of the processed event
+// and moves the cursor to the block. ev is the EpochPrepared event if the
+// processed event is EpochPrepared, nil otherwise.
+func (rcm *RootChainManager) updateRootChainCursor(raw types.Log, ev *rootchain.RootChainEpochPrepared) {
+	cursor := rcm.rootchainCursor()
+
+	b := rawdb.ReadRootChainBlock(rcm.db, raw.BlockNumber)
+	if b == nil || b.Hash != raw.BlockHash {
+		b = &rawdb.RootChainBlock{
+			Number: raw.BlockNumber,
+			Hash:   raw.BlockHash,
+		}
+		if cursor.Number < raw.BlockNumber {
+			b.Parent = cursor.Number
+		}
+	}
+
+	if ev != nil && !b.EpochPrepared {
+		b.EpochPrepared = true
+		b.ForkNumber = ev.ForkNumber.Uint64()
+		b.EpochNumber = ev.EpochNumber.Uint64()
+		b.StartBlockNumber = ev.StartBlockNumber.Uint64()
+	}
+
+	rawdb.WriteRootChainBlock(rcm.db, b)
+	if cursor.Number <= b.Number {
+		rawdb.WriteRootChainCursor(rcm.db, b)
+	}
+}
+
+// isCanonicalEvent returns true if the root chain block including the event
+// is in the canonical chain.
+func (rcm *RootChainManager) isCanonicalEvent(e *rootchainEvent) (bool, error) {
+	header, err := rcm.backend.HeaderByNumber(context.Background(), new(big.Int).SetUint64(e.raw.BlockNumber))
+	if err != nil {
+		return false, err
+	}
+	return header.Hash() == e.raw.BlockHash, nil
+}
+
+// handleRootChainEvent handles the confirmed event and moves the cursor.
+func (rcm *RootChainManager) handleRootChainEvent(e *rootchainEvent) error {
+	switch ev := e.event.(type) {
+	case *rootchain.RootChainEpochPrepared:
+		if err := rcm.handleEpochPrepared(ev); err != nil {
+			return err
+		}
+		rcm.updateRootChainCursor(e.raw, ev)
+	case *rootchain.RootChainBlockFinalized:
+		if err := rcm.handleBlockFinalized(ev); err != nil {
+			return err
+		}
+		rcm.updateRootChainCursor(e.raw, nil)
+	case *rootchain.RootChainForked:
+		if err := rcm.handleForked(ev); err != nil {
+			return err
+		}
+		rcm.updateRootChainCursor(e.raw, nil)
+	}
+
+	rcm.state.requestBlockNumbersUpdate()
+	return nil
+}
+
+// dispatchRootChainEvent sends the confirmed event to the handlers.
+func (rcm *RootChainManager) dispatchRootChainEvent(e *rootchainEvent) {
+	switch ev := e.event.(type) {
+	case *rootchain.RootChainEpochPrepared:
+		rcm.epochPreparedCh <- ev
+	case *rootchain.RootChainBlockFinalized:
+		rcm.blockFinalizedCh <- ev
+	case *rootchain.RootChainForked:
+		rcm.forkedCh <- ev
+	}
+}
+
+// checkRootChainCursor reverts the processed root chain blocks that are
+// reorganized while the node is stopped.
+func (rcm *RootChainManager) checkRootChainCursor() error {
+	cursor := rcm.rootchainCursor()
+
+	// Short circuit if the cursor is migrated from the root chain block number.
+	if (cursor.Hash == common.Hash{}) {
+		return nil
+	}
+
+	b := rawdb.ReadRootChainBlock(rcm.db, cursor.Number)
+	for b != nil {
+		header, err := rcm.backend.HeaderByNumber(context.Background(), new(big.Int).SetUint64(b.Number))
+		if err != nil {
+			return err
+		}
+
+		if header.Hash() == b.Hash {
+			break
+		}
+
+		log.Warn("Processed root chain block is reorganized", "number", b.Number, "hash", b.Hash, "canonical", header.Hash())
+
+		if b.Parent == 0 {
+			return rcm.revertRootChainBlocks(b.Number)
+		}
+		b = rawdb.ReadRootChainBlock(rcm.db, b.Parent)
+	}
+
+	if b != nil && b.Number < cursor.Number {
+		return rcm.revertRootChainBlocks(b.Number + 1)
+	}
+	return nil
+}
+
+// revertRootChainCursor deletes the processed root chain blocks from the
+// number and moves the cursor to the block before them. It returns the first
+// reverted block in which an epoch was prepared, or nil if there is none.
+func revertRootChainCursor(db ethdb.Database, cursor *rawdb.RootChainBlock, number uint64) *rawdb.RootChainBlock {
+	var (
+		b        = rawdb.ReadRootChainBlock(db, cursor.Number)
+		reverted *rawdb.RootChainBlock
+	)
+
+	for b != nil && b.Number >= number {
+		if b.EpochPrepared {
+			reverted = b
+		}
+		rawdb.DeleteRootChainBlock(db, b.Number)
+
+		if b.Parent == 0 {
+			b = nil
+			break
+		}
+		b = rawdb.ReadRootChainBlock(db, b.Parent)
+	}
+
+	if b == nil {
+		b = &rawdb.RootChainBlock{}
+		if number > 0 {
+			b.Number = number - 1
+		}
+	}
+	rawdb.WriteRootChainCursor(db, b)
+	log.Warn("Root chain cursor is reverted", "number", b.Number, "hash", b.Hash)
+
+	return reverted
+}
+
+// revertRootChainBlocks reverts the events processed in root chain blocks
+// from the number. If an epoch was prepared in the blocks, plasma chain is
+// rolled back to the block before the epoch and epoch environment is restored.
+func (rcm *RootChainManager) revertRootChainBlocks(number uint64) error {
+	rcm.lock.Lock()
+	defer rcm.lock.Unlock()
+
+	reverted := revertRootChainCursor(rcm.db, rcm.rootchainCursor(), number)
+
+	// requests in the reverted root chain blocks could be changed.
+	rcm.requestFetcher.purge()
+	rcm.state.requestBlockNumbersUpdate()
+
+	if reverted == nil {
+		return nil
+	}
+
+	// the epoch before the speculative NRE is reverted.
+	if err := rcm.discardSpeculativeNRE(); err != nil {
+		return err
+	}
+
+	env := rawdb.ReadEpochEnvSnapshot(rcm.db, reverted.ForkNumber, reverted.EpochNumber)
+	if env == nil {
+		return errors.New(fmt.Sprintf("No epoch environment before epoch#%d of fork#%d", reverted.EpochNumber, reverted.ForkNumber))
+	}
+
+	rcm.miner.Stop()
+
+	if head := rcm.blockchain.CurrentBlock().NumberU64(); reverted.StartBlockNumber > 0 && reverted.StartBlockNumber <= head {
+		if err := rcm.blockchain.SetHead(reverted.StartBlockNumber - 1); err != nil {
+			return err
+		}
+		log.Warn("Plasma chain is rolled back", "from", head, "to", reverted.StartBlockNumber-1)
+	}
+
+	epoch.Copy(env, rcm.minerEnv)
+	rcm.clearRequestTxs()
+	rcm.deleteRequestEpochCheckpoint()
+
+	rcm.state.lock.Lock()
+	rcm.state.currentFork = env.CurrentFork.Uint64()
+	rcm.state.lock.Unlock()
+
+	rawdb.WriteEpochEnv(rcm.db, rcm.minerEnv)
+
+	log.Warn("Epoch is reverted", "forkNumber", reverted.ForkNumber, "epochNumber", reverted.EpochNumber, "startBlockNumber", reverted.StartBlockNumber)
+	return nil
+}
@@ -0,0 +1,223 @@
+package pls
+
+import (
+	"testing"
+
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/contracts/plasma/rootchain"
+	"github.com/Onther-Tech/plasma-evm/core/rawdb"
+	"github.com/Onther-Tech/plasma-evm/core/types"
+)
+
+func newTestRootChainEvent(number uint64, hash byte, index uint) *rootchainEvent {
+	return &rootchainEvent{
+		raw: types.Log{
+			BlockNumber: number,
+			BlockHash:   common.Hash{hash},
+			Index:       index,
+		},
+	}
+}
+
+func eventNumbers(events rootchainEvents) []uint64 {
+	numbers := make([]uint64, len(events))
+	for i, e := range events {
+		numbers[i] = e.raw.BlockNumber
+	}
+	return numbers
+}
+
+func equalNumbers(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEventQueueOrder(t *testing.T) {
+	q := newEventQueue(0)
+
+	q.push(newTestRootChainEvent(12, 0x12, 1))
+	q.push(newTestRootChainEvent(10, 0x10, 0))
+	q.push(newTestRootChainEvent(12, 0x12, 0))
+
+	if q.push(newTestRootChainEvent(10, 0x10, 0)) {
+		t.Fatalf("Duplicate event is pushed")
+	}
+
+	events := q.pop(12)
+	if have, want := eventNumbers(events), []uint64{10, 12, 12}; !equalNumbers(have, want) {
+		t.Fatalf("Popped events mismatch: have %v, want %v", have, want)
+	}
+	if events[1].raw.Index != 0 || events[2].raw.Index != 1 {
+		t.Fatalf("Events in same block are not ordered by index: have %d, %d", events[1].raw.Index, events[2].raw.Index)
+	}
+	if len(q.events) != 0 {
+		t.Fatalf("Queue is not empty after pop: %d events", len(q.events))
+	}
+}
+
+func TestEventQueueConfirmations(t *testing.T) {
+	tests := []struct {
+		confirmations uint64
+		head          uint64
+		popped        []uint64
+		remaining     []uint64
+	}{
+		{confirmations: 0, head: 9, popped: []uint64{}, remaining: []uint64{10, 11, 15}},
+		{confirmations: 0, head: 11, popped: []uint64{10, 11}, remaining: []uint64{15}},
+		{confirmations: 3, head: 13, popped: []uint64{10}, remaining: []uint64{11, 15}},
+		{confirmations: 3, head: 18, popped: []uint64{10, 11, 15}, remaining: []uint64{}},
+		{confirmations: 20, head: 18, popped: []uint64{}, remaining: []uint64{10, 11, 15}},
+	}
+
+	for i, tt := range tests {
+		q := newEventQueue(tt.confirmations)
+		q.push(newTestRootChainEvent(15, 0x15, 0))
+		q.push(newTestRootChainEvent(10, 0x10, 0))
+		q.push(newTestRootChainEvent(11, 0x11, 0))
+
+		if have := eventNumbers(q.pop(tt.head)); !equalNumbers(have, tt.popped) {
+			t.Errorf("test %d: popped events mismatch: have %v, want %v", i, have, tt.popped)
+		}
+		if have := eventNumbers(q.events); !equalNumbers(have, tt.remaining) {
+			t.Errorf("test %d: remaining events mismatch: have %v, want %v", i, have, tt.remaining)
+		}
+	}
+}
+
+func TestEventQueueRequeue(t *testing.T) {
+	q := newEventQueue(0)
+	q.push(newTestRootChainEvent(10, 0x10, 0))
+	q.push(newTestRootChainEvent(11, 0x11, 0))
+
+	// events failed to be checked are queued again with newer events.
+	events := q.pop(11)
+	q.push(newTestRootChainEvent(12, 0x12, 0))
+	for _, e := range events {
+		q.push(e)
+	}
+
+	if have, want := eventNumbers(q.pop(12)), []uint64{10, 11, 12}; !equalNumbers(have, want) {
+		t.Fatalf("Requeued events mismatch: have %v, want %v", have, want)
+	}
+}
+
+func TestEventQueueRemove(t *testing.T) {
+	q := newEventQueue(2)
+
+	q.push(newTestRootChainEvent(10, 0x10, 0))
+	q.push(newTestRootChainEvent(11, 0x11, 0))
+
+	// event in the reorganized block is removed before it is confirmed.
+	removed := newTestRootChainEvent(11, 0x11, 0)
+	removed.raw.Removed = true
+	if !q.remove(removed) {
+		t.Fatalf("Pending event is not removed")
+	}
+
+	// event of the same block number in another block is not in the queue.
+	if q.remove(newTestRootChainEvent(10, 0xaa, 0)) {
+		t.Fatalf("Event in another block is removed")
+	}
+
+	// event in the new canonical block is pushed again.
+	if !q.push(newTestRootChainEvent(11, 0xbb, 0)) {
+		t.Fatalf("Event in new canonical block is not pushed")
+	}
+
+	events := q.pop(13)
+	if have, want := eventNumbers(events), []uint64{10, 11}; !equalNumbers(have, want) {
+		t.Fatalf("Popped events mismatch: have %v, want %v", have, want)
+	}
+	if events[1].raw.BlockHash != (common.Hash{0xbb}) {
+		t.Fatalf("Removed event is popped: %x", events[1].raw.BlockHash)
+	}
+}
+
+func TestUpdateRootChainCursor(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	rcm := &RootChainManager{db: db}
+
+	rawdb.WriteRootChainCursor(db, &rawdb.RootChainBlock{Number: 5})
+
+	ev := &rootchain.RootChainEpochPrepared{
+		ForkNumber:       common.Big0,
+		EpochNumber:      common.Big3,
+		StartBlockNumber: common.Big2,
+	}
+	rcm.updateRootChainCursor(types.Log{BlockNumber: 10, BlockHash: common.Hash{0x10}}, ev)
+	rcm.updateRootChainCursor(types.Log{BlockNumber: 12, BlockHash: common.Hash{0x12}}, nil)
+
+	// event in a processed block does not move the cursor backward.
+	rcm.updateRootChainCursor(types.Log{BlockNumber: 10, BlockHash: common.Hash{0x10}}, nil)
+
+	cursor := rcm.rootchainCursor()
+	if cursor.Number != 12 || cursor.Hash != (common.Hash{0x12}) || cursor.Parent != 10 {
+		t.Fatalf("Cursor mismatch: have %d %x parent %d, want %d %x parent %d", cursor.Number, cursor.Hash, cursor.Parent, 12, common.Hash{0x12}, 10)
+	}
+
+	b := rawdb.ReadRootChainBlock(db, 10)
+	if b == nil || !b.EpochPrepared || b.EpochNumber != 3 || b.StartBlockNumber != 2 {
+		t.Fatalf("EpochPrepared block mismatch: %+v", b)
+	}
+	if b.Parent != 5 {
+		t.Fatalf("Parent mismatch: have %d, want %d", b.Parent, 5)
+	}
+}
+
+func TestRevertRootChainCursor(t *testing.T) {
+	// processed blocks: 10 (epoch#3) <- 12 <- 15 (epoch#4) <- 18 (epoch#5)
+	blocks := []*rawdb.RootChainBlock{
+		{Number: 10, Hash: common.Hash{0x10}, EpochPrepared: true, EpochNumber: 3, StartBlockNumber: 5},
+		{Number: 12, Hash: common.Hash{0x12}, Parent: 10},
+		{Number: 15, Hash: common.Hash{0x15}, Parent: 12, EpochPrepared: true, EpochNumber: 4, StartBlockNumber: 9},
+		{Number: 18, Hash: common.Hash{0x18}, Parent: 15, EpochPrepared: true, EpochNumber: 5, StartBlockNumber: 13},
+	}
+
+	tests := []struct {
+		number   uint64
+		cursor   uint64
+		reverted uint64 // epoch number of the first reverted epoch, 0 if none
+		deleted  []uint64
+	}{
+		{number: 19, cursor: 18, reverted: 0},
+		{number: 18, cursor: 15, reverted: 5, deleted: []uint64{18}},
+		{number: 13, cursor: 12, reverted: 4, deleted: []uint64{18, 15}},
+		{number: 12, cursor: 10, reverted: 4, deleted: []uint64{18, 15, 12}},
+		{number: 10, cursor: 9, reverted: 3, deleted: []uint64{18, 15, 12, 10}},
+	}
+
+	for i, tt := range tests {
+		db := rawdb.NewMemoryDatabase()
+		for _, b := range blocks {
+			rawdb.WriteRootChainBlock(db, b)
+		}
+		cursor := blocks[len(blocks)-1]
+		rawdb.WriteRootChainCursor(db, cursor)
+
+		reverted := revertRootChainCursor(db, cursor, tt.number)
+
+		switch {
+		case tt.reverted == 0 && reverted != nil:
+			t.Errorf("test %d: unexpected reverted epoch#%d", i, reverted.EpochNumber)
+		case tt.reverted != 0 && (reverted == nil || reverted.EpochNumber != tt.reverted):
+			t.Errorf("test %d: reverted epoch mismatch: have %+v, want epoch#%d", i, reverted, tt.reverted)
+		}
+
+		if have := rawdb.ReadRootChainCursor(db); have == nil || have.Number != tt.cursor {
+			t.Errorf("test %d: cursor mismatch: have %+v, want %d", i, have, tt.cursor)
+		}
+
+		for _, n := range tt.deleted {
+			if rawdb.ReadRootChainBlock(db, n) != nil {
+				t.Errorf("test %d: reverted block %d is not deleted", i, n)
+			}
+		}
+	}
+}
@@ -9,24 +9,45 @@ import (
 	"sync"
 	"time"
 
+	ethereum "github.com/Onther-Tech/plasma-evm"
 	"github.com/Onther-Tech/plasma-evm/accounts"
 	"github.com/Onther-Tech/plasma-evm/accounts/abi"
 	"github.com/Onther-Tech/plasma-evm/accounts/abi/bind"
 	"github.com/Onther-Tech/plasma-evm/common"
 	"github.com/Onther-Tech/plasma-evm/contracts/plasma/rootchain"
 	"github.com/Onther-Tech/plasma-evm/core"
+	"github.com/Onther-Tech/plasma-evm/core/rawdb"
 	"github.com/Onther-Tech/plasma-evm/core/types"
 	"github.com/Onther-Tech/plasma-evm/ethclient"
+	"github.com/Onther-Tech/plasma-evm/ethdb"
 	"github.com/Onther-Tech/plasma-evm/event"
 	"github.com/Onther-Tech/plasma-evm/log"
+	"github.com/Onther-Tech/plasma-evm/metrics"
 	"github.com/Onther-Tech/plasma-evm/miner"
 	"github.com/Onther-Tech/plasma-evm/miner/epoch"
 	"github.com/Onther-Tech/plasma-evm/params"
+	"github.com/Onther-Tech/plasma-evm/rlp"
 	"github.com/Onther-Tech/plasma-evm/tx"
 )
 
 const (
 	MAX_EPOCH_EVENTS = 0
+
+	// nullAddressCheckInterval is the interval to check whether the blocks with
+	// null address transactions are submitted to root chain.
+	nullAddressCheckInterval = 10 * time.Second
+
+	// withholdingCheckInterval is the interval to check whether operator
+	// withholds blocks while there are ERUs to be included in URBs.
+	withholdingCheckInterval = 1 * time.Minute
+
+	// blockNumbersUpdateInterval is the interval to update the last submitted
+	// block number as NRE submission does not fire BlockSubmitted event.
+	blockNumbersUpdateInterval = 30 * time.Second
+
+	// resubscribeBackoff is the maximum time to wait before re-subscribing a
+	// failed RootChain event subscription.
+	resubscribeBackoff = 10 * time.Second
 )
 
 var (
@@ -37,15 +58,7 @@ var (
 	ErrKnownTransaction = errors.New("known transaction")
 )
 
-type invalidExit struct {
-	forkNumber  *big.Int
-	blockNumber *big.Int
-	receipt     *types.Receipt
-	index       int64
-	proof       []common.Hash
-}
-
-type invalidExits []*invalidExit
+type invalidExits []*rawdb.InvalidExit
 
 type RootChainManager struct {
 	config *Config
@@ -53,6 +66,7 @@ type RootChainManager struct {
 
 	txPool     *core.TxPool
 	blockchain *core.BlockChain
+	db         ethdb.Database
 
 	backend           *ethclient.Client
 	rootchainContract *rootchain.RootChain
@@ -68,10 +82,30 @@ type RootChainManager struct {
 	// fork => block number => invalidExits
 	invalidExits map[uint64]map[uint64]invalidExits
 
+	// block number => null address transactions to challenge
+	nullAddressTxs map[uint64][]*rawdb.NullAddressTx
+
+	// request block number => request transactions expected in the block
+	requestTxs     map[uint64]types.Transactions
+	requestTxsLock sync.Mutex
+
+	requestFetcher *requestFetcher
+	costTracker    *costTracker
+
+	checkpointLock sync.Mutex // Protects the request epoch checkpoint in database
+
+	// NRE mined before it is prepared in root chain
+	speculative *rawdb.SpeculativeEpoch
+
+	// raw transaction hash => time when the submit transaction is added
+	submissions     map[common.Hash]time.Time
+	submissionsLock sync.Mutex
+
 	// channels
 	quit             chan struct{}
 	epochPreparedCh  chan *rootchain.RootChainEpochPrepared
 	blockFinalizedCh chan *rootchain.RootChainBlockFinalized
+	forkedCh         chan *rootchain.RootChainForked
 
 	lock sync.RWMutex // Protects the variadic fields (e.g. gas price and etherbase)
 }
@@ -86,6 +120,7 @@ func NewRootChainManager(
 	stopFn func(),
 	txPool *core.TxPool,
 	blockchain *core.BlockChain,
+	db ethdb.Database,
 	backend *ethclient.Client,
 	rootchainContract *rootchain.RootChain,
 	eventMux *event.TypeMux,
@@ -121,6 +156,7 @@ func NewRootChainManager(
 		stopFn:            stopFn,
 		txPool:            txPool,
 		blockchain:        blockchain,
+		db:                db,
 		backend:           backend,
 		rootchainContract: rootchainContract,
 		eventMux:          eventMux,
@@ -129,12 +165,21 @@ func NewRootChainManager(
 		miner:             miner,
 		minerEnv:          env,
 		invalidExits:      make(map[uint64]map[uint64]invalidExits),
+		nullAddressTxs:    make(map[uint64][]*rawdb.NullAddressTx),
+		requestTxs:        make(map[uint64]types.Transactions),
+		requestFetcher:    newRequestFetcher(backend, config.RootChainContract),
+		speculative:       rawdb.ReadSpeculativeEpoch(db),
+		submissions:       make(map[common.Hash]time.Time),
 		quit:              make(chan struct{}),
 		epochPreparedCh:   make(chan *rootchain.RootChainEpochPrepared, MAX_EPOCH_EVENTS),
 		blockFinalizedCh:  make(chan *rootchain.RootChainBlockFinalized),
+		forkedCh:          make(chan *rootchain.RootChainForked),
 	}
 
 	rcm.state = newRootchainState(rcm)
+	rcm.costTracker = newCostTracker(rcm)
+	rcm.loadInvalidExits()
+	rcm.loadNullAddressTxs()
 
 	epochLength, err := rcm.NRELength()
 	if err != nil {
@@ -149,15 +194,39 @@ func NewRootChainManager(
 }
 
 func (rcm *RootChainManager) Start() error {
+	if rcm.config.NodeMode == ModeOperator {
+		rcm.updateSubmitterPool()
+		if len(rcm.config.Submitters) > 0 {
+			go rcm.runSubmitterPool()
+		}
+	}
+
 	if err := rcm.run(); err != nil {
 		return err
 	}
 
 	go rcm.pingBackend()
 	rcm.txManager.Start()
+	go rcm.challengeFinalizedExits()
+	go rcm.costTracker.run()
+
+	if metrics.Enabled {
+		go rcm.runMetrics()
+	}
+
+	if rcm.config.Finalizer.Enabled {
+		f, err := newFinalizer(rcm)
+		if err != nil {
+			return err
+		}
+		go f.run()
+	}
 
 	if rcm.config.NodeMode == ModeOperator {
 		go rcm.miner.Start(rcm.config.Operator.Address, new(rootchain.RootChainEpochPrepared), true)
+	} else {
+		// verify request blocks from operator against requests in root chain.
+		rcm.blockchain.SetRequestTxsFn(rcm.expectedRequestTxs)
 	}
 
 	return nil
@@ -174,6 +243,10 @@ func (rcm *RootChainManager) run() error {
 	go rcm.runHandlers()
 	go rcm.runSubmitter()
 	go rcm.runDetector()
+	go rcm.runNullAddressDetector()
+	go rcm.runURBPreparer()
+	go rcm.runSubmissionDetector()
+	go rcm.runBlockNumbersUpdater()
 
 	if err := rcm.watchEvents(); err != nil {
 		return err
@@ -182,7 +255,8 @@ func (rcm *RootChainManager) run() error {
 	return nil
 }
 
-// watchEvents watchs RootChain contract events
+// watchEvents watchs RootChain contract events. Events are processed after
+// the root chain blocks including them are confirmed.
 func (rcm *RootChainManager) watchEvents() error {
 	closed := false
 
@@ -191,13 +265,26 @@ func (rcm *RootChainManager) watchEvents() error {
 		return err
 	}
 
-	startBlockNumber := rcm.blockchain.GetRootchainBlockNumber()
+	// revert epochs prepared in root chain blocks reorganized while the node is stopped.
+	if err := rcm.checkRootChainCursor(); err != nil {
+		return err
+	}
+
+	head, err := rcm.backend.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	headNumber := head.Number.Uint64()
+
+	startBlockNumber := rcm.rootchainCursor().Number
 	filterOpts := &bind.FilterOpts{
 		Start:   startBlockNumber,
-		End:     nil,
+		End:     &headNumber,
 		Context: context.Background(),
 	}
 
+	queue := newEventQueue(rcm.config.RootChainConfirmations)
+
 	// TODO: have to read only NRE1
 	// iterate to find previous epoch prepared events
 	iteratorForEpochPreparedEvent, err := filterer.FilterEpochPrepared(filterOpts)
@@ -209,9 +296,7 @@ func (rcm *RootChainManager) watchEvents() error {
 	for iteratorForEpochPreparedEvent.Next() {
 		e := iteratorForEpochPreparedEvent.Event
 		if e != nil {
-			if err := rcm.handleEpochPrepared(e); err != nil {
-				log.Error("Failed to handle past EpochPrepared events", "err", err)
-			}
+			queue.push(&rootchainEvent{raw: e.Raw, event: e})
 		}
 	}
 
@@ -225,18 +310,47 @@ func (rcm *RootChainManager) watchEvents() error {
 	for iteratorForBlockFinalizedEvent.Next() {
 		e := iteratorForBlockFinalizedEvent.Event
 		if e != nil {
-			if err := rcm.handleBlockFinalized(e); err != nil {
-				log.Error("Failed to handle past BlockFinalized events", "err", err)
-			}
+			queue.push(&rootchainEvent{raw: e.Raw, event: e})
+		}
+	}
+
+	// iterate to find previous forked events
+	iteratorForForkedEvent, err := filterer.FilterForked(filterOpts)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Iterating forked event")
+	for iteratorForForkedEvent.Next() {
+		e := iteratorForForkedEvent.Event
+		if e != nil {
+			queue.push(&rootchainEvent{raw: e.Raw, event: e})
+		}
+	}
+
+	// handle confirmed past events in the order they were emitted. Others are
+	// handled after confirmations.
+	for _, e := range queue.pop(headNumber) {
+		if err := rcm.handleRootChainEvent(e); err != nil {
+			log.Error("Failed to handle past RootChain events", "blockNumber", e.raw.BlockNumber, "err", err)
 		}
 	}
 
+	startBlockNumber = headNumber + 1
 	watchOpts := &bind.WatchOpts{
 		Context: context.Background(),
 		Start:   &startBlockNumber,
 	}
+	headCh := make(chan *types.Header)
 	epochPrepareWatchCh := make(chan *rootchain.RootChainEpochPrepared)
 	blockFinalizedWatchCh := make(chan *rootchain.RootChainBlockFinalized)
+	forkedWatchCh := make(chan *rootchain.RootChainForked)
+
+	log.Info("Watching root chain head", "confirmations", rcm.config.RootChainConfirmations)
+	headSub, err := rcm.backend.SubscribeNewHead(context.Background(), headCh)
+	if err != nil {
+		return err
+	}
 
 	log.Info("Watching epoch prepared event", "startBlockNumber", startBlockNumber)
 	epochPrepareSub, err := filterer.WatchEpochPrepared(watchOpts, epochPrepareWatchCh)
@@ -250,6 +364,12 @@ func (rcm *RootChainManager) watchEvents() error {
 		return err
 	}
 
+	log.Info("Watching forked event", "startBlockNumber", startBlockNumber)
+	forkedSub, err := filterer.WatchForked(watchOpts, forkedWatchCh)
+	if err != nil {
+		return err
+	}
+
 	resubTimer := time.NewTimer(0)
 	<-resubTimer.C
 	resub := func() {
@@ -257,12 +377,21 @@ func (rcm *RootChainManager) watchEvents() error {
 			return
 		}
 
-		startBlockNumber := rcm.blockchain.GetRootchainBlockNumber() + 1
+		startBlockNumber := rcm.rootchainCursor().Number + 1
 		watchOpts := &bind.WatchOpts{
 			Context: context.Background(),
 			Start:   &startBlockNumber,
 		}
 
+		log.Info("Re-subscribe root chain head")
+		headSub2, err := rcm.backend.SubscribeNewHead(context.Background(), headCh)
+		if err != nil {
+			log.Error("Failed to re-subscribe head", "err", err)
+			resubTimer.Reset(5 * time.Second)
+			return
+		}
+		headSub = headSub2
+
 		log.Info("Re-subsribe EpochPrepared event", "startBlockNumber", startBlockNumber)
 		epochPrepareSub2, err := filterer.WatchEpochPrepared(watchOpts, epochPrepareWatchCh)
 		if err != nil {
@@ -281,6 +410,69 @@ func (rcm *RootChainManager) watchEvents() error {
 		}
 
 		blockFinalizedSub = blockFinalizedSub2
+
+		log.Info("Watching forked event", "startBlockNumber", startBlockNumber)
+		forkedSub2, err := filterer.WatchForked(watchOpts, forkedWatchCh)
+		if err != nil {
+			log.Error("Failed to re-subscribe event", "err", err)
+			resubTimer.Reset(5 * time.Second)
+			return
+		}
+
+		forkedSub = forkedSub2
+	}
+
+	// flush dispatches events confirmed at the root chain head. Events
+	// removed from the canonical chain are dropped. If an event cannot be
+	// checked, it and the following events are queued again to retry at the
+	// next head.
+	flush := func() {
+		events := queue.pop(headNumber)
+		for i, e := range events {
+			canonical, err := rcm.isCanonicalEvent(e)
+			if err != nil {
+				log.Error("Failed to check root chain event", "blockNumber", e.raw.BlockNumber, "err", err)
+				for _, e := range events[i:] {
+					queue.push(e)
+				}
+				return
+			}
+			if !canonical {
+				log.Warn("Root chain event is reorganized", "blockNumber", e.raw.BlockNumber, "blockHash", e.raw.BlockHash)
+				continue
+			}
+			rcm.dispatchRootChainEvent(e)
+		}
+	}
+
+	// enqueue adds the event to the queue. If the event is removed by root
+	// chain reorg, it is dropped from the queue or epochs based on it are
+	// reverted.
+	enqueue := func(e *rootchainEvent) {
+		if e.raw.Removed {
+			if queue.remove(e) {
+				log.Info("Pending root chain event is removed", "blockNumber", e.raw.BlockNumber, "blockHash", e.raw.BlockHash)
+				return
+			}
+
+			if e.raw.BlockNumber <= rcm.rootchainCursor().Number {
+				if err := rcm.revertRootChainBlocks(e.raw.BlockNumber); err != nil {
+					log.Error("Failed to revert root chain blocks", "number", e.raw.BlockNumber, "err", err)
+				}
+			}
+			return
+		}
+
+		if !queue.push(e) {
+			return
+		}
+
+		// the event may be notified before the root chain head. It is
+		// dispatched right away if confirmations are not required.
+		if e.raw.BlockNumber > headNumber {
+			headNumber = e.raw.BlockNumber
+		}
+		flush()
 	}
 
 	// TODO: wait untli previous submit transaction is mined.
@@ -288,9 +480,22 @@ func (rcm *RootChainManager) watchEvents() error {
 	go func() {
 		for {
 			select {
+			case h := <-headCh:
+				if h != nil {
+					headNumber = h.Number.Uint64()
+					flush()
+					rcm.updateRootChainHead(headNumber)
+				}
+
+			case err := <-headSub.Err():
+				if err != nil {
+					log.Error("Root chain head subscription error", "err", err)
+					resub()
+				}
+
 			case e := <-epochPrepareWatchCh:
 				if e != nil {
-					rcm.epochPreparedCh <- e
+					enqueue(&rootchainEvent{raw: e.Raw, event: e})
 				}
 
 			case err := <-epochPrepareSub.Err():
@@ -301,7 +506,7 @@ func (rcm *RootChainManager) watchEvents() error {
 
 			case e := <-blockFinalizedWatchCh:
 				if e != nil {
-					rcm.blockFinalizedCh <- e
+					enqueue(&rootchainEvent{raw: e.Raw, event: e})
 				}
 
 			case err := <-blockFinalizedSub.Err():
@@ -309,7 +514,17 @@ func (rcm *RootChainManager) watchEvents() error {
 					log.Error("Block finalized event subscription error", "err", err)
 					resub()
 				}
-				return
+
+			case e := <-forkedWatchCh:
+				if e != nil {
+					enqueue(&rootchainEvent{raw: e.Raw, event: e})
+				}
+
+			case err := <-forkedSub.Err():
+				if err != nil {
+					log.Error("Forked event subscription error", "err", err)
+					resub()
+				}
 
 			case <-rcm.quit:
 				closed = true
@@ -334,6 +549,38 @@ func makePos(v1 *big.Int, v2 *big.Int) *big.Int {
 	return new(big.Int).Add(a, v2)
 }
 
+// splitPos returns the two values of the position made by makePos.
+func splitPos(pos *big.Int) (*big.Int, *big.Int) {
+	v1 := new(big.Int).Rsh(pos, 128)
+	return v1, new(big.Int).Sub(pos, new(big.Int).Lsh(v1, 128))
+}
+
+// submitNRE adds the submit transaction of the completed NRE in the epoch
+// environment.
+//
+// NRE' is re-mined to keep the child chain on the new fork, but it is not
+// submitted. RootChain contract rejects rebased epochs in submitNRE and has no
+// other method to submit NRE' yet.
+func (rcm *RootChainManager) submitNRE() error {
+	if rcm.minerEnv.Rebase {
+		log.Warn("RootChain contract does not accept NRE', skip submission", "forkNumber", rcm.minerEnv.CurrentFork, "epochNumber", rcm.minerEnv.EpochNumber)
+		return nil
+	}
+
+	var blocks types.Blocks
+
+	st := time.Now()
+	s := rcm.minerEnv.StartBlockNumber.Uint64()
+	e := rcm.minerEnv.EndBlockNumber.Uint64()
+	for i := s; i <= e; i++ {
+		blocks = append(blocks, rcm.blockchain.GetBlockByNumber(i))
+	}
+	elapsed := time.Since(st)
+	log.Debug("Read blocks for NRE", "epochNumber", rcm.minerEnv.EpochNumber, "numBlocks", e-s+1, "elapsed", elapsed)
+
+	return rcm.addEpochSubmitTransaction(blocks)
+}
+
 func (rcm *RootChainManager) addEpochSubmitTransaction(blocks types.Blocks) error {
 	if rcm.config.NodeMode != ModeOperator {
 		return errors.New("only operator node can add submit transaction")
@@ -372,7 +619,11 @@ func (rcm *RootChainManager) addEpochSubmitTransaction(blocks types.Blocks) erro
 	caption := fmt.Sprintf("%s(%d: [%d-%d])", funcName, epochNumber.Uint64(), startBlockNumber.Uint64(), endBlockNumber.Uint64())
 	rawTx := tx.NewRawTransaction(operator.Address, params.SubmitBlockGasLimit, &rcm.config.RootChainContract, big.NewInt(int64(rcm.state.costNRB)), input, false, caption)
 
-	return rcm.txManager.Add(operator, rawTx, false)
+	if _, err := rcm.txManager.AddToPool(submitterPool, rawTx, submissionOrder(forkNumber)); err != nil {
+		return err
+	}
+	rcm.submissionAdded(rawTx)
+	return nil
 }
 
 func (rcm *RootChainManager) addBlockSubmitTransaction(block *types.Block) error {
@@ -402,11 +653,49 @@ func (rcm *RootChainManager) addBlockSubmitTransaction(block *types.Block) error
 	caption := fmt.Sprintf("%s(%d)", funcName, block.NumberU64())
 	rawTx := tx.NewRawTransaction(operator.Address, params.SubmitBlockGasLimit, &rcm.config.RootChainContract, big.NewInt(int64(rcm.state.costNRB)), input, false, caption)
 
-	return rcm.txManager.Add(operator, rawTx, false)
+	if _, err := rcm.txManager.AddToPool(submitterPool, rawTx, submissionOrder(forkNumber)); err != nil {
+		return err
+	}
+	rcm.submissionAdded(rawTx)
+	return nil
+}
+
+func (rcm *RootChainManager) addURBSubmitTransaction(block *types.Block) error {
+	if !rcm.isURBSubmitter() {
+		return errors.New("only URB submitter node can add URB submit transaction")
+	}
+
+	submitter := rcm.urbSubmitter()
+	funcName := "submitURB"
+
+	forkNumber := new(big.Int).Set(rcm.minerEnv.CurrentFork)
+
+	pos := makePos(forkNumber, block.Number())
+
+	input, err := rootchainContractABI.Pack(
+		funcName,
+		pos,
+		block.Header().Root,
+		block.Header().TxHash,
+		block.Header().ReceiptHash,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	caption := fmt.Sprintf("%s(%d: %d)", funcName, forkNumber.Uint64(), block.NumberU64())
+	rawTx := tx.NewRawTransaction(submitter.Address, params.SubmitBlockGasLimit, &rcm.config.RootChainContract, big.NewInt(int64(rcm.state.costURB)), input, false, caption)
+
+	if err := rcm.txManager.Add(submitter, rawTx, false); err != nil {
+		return err
+	}
+	rcm.submissionAdded(rawTx)
+	return nil
 }
 
 func (rcm *RootChainManager) runSubmitter() {
-	if rcm.config.NodeMode != ModeOperator {
+	if rcm.config.NodeMode != ModeOperator && !rcm.isURBSubmitter() {
 		return
 	}
 
@@ -427,30 +716,45 @@ func (rcm *RootChainManager) runSubmitter() {
 			rcm.miner.Stop()
 		}
 
-		bal, err := rcm.backend.BalanceAt(context.Background(), rcm.config.Operator.Address, nil)
+		// only URBs are submitted by URB submitter.
+		if rcm.config.NodeMode != ModeOperator && !rcm.minerEnv.UserActivated {
+			return nil
+		}
+
+		submitter := rcm.config.Operator
+		if rcm.minerEnv.UserActivated {
+			submitter = rcm.urbSubmitter()
+		}
+
+		bal, err := rcm.backend.BalanceAt(context.Background(), submitter.Address, nil)
 		if err != nil {
-			log.Error("Failed to get balance of operator account from rootchain", "err", err)
+			log.Error("Failed to get balance of submitter account from rootchain", "err", err)
 		}
 
-		if bal.Cmp(rcm.config.OperatorMinEther) < 0 {
-			log.Warn("Operator account balance on rootchain is too low")
+		if bal != nil && submitter == rcm.config.Operator {
+			updateBalanceGauge(bal)
 		}
 
-		if rcm.minerEnv.IsRequest {
+		if bal != nil && bal.Cmp(rcm.config.OperatorMinEther) < 0 {
+			log.Warn("Submitter account balance on rootchain is too low", "address", submitter.Address)
+		}
+
+		if rcm.minerEnv.UserActivated {
+			err = rcm.addURBSubmitTransaction(block)
+		} else if rcm.minerEnv.IsRequest {
 			err = rcm.addBlockSubmitTransaction(block)
 		} else if !rcm.minerEnv.IsRequest && rcm.minerEnv.Completed {
-			var blocks types.Blocks
-
-			st := time.Now()
-			s := rcm.minerEnv.StartBlockNumber.Uint64()
-			e := rcm.minerEnv.EndBlockNumber.Uint64()
-			for i := s; i <= e; i++ {
-				blocks = append(blocks, rcm.blockchain.GetBlockByNumber(i))
+			// speculative NRE is submitted after it is prepared in root chain.
+			if rcm.isSpeculativeEpoch(rcm.minerEnv.CurrentFork, rcm.minerEnv.EpochNumber) {
+				log.Info("Speculative NRE is completed, waiting for the epoch to be prepared", "epochNumber", rcm.minerEnv.EpochNumber)
+				return nil
 			}
-			elapsed := time.Since(st)
-			log.Debug("Read blocks for NRE", "epochNumber", rcm.minerEnv.EpochNumber, "numBlocks", e-s+1, "elapsed", elapsed)
 
-			err = rcm.addEpochSubmitTransaction(blocks)
+			err = rcm.submitNRE()
+
+			if err == nil && rcm.config.OperatorPipeline {
+				go rcm.startSpeculativeNRE()
+			}
 		} else {
 			log.Info("Non-request epoch is not completed yet", "epochNumber", rcm.minerEnv.EpochNumber)
 			return nil
@@ -458,11 +762,14 @@ func (rcm *RootChainManager) runSubmitter() {
 
 		if err == tx.ErrDuplicateRaw {
 			log.Error("Same block submit transaction was included.")
-			return nil
 		} else if err != nil {
 			return err
 		}
 
+		if rcm.minerEnv.IsRequest {
+			rcm.checkpointSubmitted(block.NumberU64())
+		}
+
 		return nil
 	}
 
@@ -485,23 +792,25 @@ func (rcm *RootChainManager) runSubmitter() {
 }
 
 func (rcm *RootChainManager) runHandlers() {
-	if rcm.config.NodeMode != ModeOperator {
-		return
-	}
-
 	for {
 		select {
 		case e := <-rcm.epochPreparedCh:
 			if err := rcm.handleEpochPrepared(e); err != nil {
 				log.Error("Failed to handle epoch prepared", "err", err)
 			} else {
-				rcm.blockchain.SetRootchainBlockNumber(e.Raw.BlockNumber)
+				rcm.updateRootChainCursor(e.Raw, e)
 			}
 		case e := <-rcm.blockFinalizedCh:
 			if err := rcm.handleBlockFinalized(e); err != nil {
 				log.Error("Failed to handle block finazlied", "err", err)
 			} else {
-				rcm.blockchain.SetRootchainBlockNumber(e.Raw.BlockNumber)
+				rcm.updateRootChainCursor(e.Raw, nil)
+			}
+		case e := <-rcm.forkedCh:
+			if err := rcm.handleForked(e); err != nil {
+				log.Error("Failed to handle forked", "err", err)
+			} else {
+				rcm.updateRootChainCursor(e.Raw, nil)
 			}
 		case <-rcm.quit:
 			return
@@ -520,13 +829,77 @@ func (rcm *RootChainManager) handleEpochPrepared(ev *rootchain.RootChainEpochPre
 		return errors.New(fmt.Sprintf("EpochPrepared#%s event is removed. root chain would had been reorganized.", ev.EpochNumber.String()))
 	}
 
-	// Short circuit if epoch prepared event is fired due to reorg.
-	if rcm.minerEnv.EpochNumber.Cmp(ev.EpochNumber) >= 0 {
-		return errors.New(fmt.Sprintf("Epoch#%s is less than current epoch#%s.", ev.EpochNumber.String(), rcm.minerEnv.EpochNumber.String()))
+	// Adopt or discard the NRE mined before it is prepared.
+	if handled, err := rcm.reconcileSpeculativeNRE(ev); handled || err != nil {
+		return err
+	}
+
+	// Resume the request epoch if it was interrupted while the node mined it.
+	cp := rcm.resumableCheckpoint(ev)
+
+	if cp == nil {
+		// Short circuit if epoch prepared event is fired due to reorg.
+		if c := rcm.minerEnv.CurrentFork.Cmp(ev.ForkNumber); c > 0 || c == 0 && rcm.minerEnv.EpochNumber.Cmp(ev.EpochNumber) >= 0 {
+			return errors.New(fmt.Sprintf("Epoch#%s of fork#%s is less than current epoch#%s of fork#%s.", ev.EpochNumber.String(), ev.ForkNumber.String(), rcm.minerEnv.EpochNumber.String(), rcm.minerEnv.CurrentFork.String()))
+		}
+
+		// keep epoch environment to revert the epoch if root chain is reorganized.
+		rawdb.WriteEpochEnvSnapshot(rcm.db, ev.ForkNumber.Uint64(), ev.EpochNumber.Uint64(), rcm.minerEnv)
+	} else {
+		log.Info("Resuming request epoch", "forkNumber", cp.ForkNumber, "epochNumber", cp.EpochNumber, "numBlocks", len(cp.RequestBlockIds), "numMined", cp.NumMined, "numSubmitted", cp.NumSubmitted)
 	}
 
 	e := *ev
 
+	// URBs are mined by URB submitter. Other nodes follow the new fork when
+	// Forked event is fired.
+	if e.UserActivated {
+		log.Info("RootChain URE prepared", "forkNumber", e.ForkNumber, "epochNumber", e.EpochNumber, "startBlockNumber", e.StartBlockNumber, "endBlockNumber", e.EndBlockNumber)
+
+		if !rcm.isURBSubmitter() {
+			return nil
+		}
+
+		if cp == nil {
+			if err := rcm.rollbackToFork(e.ForkNumber, e.StartBlockNumber); err != nil {
+				return err
+			}
+		}
+	}
+
+	var (
+		requestBlockIds []*big.Int
+		nrbsToRebase    []uint64
+		err             error
+	)
+
+	// end block number of ORE' and NRE' is 0 until the epoch is rebased. Use
+	// the number of blocks to rebase in the previous fork instead.
+	if cp != nil {
+		requestBlockIds = checkpointRequestBlockIds(cp)
+		e.EndBlockNumber = new(big.Int).Add(e.StartBlockNumber, big.NewInt(int64(len(requestBlockIds)-1)))
+	} else if e.Rebase && !e.EpochIsEmpty {
+		if e.IsRequest {
+			requestBlockIds, err = rcm.requestBlocksToRebase(e.ForkNumber)
+			if err != nil {
+				return err
+			}
+			e.EndBlockNumber = new(big.Int).Add(e.StartBlockNumber, big.NewInt(int64(len(requestBlockIds)-1)))
+			e.EpochIsEmpty = len(requestBlockIds) == 0
+		} else {
+			nrbsToRebase, err = rcm.nonRequestBlocksToRebase(e.ForkNumber)
+			if err != nil {
+				return err
+			}
+			e.EndBlockNumber = new(big.Int).Add(e.StartBlockNumber, big.NewInt(int64(len(nrbsToRebase)-1)))
+			e.EpochIsEmpty = len(nrbsToRebase) == 0
+		}
+
+		if head := rcm.blockchain.CurrentBlock().Number(); !e.EpochIsEmpty && new(big.Int).Add(head, big.NewInt(1)).Cmp(e.StartBlockNumber) != 0 {
+			return errors.New(fmt.Sprintf("Rebased epoch#%s starts at block#%s, but current block is #%s", e.EpochNumber.String(), e.StartBlockNumber.String(), head.String()))
+		}
+	}
+
 	length := new(big.Int).Add(new(big.Int).Sub(e.EndBlockNumber, e.StartBlockNumber), big.NewInt(1))
 
 	log.Info("RootChain epoch prepared",
@@ -546,11 +919,33 @@ func (rcm *RootChainManager) handleEpochPrepared(ev *rootchain.RootChainEpochPre
 		return nil
 	}
 
-	if rcm.config.NodeMode == ModeOperator {
-		go rcm.miner.Start(rcm.config.Operator.Address, &e, false)
+	if e.IsRequest && !e.Rebase && cp == nil {
+		epoch, err := rcm.getEpoch(e.ForkNumber, e.EpochNumber)
+		if err != nil {
+			return err
+		}
+		log.Debug("rcm.getEpoch", "epoch", epoch)
+
+		for i := uint64(0); i < length.Uint64(); i++ {
+			requestBlockIds = append(requestBlockIds, new(big.Int).SetUint64(epoch.RE.FirstRequestBlockId+i))
+		}
+	}
+
+	// re-inject transactions of NRBs in previous fork to be included in NRE'.
+	if !e.IsRequest && e.Rebase && rcm.config.NodeMode == ModeOperator {
+		rcm.enqueueRebaseTxs(nrbsToRebase)
+	}
+
+	if rcm.shouldMine(&e) {
+		if e.IsRequest && cp == nil {
+			rcm.writeRequestEpochCheckpoint(&e, requestBlockIds)
+		}
+
+		// resumed epoch continues with the epoch environment in database.
+		go rcm.miner.Start(rcm.miningAccount(&e).Address, &e, cp != nil)
 	}
 
-	// prepare request tx for ORBs
+	// prepare request tx for ORBs and URBs
 	if e.IsRequest && !e.EpochIsEmpty {
 		events := rcm.eventMux.Subscribe(core.NewMinedBlockEvent{})
 		defer events.Unsubscribe()
@@ -558,89 +953,43 @@ func (rcm *RootChainManager) handleEpochPrepared(ev *rootchain.RootChainEpochPre
 		numORBs := new(big.Int).Sub(e.EndBlockNumber, e.StartBlockNumber)
 		numORBs = new(big.Int).Add(numORBs, big.NewInt(1))
 
-		bodies := make([]types.Transactions, 0, numORBs.Uint64()) // [][]types.Transaction
+		begin := time.Now()
 
-		currentFork := big.NewInt(int64(rcm.state.currentFork))
-		epoch, err := rcm.getEpoch(currentFork, e.EpochNumber)
+		// exit requests are not included in ORB'
+		bodies, indexes, err := rcm.fetchRequestBodies(requestBlockIds, e.UserActivated, e.Rebase)
 		if err != nil {
 			return err
 		}
-		log.Debug("rcm.getEpoch", "epoch", epoch)
-
-		// TODO: URE, ORE' should handle requestBlockId in a different way.
-		requestBlockId := big.NewInt(int64(epoch.RE.FirstRequestBlockId))
-
-		log.Debug("Num Orbs", "epochNumber", e.EpochNumber, "numORBs", numORBs, "requestBlockId", requestBlockId, "e.EndBlockNumber", e.EndBlockNumber, "e.StartBlockNumber", e.StartBlockNumber)
-		for blockNumber := e.StartBlockNumber; blockNumber.Cmp(e.EndBlockNumber) <= 0; {
-			begin := time.Now()
-
-			orb, err := rcm.rootchainContract.ORBs(baseCallOpt, requestBlockId)
-			if err != nil {
-				return err
-			}
-
-			numRequests := orb.RequestEnd - orb.RequestStart + 1
-			log.Debug("Fetching ORB", "blockNumber", blockNumber, "requestStart", orb.RequestStart, "requestEnd", orb.RequestEnd, "requestBlockId", requestBlockId)
-
-			body := make(types.Transactions, 0, numRequests)
-
-			for requestId := orb.RequestStart; requestId <= orb.RequestEnd; {
-				request, err := rcm.rootchainContract.EROs(baseCallOpt, big.NewInt(int64(requestId)))
-				if err != nil {
-					return err
-				}
-
-				log.Debug("Request fetched", "requestId", requestId, "hash", common.Bytes2Hex(request.Hash[:]), "request", request)
-
-				var (
-					to    common.Address
-					value *big.Int
-					input []byte
-				)
-
-				if request.IsTransfer && !request.IsExit {
-					to = request.Requestor
-					value = new(big.Int).SetBytes(request.TrieValue[:])
-				} else {
-					to, _ = rcm.rootchainContract.RequestableContracts(baseCallOpt, request.To)
-					value = request.Value
-					input, err = requestableContractABI.Pack("applyRequestInChildChain",
-						request.IsExit,
-						big.NewInt(int64(requestId)),
-						request.Requestor,
-						request.TrieKey,
-						request.TrieValue,
-					)
-					if err != nil {
-						log.Error("Failed to pack applyRequestInChildChain", "err", err)
-					}
 
-					log.Debug("Request tx.data", "payload", common.Bytes2Hex(input))
-				}
-
-				requestTx := types.NewTransaction(0, to, value, params.RequestTxGasLimit, params.RequestTxGasPrice, input)
+		for i, body := range bodies {
+			blockNumber := new(big.Int).Add(e.StartBlockNumber, big.NewInt(int64(i)))
 
-				log.Debug("Request Transaction", "tx", requestTx)
-
-				body = append(body, requestTx)
-				requestId += 1
+			for _, ri := range indexes[i] {
+				ri.ForkNumber = e.ForkNumber.Uint64()
+				ri.BlockNumber = blockNumber.Uint64()
+				rawdb.WriteRequestIndex(rcm.db, ri)
 			}
+			rcm.setRequestTxs(blockNumber.Uint64(), body)
 
-			log.Info("Request txs fetched", "blockNumber", blockNumber, "requestBlockId", requestBlockId, "numRequests", len(body), "elapsed", time.Since(begin))
-
-			bodies = append(bodies, body)
-
-			blockNumber = new(big.Int).Add(blockNumber, big.NewInt(1))
-			requestBlockId = new(big.Int).Add(requestBlockId, big.NewInt(1))
+			log.Info("Request txs fetched", "blockNumber", blockNumber, "requestBlockId", requestBlockIds[i], "numRequests", len(body))
 		}
 
+		log.Info("Request blocks fetched", "numORBs", len(bodies), "elapsed", time.Since(begin))
+
 		var numMinedORBs uint64 = 0
 
-		// TODO: handle ModeUser
-		if rcm.config.NodeMode != ModeOperator {
+		// Other nodes verify request blocks from operator against the bodies.
+		if !rcm.shouldMine(&e) {
 			return nil
 		}
 
+		if cp != nil {
+			if err := rcm.resubmitRequestBlocks(cp); err != nil {
+				return err
+			}
+			numMinedORBs = cp.NumMined
+		}
+
 		// Unlock mutex and make submit loop to process
 		rcm.lock.Unlock()
 		for numMinedORBs < numORBs.Uint64() {
@@ -659,6 +1008,8 @@ func (rcm *RootChainManager) handleEpochPrepared(ev *rootchain.RootChainEpochPre
 				return errors.New("Invalid request block type.")
 			}
 
+			rcm.checkpointMined(block.NumberU64())
+
 			receipts := rcm.blockchain.GetReceiptsByHash(block.Hash())
 
 			for _, receipt := range receipts {
@@ -677,71 +1028,610 @@ func (rcm *RootChainManager) handleEpochPrepared(ev *rootchain.RootChainEpochPre
 	return nil
 }
 
-// Challenge on invalid exits
-func (rcm *RootChainManager) handleBlockFinalized(ev *rootchain.RootChainBlockFinalized) error {
-	rcm.lock.Lock()
-	defer rcm.lock.Unlock()
+// isURBSubmitter returns true if the node builds and submits URBs. Nodes with
+// challenger account submit URBs on behalf of users when operator withholds
+// blocks, and user nodes submit them with the configured URB submitter account.
+func (rcm *RootChainManager) isURBSubmitter() bool {
+	switch rcm.config.NodeMode {
+	case ModeChallenger:
+		return true
+	case ModeUser:
+		return rcm.config.URBSubmitter.Address != (common.Address{})
+	}
+	return false
+}
 
-	e := *ev
+// urbSubmitter returns the account to prepare and submit URBs.
+func (rcm *RootChainManager) urbSubmitter() accounts.Account {
+	if rcm.config.NodeMode == ModeUser {
+		return rcm.config.URBSubmitter
+	}
+	return rcm.config.Challenger
+}
 
-	log.Info("RootChain block finalized", "forkNumber", e.ForkNumber, "blockNubmer", e.BlockNumber)
+// shouldMine returns true if the node mines blocks of the epoch.
+func (rcm *RootChainManager) shouldMine(e *rootchain.RootChainEpochPrepared) bool {
+	if e.UserActivated {
+		return rcm.isURBSubmitter()
+	}
+	return rcm.config.NodeMode == ModeOperator
+}
 
-	callerOpts := &bind.CallOpts{
-		Pending: true,
-		Context: context.Background(),
+// miningAccount returns the account to submit blocks of the epoch.
+func (rcm *RootChainManager) miningAccount(e *rootchain.RootChainEpochPrepared) accounts.Account {
+	if e.UserActivated {
+		return rcm.urbSubmitter()
 	}
+	return rcm.config.Operator
+}
 
-	w, err := rcm.accountManager.Find(rcm.config.Challenger)
+// fetchRequestTxs returns request transactions of the request block (ORB or
+// URB) and request indexes linking the requests to the transactions. If
+// skipExit is true, exit requests are excluded as in ORB'.
+func (rcm *RootChainManager) fetchRequestTxs(requestBlockId *big.Int, userActivated, skipExit bool) (types.Transactions, []*rawdb.RequestIndex, error) {
+	bodies, indexes, err := rcm.fetchRequestBodies([]*big.Int{requestBlockId}, userActivated, skipExit)
 	if err != nil {
-		log.Error("Failed to get challenger account", "err", err)
-		return err
+		return nil, nil, err
+	}
+	return bodies[0], indexes[0], nil
+}
+
+// fetchRequestBodies returns request transactions and request indexes of the
+// request blocks. Request blocks, requests and requestable contracts are
+// fetched in batches rather than one by one.
+func (rcm *RootChainManager) fetchRequestBodies(requestBlockIds []*big.Int, userActivated, skipExit bool) ([]types.Transactions, [][]*rawdb.RequestIndex, error) {
+	if len(requestBlockIds) == 0 {
+		return nil, nil, nil
+	}
+
+	rbs, err := rcm.requestFetcher.requestBlocks(userActivated, requestBlockIds)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	block, err := rcm.rootchainContract.GetBlock(callerOpts, e.ForkNumber, e.BlockNumber)
+	// request ids in request blocks are continuous.
+	start, end := rbs[0].RequestStart, rbs[len(rbs)-1].RequestEnd
+	for _, rb := range rbs {
+		log.Debug("Fetching ORB", "requestStart", rb.RequestStart, "requestEnd", rb.RequestEnd, "userActivated", userActivated)
+		if rb.RequestStart < start {
+			start = rb.RequestStart
+		}
+		if rb.RequestEnd > end {
+			end = rb.RequestEnd
+		}
+	}
+
+	requests, err := rcm.requestFetcher.fetchRequests(userActivated, start, end)
 	if err != nil {
+		return nil, nil, err
+	}
+
+	var addrs []common.Address
+	for _, request := range requests {
+		if !request.IsTransfer || request.IsExit {
+			addrs = append(addrs, request.To)
+		}
+	}
+
+	mappings, err := rcm.requestFetcher.requestableContracts(addrs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bodies := make([]types.Transactions, len(rbs))
+	indexes := make([][]*rawdb.RequestIndex, len(rbs))
+
+	for i, rb := range rbs {
+		numRequests := rb.RequestEnd - rb.RequestStart + 1
+
+		bodies[i] = make(types.Transactions, 0, numRequests)
+		indexes[i] = make([]*rawdb.RequestIndex, 0, numRequests)
+
+		for requestId := rb.RequestStart; requestId <= rb.RequestEnd; requestId++ {
+			request := requests[requestId-start]
+
+			log.Debug("Request fetched", "requestId", requestId, "hash", common.Bytes2Hex(request.Hash[:]), "request", request)
+
+			if skipExit && request.IsExit {
+				continue
+			}
+
+			requestTx := rcm.makeRequestTx(requestId, request, mappings)
+
+			log.Debug("Request Transaction", "tx", requestTx)
+
+			requestType := rawdb.RequestTypeEnter
+			if request.IsExit {
+				requestType = rawdb.RequestTypeExit
+			} else if request.IsTransfer {
+				requestType = rawdb.RequestTypeTransfer
+			}
+
+			indexes[i] = append(indexes[i], &rawdb.RequestIndex{
+				RequestId:      requestId,
+				UserActivated:  userActivated,
+				Requestor:      request.Requestor,
+				To:             request.To,
+				Type:           requestType,
+				RequestBlockId: requestBlockIds[i].Uint64(),
+				Index:          uint64(len(bodies[i])),
+				TxHash:         requestTx.Hash(),
+			})
+
+			bodies[i] = append(bodies[i], requestTx)
+		}
+	}
+
+	return bodies, indexes, nil
+}
+
+// makeRequestTx returns the request transaction applying the request in child
+// chain. mappings are the requestable contracts in child chain.
+func (rcm *RootChainManager) makeRequestTx(requestId uint64, request *rootchainRequest, mappings map[common.Address]common.Address) *types.Transaction {
+	var (
+		to    common.Address
+		value *big.Int
+		input []byte
+		err   error
+	)
+
+	if request.IsTransfer && !request.IsExit {
+		to = request.Requestor
+		value = new(big.Int).SetBytes(request.TrieValue[:])
+	} else {
+		to = mappings[request.To]
+		value = request.Value
+		input, err = requestableContractABI.Pack("applyRequestInChildChain",
+			request.IsExit,
+			big.NewInt(int64(requestId)),
+			request.Requestor,
+			request.TrieKey,
+			request.TrieValue,
+		)
+		if err != nil {
+			log.Error("Failed to pack applyRequestInChildChain", "err", err)
+		}
+
+		log.Debug("Request tx.data", "payload", common.Bytes2Hex(input))
+	}
+
+	return types.NewTransaction(0, to, value, params.RequestTxGasLimit, params.RequestTxGasPrice, input)
+}
+
+// handleForked rolls back the plasma chain to the forked block so that URBs
+// and rebased epochs (ORE', NRE') are built on top of the new fork.
+func (rcm *RootChainManager) handleForked(ev *rootchain.RootChainForked) error {
+	rcm.lock.Lock()
+	defer rcm.lock.Unlock()
+
+	if ev.Raw.Removed {
+		return errors.New(fmt.Sprintf("Forked#%s event is removed. root chain would had been reorganized.", ev.NewFork.String()))
+	}
+
+	e := *ev
+
+	log.Info("RootChain forked", "newFork", e.NewFork, "epochNumber", e.EpochNumber, "forkedBlockNumber", e.ForkedBlockNumber)
+
+	return rcm.rollbackToFork(e.NewFork, e.ForkedBlockNumber)
+}
+
+// rollbackToFork sets the head of plasma chain to the block before the forked
+// block and switches current fork. Transactions in the rolled back NRBs are
+// kept to be included in NRE' again.
+func (rcm *RootChainManager) rollbackToFork(forkNumber, forkedBlockNumber *big.Int) error {
+	// Short circuit if the fork is already applied.
+	if rcm.minerEnv.CurrentFork.Cmp(forkNumber) >= 0 {
+		log.Info("Fork is already applied", "forkNumber", forkNumber, "currentFork", rcm.minerEnv.CurrentFork)
+		return nil
+	}
+
+	// speculative NRE of the previous fork is not prepared.
+	if err := rcm.discardSpeculativeNRE(); err != nil {
 		return err
 	}
 
-	if block.IsRequest {
-		invalidExits := rcm.invalidExits[e.ForkNumber.Uint64()][e.BlockNumber.Uint64()]
-		for i := 0; i < len(invalidExits); i++ {
+	rcm.miner.Stop()
+
+	head := rcm.blockchain.CurrentBlock().NumberU64()
+	forked := forkedBlockNumber.Uint64()
+
+	for i := forked; i <= head; i++ {
+		block := rcm.blockchain.GetBlockByNumber(i)
+		if block == nil {
+			break
+		}
+
+		if !block.IsRequest() {
+			rawdb.WriteRebaseTxs(rcm.db, i, block.Transactions())
+		}
+		rcm.deleteNullAddressTxs(i, rcm.nullAddressTxs[i])
+	}
+
+	if forked <= head {
+		if err := rcm.blockchain.SetHead(forked - 1); err != nil {
+			return err
+		}
+		log.Info("Plasma chain is rolled back", "from", head, "to", forked-1)
+	}
+
+	// request blocks of the new fork are verified against URBs and ORB's.
+	rcm.clearRequestTxs()
+	rcm.deleteRequestEpochCheckpoint()
+
+	rcm.state.lock.Lock()
+	rcm.state.currentFork = forkNumber.Uint64()
+	rcm.state.lock.Unlock()
+
+	rcm.minerEnv.SetCurrentFork(forkNumber)
+	rawdb.WriteEpochEnv(rcm.db, rcm.minerEnv)
+
+	return nil
+}
+
+// requestBlocksToRebase returns request block ids of ORBs in previous fork
+// that have enter requests. The ORBs are included in ORE' of the fork.
+func (rcm *RootChainManager) requestBlocksToRebase(forkNumber *big.Int) ([]*big.Int, error) {
+	preFork := new(big.Int).Sub(forkNumber, big.NewInt(1))
+
+	epochNumber, err := rcm.forkedEpoch(preFork, true)
+	if err != nil {
+		return nil, err
+	}
 
-			var proofs []byte
-			for j := 0; j < len(invalidExits[i].proof); j++ {
-				proof := invalidExits[i].proof[j].Bytes()
-				proofs = append(proofs, proof...)
+	var requestBlockIds []*big.Int
+
+	for ; ; epochNumber = new(big.Int).Add(epochNumber, big.NewInt(2)) {
+		epoch, err := rcm.getEpoch(preFork, epochNumber)
+		if err != nil {
+			return nil, err
+		}
+
+		if !epoch.Initialized {
+			break
+		}
+
+		if !epoch.IsRequest || epoch.UserActivated || epoch.IsEmpty || epoch.RE.NumEnter == 0 {
+			continue
+		}
+
+		for blockNumber := epoch.StartBlockNumber; blockNumber <= epoch.EndBlockNumber; blockNumber++ {
+			requestBlockId := new(big.Int).SetUint64(epoch.RE.FirstRequestBlockId + blockNumber - epoch.StartBlockNumber)
+
+			// ORB' refers request block of the block in its previous fork.
+			if epoch.Rebase {
+				block, err := rcm.getBlock(preFork, new(big.Int).SetUint64(blockNumber))
+				if err != nil {
+					return nil, err
+				}
+				requestBlockId = new(big.Int).SetUint64(block.RequestBlockId)
 			}
-			// TODO: ChallengeExit receipt check
-			input, err := rootchainContractABI.Pack("challengeExit", e.ForkNumber, e.BlockNumber, big.NewInt(invalidExits[i].index), invalidExits[i].receipt.GetRlp(), proofs)
+
+			orb, err := rcm.rootchainContract.ORBs(baseCallOpt, requestBlockId)
 			if err != nil {
-				log.Error("Failed to pack challengeExit", "err", err)
+				return nil, err
 			}
 
-			nonce, err := rcm.backend.NonceAt(context.Background(), rcm.config.Challenger.Address, nil)
-			if err != nil {
-				log.Error("Failed to get challenger nonce", "err", err)
+			if orb.NumEnter > 0 {
+				requestBlockIds = append(requestBlockIds, requestBlockId)
 			}
+		}
+	}
 
-			// TODO: use tx.TransactionManager
-			challengeTx := types.NewTransaction(uint64(nonce), rcm.config.RootChainContract, big.NewInt(0), params.SubmitBlockGasLimit, params.SubmitBlockGasPrice, input)
+	return requestBlockIds, nil
+}
 
-			signedTx, err := w.SignTx(rcm.config.Challenger, challengeTx, big.NewInt(int64(rcm.config.RootChainNetworkID)))
-			if err != nil {
-				log.Error("Failed to sign challengeTx", "err", err)
-			}
+// nonRequestBlocksToRebase returns block numbers of submitted NRBs in previous
+// fork after the forked block. The NRBs are included in NRE' of the fork.
+func (rcm *RootChainManager) nonRequestBlocksToRebase(forkNumber *big.Int) ([]uint64, error) {
+	preFork := new(big.Int).Sub(forkNumber, big.NewInt(1))
+
+	fork, err := rcm.rootchainContract.Forks(baseCallOpt, preFork)
+	if err != nil {
+		return nil, err
+	}
 
-			err = rcm.backend.SendTransaction(context.Background(), signedTx)
+	epochNumber, err := rcm.forkedEpoch(preFork, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var blockNumbers []uint64
+
+	for ; ; epochNumber = new(big.Int).Add(epochNumber, big.NewInt(2)) {
+		epoch, err := rcm.getEpoch(preFork, epochNumber)
+		if err != nil {
+			return nil, err
+		}
+
+		if !epoch.Initialized || epoch.IsRequest || epoch.NRE.SubmittedAt == 0 {
+			break
+		}
+
+		start := epoch.StartBlockNumber
+		if start < fork.ForkedBlock {
+			start = fork.ForkedBlock
+		}
+
+		for blockNumber := start; blockNumber <= epoch.EndBlockNumber; blockNumber++ {
+			blockNumbers = append(blockNumbers, blockNumber)
+		}
+	}
+
+	return blockNumbers, nil
+}
+
+// forkedEpoch returns the first request (or non-request) epoch number at or
+// after the epoch of the forked block.
+func (rcm *RootChainManager) forkedEpoch(forkNumber *big.Int, isRequest bool) (*big.Int, error) {
+	fork, err := rcm.rootchainContract.Forks(baseCallOpt, forkNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if fork.ForkedBlock == 0 {
+		return nil, errors.New(fmt.Sprintf("fork#%s is not forked", forkNumber.String()))
+	}
+
+	block, err := rcm.getBlock(forkNumber, new(big.Int).SetUint64(fork.ForkedBlock))
+	if err != nil {
+		return nil, err
+	}
+
+	epochNumber := new(big.Int).SetUint64(block.EpochNumber)
+
+	epoch, err := rcm.getEpoch(forkNumber, epochNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if epoch.IsRequest != isRequest {
+		epochNumber = new(big.Int).Add(epochNumber, big.NewInt(1))
+	}
+
+	return epochNumber, nil
+}
+
+// enqueueRebaseTxs adds transactions in NRBs of previous fork into tx pool.
+func (rcm *RootChainManager) enqueueRebaseTxs(blockNumbers []uint64) {
+	for _, blockNumber := range blockNumbers {
+		txs := rawdb.ReadRebaseTxs(rcm.db, blockNumber)
+		if txs == nil {
+			log.Warn("Transactions of NRB to rebase are not found", "blockNumber", blockNumber)
+			continue
+		}
+
+		for i, err := range rcm.txPool.AddLocals(txs) {
 			if err != nil {
-				log.Error("Failed to send challengeTx", "err", err)
-			} else {
-				log.Info("challengeExit is submitted", "exit request number", invalidExits[i].index, "hash", signedTx.Hash().Hex())
+				log.Warn("Failed to add transaction to rebase", "blockNumber", blockNumber, "hash", txs[i].Hash(), "err", err)
 			}
 		}
+
+		rawdb.DeleteRebaseTxs(rcm.db, blockNumber)
+	}
+}
+
+// Challenge on invalid exits
+func (rcm *RootChainManager) handleBlockFinalized(ev *rootchain.RootChainBlockFinalized) error {
+	rcm.lock.Lock()
+	defer rcm.lock.Unlock()
+
+	e := *ev
+
+	log.Info("RootChain block finalized", "forkNumber", e.ForkNumber, "blockNubmer", e.BlockNumber)
+
+	rcm.checkChallenges()
+
+	return rcm.challengeInvalidExits(e.ForkNumber, e.BlockNumber)
+}
+
+// challengeInvalidExits adds challengeExit transactions for the invalid exits
+// detected in the finalized request block to the transaction manager.
+func (rcm *RootChainManager) challengeInvalidExits(forkNumber, blockNumber *big.Int) error {
+	var targets invalidExits
+	for _, ie := range rcm.invalidExits[forkNumber.Uint64()][blockNumber.Uint64()] {
+		if ie.Status == rawdb.InvalidExitDetected {
+			targets = append(targets, ie)
+		}
+	}
+
+	// Short circuit if there is no invalid exit to challenge.
+	if len(targets) == 0 {
+		return nil
+	}
+
+	callerOpts := &bind.CallOpts{
+		Pending: true,
+		Context: context.Background(),
+	}
+
+	block, err := rcm.rootchainContract.GetBlock(callerOpts, forkNumber, blockNumber)
+	if err != nil {
+		return err
+	}
+
+	if !block.IsRequest {
+		return nil
+	}
+
+	// Short circuit if exit challenge period is over.
+	if block.Finalized && block.FinalizedAt+rcm.state.cpExit <= uint64(time.Now().Unix()) {
+		for _, ie := range targets {
+			log.Warn("Exit challenge period is over", "forkNumber", ie.ForkNumber, "blockNumber", ie.BlockNumber, "index", ie.Index)
+			rcm.updateInvalidExit(ie, rawdb.InvalidExitExpired)
+		}
+		return nil
+	}
+
+	var requestStart uint64
+	requestBlockId := new(big.Int).SetUint64(block.RequestBlockId)
+	if block.UserActivated {
+		urb, err := rcm.rootchainContract.URBs(callerOpts, requestBlockId)
+		if err != nil {
+			return err
+		}
+		requestStart = urb.RequestStart
+	} else {
+		orb, err := rcm.rootchainContract.ORBs(callerOpts, requestBlockId)
+		if err != nil {
+			return err
+		}
+		requestStart = orb.RequestStart
+	}
+
+	challenger := rcm.config.Challenger
+	funcName := "challengeExit"
+
+	// exit can be challenged until exit challenge period is over after the
+	// block is finalized.
+	deadline := block.FinalizedAt + rcm.state.cpExit
+	if !block.Finalized {
+		deadline = block.Timestamp + rcm.state.cpComputation + rcm.state.cpExit
+	}
+
+	for _, ie := range targets {
+		var proofs []byte
+		for j := 0; j < len(ie.Proof); j++ {
+			proof := ie.Proof[j].Bytes()
+			proofs = append(proofs, proof...)
+		}
+		input, err := rootchainContractABI.Pack(funcName, forkNumber, blockNumber, new(big.Int).SetUint64(ie.Index), ie.Receipt.GetRlp(), proofs)
+		if err != nil {
+			log.Error("Failed to pack challengeExit", "err", err)
+			continue
+		}
+
+		ie.RequestId = requestStart + ie.Index
+		ie.UserActivated = block.UserActivated
+
+		caption := fmt.Sprintf("%s(fork#%d block#%d request#%d)", funcName, ie.ForkNumber, ie.BlockNumber, ie.RequestId)
+		rawTx := tx.NewRawTransaction(challenger.Address, params.SubmitBlockGasLimit, &rcm.config.RootChainContract, big.NewInt(0), input, false, caption)
+		rawTx.GasPriceStrategy = tx.DeadlineStrategy
+		rawTx.Deadline = deadline
+
+		err = rcm.txManager.Add(challenger, rawTx, false)
+		if err == tx.ErrDuplicateRaw {
+			log.Warn("Same challenge transaction was included", "caption", caption)
+		} else if err != nil {
+			log.Error("Failed to add challengeExit transaction", "caption", caption, "err", err)
+			continue
+		} else {
+			challengeSentCounter.Inc(1)
+		}
+
+		rcm.updateInvalidExit(ie, rawdb.InvalidExitChallengeSent)
 	}
 
 	return nil
 }
 
+// checkChallenges updates the status of invalid exits whose exit request is
+// marked as challenged in root chain.
+func (rcm *RootChainManager) checkChallenges() {
+	for _, blocks := range rcm.invalidExits {
+		for _, ies := range blocks {
+			for _, ie := range ies {
+				if ie.Status != rawdb.InvalidExitChallengeSent {
+					continue
+				}
+
+				var (
+					challenged bool
+					err        error
+				)
+
+				requestId := new(big.Int).SetUint64(ie.RequestId)
+				if ie.UserActivated {
+					request, err2 := rcm.rootchainContract.ERUs(baseCallOpt, requestId)
+					challenged, err = request.Challenged, err2
+				} else {
+					request, err2 := rcm.rootchainContract.EROs(baseCallOpt, requestId)
+					challenged, err = request.Challenged, err2
+				}
+
+				if err != nil {
+					log.Error("Failed to read exit request", "requestId", ie.RequestId, "err", err)
+					continue
+				}
+
+				if challenged {
+					log.Info("Invalid exit is challenged", "forkNumber", ie.ForkNumber, "blockNumber", ie.BlockNumber, "index", ie.Index, "requestId", ie.RequestId)
+					rcm.updateInvalidExit(ie, rawdb.InvalidExitChallengeMined)
+					continue
+				}
+
+				finalizedAt, err := rcm.rootchainContract.GetBlockFinalizedAt(baseCallOpt, new(big.Int).SetUint64(ie.ForkNumber), new(big.Int).SetUint64(ie.BlockNumber))
+				if err != nil {
+					continue
+				}
+
+				if finalizedAt.Uint64()+rcm.state.cpExit <= uint64(time.Now().Unix()) {
+					log.Error("Exit challenge period is over before challenge is mined", "forkNumber", ie.ForkNumber, "blockNumber", ie.BlockNumber, "index", ie.Index, "requestId", ie.RequestId)
+					rcm.updateInvalidExit(ie, rawdb.InvalidExitExpired)
+				}
+			}
+		}
+	}
+}
+
+// challengeFinalizedExits challenges invalid exits loaded from database whose
+// request block was finalized while the node was not running.
+func (rcm *RootChainManager) challengeFinalizedExits() {
+	rcm.lock.Lock()
+	defer rcm.lock.Unlock()
+
+	rcm.checkChallenges()
+
+	for fork, blocks := range rcm.invalidExits {
+		forkNumber := new(big.Int).SetUint64(fork)
+
+		lastFinalizedBlock, err := rcm.rootchainContract.GetLastFinalizedBlock(baseCallOpt, forkNumber)
+		if err != nil {
+			log.Error("Failed to get last finalized block", "forkNumber", fork, "err", err)
+			continue
+		}
+
+		for num := range blocks {
+			blockNumber := new(big.Int).SetUint64(num)
+			if blockNumber.Cmp(lastFinalizedBlock) > 0 {
+				continue
+			}
+
+			if err := rcm.challengeInvalidExits(forkNumber, blockNumber); err != nil {
+				log.Error("Failed to challenge invalid exits", "forkNumber", fork, "blockNumber", num, "err", err)
+			}
+		}
+	}
+}
+
+// loadInvalidExits reads invalid exits which are not resolved yet from database.
+func (rcm *RootChainManager) loadInvalidExits() {
+	n := 0
+	for _, ie := range rawdb.ReadAllInvalidExits(rcm.db) {
+		if ie.Status == rawdb.InvalidExitChallengeMined || ie.Status == rawdb.InvalidExitExpired {
+			continue
+		}
+
+		rcm.addInvalidExit(ie)
+		n++
+	}
+
+	if n > 0 {
+		log.Info("Previous invalid exits are loaded", "numInvalidExits", n)
+	}
+}
+
+func (rcm *RootChainManager) addInvalidExit(ie *rawdb.InvalidExit) {
+	if rcm.invalidExits[ie.ForkNumber] == nil {
+		rcm.invalidExits[ie.ForkNumber] = make(map[uint64]invalidExits)
+	}
+	rcm.invalidExits[ie.ForkNumber][ie.BlockNumber] = append(rcm.invalidExits[ie.ForkNumber][ie.BlockNumber], ie)
+}
+
+func (rcm *RootChainManager) updateInvalidExit(ie *rawdb.InvalidExit, status rawdb.InvalidExitStatus) {
+	ie.Status = status
+	rawdb.WriteInvalidExit(rcm.db, ie)
+}
+
 func (rcm *RootChainManager) runDetector() {
 	if rcm.config.NodeMode == ModeUser {
 		return
@@ -766,8 +1656,6 @@ func (rcm *RootChainManager) runDetector() {
 			block := ev.Data.(core.NewMinedBlockEvent).Block
 
 			if block.IsRequest() {
-				var invalidExitsList invalidExits
-
 				forkNumber, err := rcm.rootchainContract.CurrentFork(callerOpts)
 				if err != nil {
 					log.Warn("failed to get current fork number", "err", err)
@@ -775,30 +1663,31 @@ func (rcm *RootChainManager) runDetector() {
 					continue
 				}
 
-				// TODO: read and write to DB
-				if rcm.invalidExits[forkNumber.Uint64()] == nil {
-					rcm.invalidExits[forkNumber.Uint64()] = make(map[uint64]invalidExits)
-				}
-
 				receipts := rcm.blockchain.GetReceiptsByHash(block.Hash())
 
 				// TODO: should check if the request[i] is enter or exit request. Undo request will make posterior enter request.
 				for i := 0; i < len(receipts); i++ {
 					if receipts[i].Status == types.ReceiptStatusFailed {
-						invalidExit := &invalidExit{
-							forkNumber:  forkNumber,
-							blockNumber: block.Number(),
-							receipt:     receipts[i],
-							index:       int64(i),
-							proof:       types.GetMerkleProof(receipts, i),
+						// Skip already known invalid exit.
+						if rawdb.ReadInvalidExit(rcm.db, forkNumber.Uint64(), block.NumberU64(), uint64(i)) != nil {
+							continue
 						}
-						invalidExitsList = append(invalidExitsList, invalidExit)
+
+						invalidExit := &rawdb.InvalidExit{
+							ForkNumber:  forkNumber.Uint64(),
+							BlockNumber: block.NumberU64(),
+							Index:       uint64(i),
+							Receipt:     receipts[i],
+							Proof:       types.GetMerkleProof(receipts, i),
+							Status:      rawdb.InvalidExitDetected,
+						}
+						rawdb.WriteInvalidExit(rcm.db, invalidExit)
+						rcm.addInvalidExit(invalidExit)
+						invalidExitCounter.Inc(1)
 
 						log.Info("Invalid Exit Detected", "invalidExit", invalidExit, "forkNumber", forkNumber, "blockNumber", block.Number())
 					}
 				}
-				// TODO: read and write to DB
-				rcm.invalidExits[forkNumber.Uint64()][block.NumberU64()] = invalidExitsList
 			}
 			rcm.lock.Unlock()
 
@@ -808,6 +1697,291 @@ func (rcm *RootChainManager) runDetector() {
 	}
 }
 
+// runURBPreparer sends prepareToSubmitURB transaction if there are ERUs and
+// operator does not submit blocks during the withholding challenge period. The
+// transaction is sent only if RootChain contract accepts it.
+func (rcm *RootChainManager) runURBPreparer() {
+	if !rcm.isURBSubmitter() {
+		return
+	}
+
+	ticker := time.NewTicker(withholdingCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := rcm.prepareToSubmitURB(); err != nil {
+				log.Error("Failed to prepare to submit URB", "err", err)
+			}
+
+		case <-rcm.quit:
+			return
+		}
+	}
+}
+
+func (rcm *RootChainManager) prepareToSubmitURB() error {
+	currentFork, err := rcm.rootchainContract.CurrentFork(baseCallOpt)
+	if err != nil {
+		return err
+	}
+
+	fork, err := rcm.rootchainContract.Forks(baseCallOpt, currentFork)
+	if err != nil {
+		return err
+	}
+
+	// Short circuit if URE is already prepared.
+	if fork.ForkedBlock != 0 {
+		return nil
+	}
+
+	// ERUs before requestStart are already included in previous URE.
+	requestStart := uint64(0)
+	if currentFork.Sign() > 0 {
+		epoch, err := rcm.getEpoch(currentFork, new(big.Int).SetUint64(fork.FirstEpoch))
+		if err != nil {
+			return err
+		}
+		requestStart = epoch.RE.RequestEnd + 1
+	}
+
+	// ERUs getter reverts if there is no ERU to include.
+	eru, err := rcm.rootchainContract.ERUs(baseCallOpt, new(big.Int).SetUint64(requestStart))
+	if err != nil || eru.Timestamp == 0 {
+		return nil
+	}
+
+	block, err := rcm.getBlock(currentFork, new(big.Int).SetUint64(fork.LastBlock))
+	if err != nil {
+		return err
+	}
+
+	now := uint64(time.Now().Unix())
+	cpWithholding := rcm.state.cpWithholding
+
+	// Short circuit if operator submitted a block after the ERU is created, or
+	// the withholding challenge period is not over yet.
+	if block.Timestamp >= eru.Timestamp || eru.Timestamp+cpWithholding > now {
+		return nil
+	}
+
+	log.Warn("Operator is withholding blocks. Prepare URE", "forkNumber", currentFork, "lastBlock", fork.LastBlock, "requestStart", requestStart)
+
+	funcName := "prepareToSubmitURB"
+	input, err := rootchainContractABI.Pack(funcName)
+	if err != nil {
+		return err
+	}
+
+	submitter := rcm.urbSubmitter()
+	value := big.NewInt(int64(rcm.state.costURBPrepare))
+
+	// Short circuit if RootChain contract does not accept URBs, e.g., the
+	// current RootChain contract disables user-activated fork and reverts
+	// prepareToSubmitURB, not to spend gas for the reverted transaction.
+	if _, err := rcm.backend.EstimateGas(context.Background(), ethereum.CallMsg{
+		From:  submitter.Address,
+		To:    &rcm.config.RootChainContract,
+		Value: value,
+		Data:  input,
+	}); err != nil {
+		log.Warn("RootChain contract does not accept prepareToSubmitURB", "forkNumber", currentFork, "err", err)
+		return nil
+	}
+
+	caption := fmt.Sprintf("%s(fork#%d request#%d)", funcName, currentFork.Uint64(), requestStart)
+	rawTx := tx.NewRawTransaction(submitter.Address, params.SubmitBlockGasLimit, &rcm.config.RootChainContract, value, input, false, caption)
+
+	if err := rcm.txManager.Add(submitter, rawTx, false); err == tx.ErrDuplicateRaw {
+		log.Debug("prepareToSubmitURB transaction is already added", "forkNumber", currentFork)
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// runNullAddressDetector finds transactions from the null address in non-request
+// blocks and challenges them after the blocks are submitted to root chain.
+func (rcm *RootChainManager) runNullAddressDetector() {
+	if rcm.config.NodeMode != ModeChallenger {
+		return
+	}
+
+	chainEventCh := make(chan core.ChainEvent, 16)
+	chainEventSub := rcm.blockchain.SubscribeChainEvent(chainEventCh)
+	defer chainEventSub.Unsubscribe()
+
+	ticker := time.NewTicker(nullAddressCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case ev := <-chainEventCh:
+			rcm.detectNullAddressTxs(ev.Block)
+
+		case <-ticker.C:
+			rcm.challengeNullAddressTxs()
+
+		case <-chainEventSub.Err():
+			return
+
+		case <-rcm.quit:
+			return
+		}
+	}
+}
+
+// detectNullAddressTxs records transactions from the null address in the non-request block.
+func (rcm *RootChainManager) detectNullAddressTxs(block *types.Block) {
+	if block.IsRequest() {
+		return
+	}
+
+	txs := block.Transactions()
+	signer := types.MakeSigner(rcm.blockchain.Config(), block.Number())
+
+	var detected []*rawdb.NullAddressTx
+	for i, tx := range txs {
+		from, err := types.Sender(signer, tx)
+		if err != nil || from != params.NullAddress {
+			continue
+		}
+
+		txByte, err := rlp.EncodeToBytes(tx)
+		if err != nil {
+			log.Error("Failed to encode null address transaction", "hash", tx.Hash(), "err", err)
+			continue
+		}
+
+		natx := &rawdb.NullAddressTx{
+			BlockNumber: block.NumberU64(),
+			Index:       uint64(i),
+			TxByte:      txByte,
+			Proof:       types.GetMerkleProof(txs, i),
+		}
+		rawdb.WriteNullAddressTx(rcm.db, natx)
+		detected = append(detected, natx)
+
+		log.Warn("Null address transaction detected in non-request block", "blockNumber", block.Number(), "index", i, "hash", tx.Hash())
+	}
+
+	if len(detected) == 0 {
+		return
+	}
+
+	rcm.lock.Lock()
+	rcm.nullAddressTxs[block.NumberU64()] = detected
+	rcm.lock.Unlock()
+}
+
+// challengeNullAddressTxs adds challengeNullAddress transactions to the transaction
+// manager for the submitted blocks in computation challenge period.
+func (rcm *RootChainManager) challengeNullAddressTxs() {
+	rcm.lock.Lock()
+	defer rcm.lock.Unlock()
+
+	if len(rcm.nullAddressTxs) == 0 {
+		return
+	}
+
+	forkNumber, err := rcm.rootchainContract.CurrentFork(baseCallOpt)
+	if err != nil {
+		log.Error("Failed to get current fork number", "err", err)
+		return
+	}
+
+	challenger := rcm.config.Challenger
+	funcName := "challengeNullAddress"
+
+	for num, natxs := range rcm.nullAddressTxs {
+		blockNumber := new(big.Int).SetUint64(num)
+
+		block, err := rcm.getBlock(forkNumber, blockNumber)
+		if err != nil {
+			log.Error("Failed to get plasma block", "blockNumber", num, "err", err)
+			continue
+		}
+
+		// Wait until the block is submitted.
+		if block.Timestamp == 0 {
+			continue
+		}
+
+		if block.IsRequest || block.Timestamp+rcm.state.cpComputation <= uint64(time.Now().Unix()) {
+			log.Error("Computation challenge period is over", "blockNumber", num, "numNullAddressTxs", len(natxs))
+			rcm.deleteNullAddressTxs(num, natxs)
+			continue
+		}
+
+		// keep the transactions failed to be challenged to retry them later.
+		var sent, failed []*rawdb.NullAddressTx
+		for _, natx := range natxs {
+			// The transactions root is a binary merkle tree, so the branch mask is
+			// the index of the leaf and the siblings are the merkle proof.
+			key, _ := rlp.EncodeToBytes(uint(natx.Index))
+			siblings := make([][32]byte, len(natx.Proof))
+			for i, p := range natx.Proof {
+				siblings[i] = p
+			}
+
+			input, err := rootchainContractABI.Pack(funcName, blockNumber, key, natx.TxByte, new(big.Int).SetUint64(natx.Index), siblings)
+			if err != nil {
+				log.Error("Failed to pack challengeNullAddress", "err", err)
+				failed = append(failed, natx)
+				continue
+			}
+
+			caption := fmt.Sprintf("%s(block#%d tx#%d)", funcName, num, natx.Index)
+			rawTx := tx.NewRawTransaction(challenger.Address, params.SubmitBlockGasLimit, &rcm.config.RootChainContract, big.NewInt(0), input, false, caption)
+			rawTx.GasPriceStrategy = tx.DeadlineStrategy
+			rawTx.Deadline = block.Timestamp + rcm.state.cpComputation
+
+			if err := rcm.txManager.Add(challenger, rawTx, false); err == nil {
+				challengeSentCounter.Inc(1)
+			} else if err != tx.ErrDuplicateRaw {
+				log.Error("Failed to add challengeNullAddress transaction", "caption", caption, "err", err)
+				failed = append(failed, natx)
+				continue
+			}
+			sent = append(sent, natx)
+		}
+
+		if len(failed) == 0 {
+			rcm.deleteNullAddressTxs(num, sent)
+			continue
+		}
+		for _, natx := range sent {
+			rawdb.DeleteNullAddressTx(rcm.db, natx.BlockNumber, natx.Index)
+		}
+		rcm.nullAddressTxs[num] = failed
+	}
+}
+
+// loadNullAddressTxs reads null address transactions which are not challenged
+// yet from database.
+func (rcm *RootChainManager) loadNullAddressTxs() {
+	natxs := rawdb.ReadAllNullAddressTxs(rcm.db)
+	for _, natx := range natxs {
+		rcm.nullAddressTxs[natx.BlockNumber] = append(rcm.nullAddressTxs[natx.BlockNumber], natx)
+	}
+
+	if len(natxs) > 0 {
+		log.Info("Previous null address transactions are loaded", "numNullAddressTxs", len(natxs))
+	}
+}
+
+// deleteNullAddressTxs removes the null address transactions in the block from
+// memory and database.
+func (rcm *RootChainManager) deleteNullAddressTxs(num uint64, natxs []*rawdb.NullAddressTx) {
+	for _, natx := range natxs {
+		rawdb.DeleteNullAddressTx(rcm.db, natx.BlockNumber, natx.Index)
+	}
+	delete(rcm.nullAddressTxs, num)
+}
+
 func (rcm *RootChainManager) getEpoch(forkNumber, epochNumber *big.Int) (rootchain.DataEpoch, error) {
 	return rcm.rootchainContract.GetEpoch(baseCallOpt, forkNumber, epochNumber)
 }
@@ -824,6 +1998,57 @@ func (rcm *RootChainManager) lastBlock(forkNumber *big.Int, pending bool) (*big.
 	return num, nil
 }
 
+// lastSubmittedBlock returns the last block number submitted in the current fork.
+func (rcm *RootChainManager) lastSubmittedBlock() (uint64, error) {
+	submitted, _, err := rcm.state.blockNumbers()
+	return submitted, err
+}
+
+// lastFinalizedBlock returns the last block number finalized in the current fork.
+func (rcm *RootChainManager) lastFinalizedBlock() (uint64, error) {
+	_, finalized, err := rcm.state.blockNumbers()
+	return finalized, err
+}
+
+// runBlockNumbersUpdater updates the last submitted and finalized block
+// numbers when BlockSubmitted event is fired, when the other RootChain events
+// are handled, or periodically as NRE submission does not fire the event.
+func (rcm *RootChainManager) runBlockNumbersUpdater() {
+	filterer, err := rootchain.NewRootChainFilterer(rcm.config.RootChainContract, rcm.backend)
+	if err != nil {
+		log.Error("Failed to create RootChain filterer", "err", err)
+		return
+	}
+
+	submittedCh := make(chan *rootchain.RootChainBlockSubmitted)
+	submittedSub := event.Resubscribe(resubscribeBackoff, func(ctx context.Context) (event.Subscription, error) {
+		return filterer.WatchBlockSubmitted(&bind.WatchOpts{Context: ctx}, submittedCh)
+	})
+	defer submittedSub.Unsubscribe()
+
+	ticker := time.NewTicker(blockNumbersUpdateInterval)
+	defer ticker.Stop()
+
+	update := func() {
+		if err := rcm.state.updateBlockNumbers(); err != nil {
+			log.Warn("Failed to update last submitted and finalized block numbers", "err", err)
+		}
+	}
+
+	for {
+		select {
+		case <-submittedCh:
+			update()
+		case <-rcm.state.updateCh:
+			update()
+		case <-ticker.C:
+			update()
+		case <-rcm.quit:
+			return
+		}
+	}
+}
+
 // pingBackend checks rootchain backend is alive.
 func (rcm *RootChainManager) pingBackend() {
 	ticker := time.NewTicker(3 * time.Second)
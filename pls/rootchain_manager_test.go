@@ -1105,6 +1105,85 @@ func TestInvalidExit(t *testing.T) {
 	t.Log("Test finished")
 }
 
+// BenchmarkFetchRequests* compare fetching EROs one by one with fetching them
+// in batches, and with the request cache.
+func BenchmarkFetchRequestsSequential100(b *testing.B) {
+	benchmarkFetchRequests(b, 100, fetchRequestsSequential)
+}
+func BenchmarkFetchRequestsSequential1000(b *testing.B) {
+	benchmarkFetchRequests(b, 1000, fetchRequestsSequential)
+}
+func BenchmarkFetchRequestsBatch100(b *testing.B) {
+	benchmarkFetchRequests(b, 100, fetchRequestsBatch)
+}
+func BenchmarkFetchRequestsBatch1000(b *testing.B) {
+	benchmarkFetchRequests(b, 1000, fetchRequestsBatch)
+}
+func BenchmarkFetchRequestsCached1000(b *testing.B) {
+	benchmarkFetchRequests(b, 1000, fetchRequestsCached)
+}
+
+func fetchRequestsSequential(rcm *RootChainManager, numRequests uint64) error {
+	for requestId := uint64(0); requestId < numRequests; requestId++ {
+		if _, err := rcm.rootchainContract.EROs(baseCallOpt, new(big.Int).SetUint64(requestId)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fetchRequestsBatch(rcm *RootChainManager, numRequests uint64) error {
+	rcm.requestFetcher.purge()
+	_, err := rcm.requestFetcher.fetchRequests(false, 0, numRequests-1)
+	return err
+}
+
+func fetchRequestsCached(rcm *RootChainManager, numRequests uint64) error {
+	_, err := rcm.requestFetcher.fetchRequests(false, 0, numRequests-1)
+	return err
+}
+
+func benchmarkFetchRequests(b *testing.B, numRequests uint64, fetch func(rcm *RootChainManager, numRequests uint64) error) {
+	rcm, stopFn, err := makeManager()
+	if err != nil {
+		b.Fatalf("Failed to make RootChainManager: %v", err)
+	}
+	defer stopFn()
+
+	if err := resetNonces(); err != nil {
+		b.Fatalf("Failed to reset nonces: %v", err)
+	}
+
+	trieKey, err := etherToken.GetBalanceTrieKey(baseCallOpt, addr1)
+	if err != nil {
+		b.Fatalf("Failed to get trie key: %v", err)
+	}
+	trieValue := common.LeftPadBytes(big.NewInt(1).Bytes(), 32)
+
+	var tx *types.Transaction
+	for i := uint64(0); i < numRequests; i++ {
+		setNonce(opt1, noncesRootChain[addr1])
+		if tx, err = rcm.rootchainContract.StartEnter(opt1, etherTokenAddr, trieKey, trieValue); err != nil {
+			b.Fatalf("Failed to make an ETH deposit request: %v", err)
+		}
+	}
+	if err = waitEthTx(tx.Hash(), "last deposit request"); err != nil {
+		b.Fatalf("Failed to make an ETH deposit request: %v", err)
+	}
+
+	// warm up the cache for fetchRequestsCached.
+	if _, err := rcm.requestFetcher.fetchRequests(false, 0, numRequests-1); err != nil {
+		b.Fatalf("Failed to fetch requests: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := fetch(rcm, numRequests); err != nil {
+			b.Fatalf("Failed to fetch requests: %v", err)
+		}
+	}
+}
+
 //func TestStress(t *testing.T) {
 //	// override test parameters
 //	NRELength = big.NewInt(1024)
@@ -2265,7 +2344,7 @@ func makePls() (*Plasma, *rpc.Server, string, error) {
 	}
 
 	stopFn := func() { pls.Stop() }
-	txManager, err := tx.NewTransactionManager(ks, rootchainBackend, db, &config.TxConfig)
+	txManager, err := tx.NewTransactionManager(accManager, rootchainBackend, db, &config.TxConfig)
 
 	if err != nil {
 		return nil, nil, d, err
@@ -2276,6 +2355,7 @@ func makePls() (*Plasma, *rpc.Server, string, error) {
 		stopFn,
 		pls.txPool,
 		pls.blockchain,
+		db,
 		rootchainBackend,
 		rootchainContract,
 		pls.eventMux,
@@ -2395,7 +2475,7 @@ func makeManager() (*RootChainManager, func(), error) {
 
 	accConfig := accounts.Config{true}
 	accManager := accounts.NewManager(&accConfig, backends...)
-	txManager, err := tx.NewTransactionManager(ks, ethClient, db, &testPlsConfig.TxConfig)
+	txManager, err := tx.NewTransactionManager(accManager, ethClient, db, &testPlsConfig.TxConfig)
 
 	var rcm *RootChainManager
 
@@ -2411,6 +2491,7 @@ func makeManager() (*RootChainManager, func(), error) {
 		stopFn,
 		txPool,
 		blockchain,
+		db,
 		ethClient,
 		rootchainContract,
 		mux,
@@ -0,0 +1,143 @@
+package pls
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/Onther-Tech/plasma-evm/metrics"
+	"github.com/Onther-Tech/plasma-evm/params"
+	"github.com/Onther-Tech/plasma-evm/tx"
+)
+
+// metricsInterval is the interval to sample the plasma metrics.
+const metricsInterval = 15 * time.Second
+
+var (
+	rootchainHeadGauge = metrics.NewRegisteredGauge("plasma/rootchain/head", nil)
+	rootchainLagGauge  = metrics.NewRegisteredGauge("plasma/rootchain/lag", nil) // root chain blocks whose events are not processed yet
+
+	forkGauge         = metrics.NewRegisteredGauge("plasma/epoch/fork", nil)
+	epochGauge        = metrics.NewRegisteredGauge("plasma/epoch/number", nil)
+	epochRequestGauge = metrics.NewRegisteredGauge("plasma/epoch/request", nil) // 1 if the current epoch is a request epoch
+
+	submissionTimer = metrics.NewRegisteredTimer("plasma/submission/mined", nil) // time from epoch (or request block) completion to submission mined
+
+	operatorBalanceGauge = metrics.NewRegisteredGaugeFloat64("plasma/operator/balance", nil) // in ether
+
+	invalidExitCounter   = metrics.NewRegisteredCounter("plasma/challenge/invalidexits", nil)
+	challengeSentCounter = metrics.NewRegisteredCounter("plasma/challenge/sent", nil)
+)
+
+// submissionAdded records the time when the submit transaction is added, to
+// measure the time until the transaction is mined.
+func (rcm *RootChainManager) submissionAdded(raw *tx.RawTransaction) {
+	if !metrics.Enabled {
+		return
+	}
+
+	rcm.submissionsLock.Lock()
+	defer rcm.submissionsLock.Unlock()
+
+	rcm.submissions[raw.Hash()] = time.Now()
+}
+
+// updateRootChainHead updates the root chain head and the number of blocks
+// whose events are not processed yet.
+func (rcm *RootChainManager) updateRootChainHead(headNumber uint64) {
+	if !metrics.Enabled {
+		return
+	}
+
+	rootchainHeadGauge.Update(int64(headNumber))
+	if cursor := rcm.rootchainCursor().Number; headNumber > cursor {
+		rootchainLagGauge.Update(int64(headNumber - cursor))
+	} else {
+		rootchainLagGauge.Update(0)
+	}
+}
+
+// runMetrics samples the epoch and the operator balance periodically, and
+// measures the submission time when submit transactions are mined. Submit
+// transactions cancelled or confirmed without the mined event are forgotten.
+func (rcm *RootChainManager) runMetrics() {
+	var (
+		minedCh     = make(chan *tx.RawTransaction, 128)
+		confirmedCh = make(chan *tx.RawTransaction, 128)
+		cancelledCh = make(chan *tx.RawTransaction, 128)
+	)
+	minedSub := rcm.txManager.SubscribeMinedTxs(minedCh)
+	defer minedSub.Unsubscribe()
+	confirmedSub := rcm.txManager.SubscribeConfirmedTxs(confirmedCh)
+	defer confirmedSub.Unsubscribe()
+	cancelledSub := rcm.txManager.SubscribeCancelledTxs(cancelledCh)
+	defer cancelledSub.Unsubscribe()
+
+	ticker := time.NewTicker(metricsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case raw := <-minedCh:
+			if added, ok := rcm.submissionDone(raw); ok {
+				submissionTimer.UpdateSince(added)
+			}
+
+		case raw := <-confirmedCh:
+			rcm.submissionDone(raw)
+
+		case raw := <-cancelledCh:
+			rcm.submissionDone(raw)
+
+		case <-ticker.C:
+			rcm.sampleMetrics()
+
+		case <-minedSub.Err():
+			return
+
+		case <-confirmedSub.Err():
+			return
+
+		case <-cancelledSub.Err():
+			return
+
+		case <-rcm.quit:
+			return
+		}
+	}
+}
+
+// submissionDone forgets the submit transaction and returns the time when it
+// was added.
+func (rcm *RootChainManager) submissionDone(raw *tx.RawTransaction) (time.Time, bool) {
+	rcm.submissionsLock.Lock()
+	defer rcm.submissionsLock.Unlock()
+
+	added, ok := rcm.submissions[raw.Hash()]
+	delete(rcm.submissions, raw.Hash())
+	return added, ok
+}
+
+func (rcm *RootChainManager) sampleMetrics() {
+	rcm.minerEnv.Lock()
+	forkGauge.Update(rcm.minerEnv.CurrentFork.Int64())
+	epochGauge.Update(rcm.minerEnv.EpochNumber.Int64())
+	if rcm.minerEnv.IsRequest {
+		epochRequestGauge.Update(1)
+	} else {
+		epochRequestGauge.Update(0)
+	}
+	rcm.minerEnv.Unlock()
+
+	if rcm.config.NodeMode == ModeOperator {
+		if balance, err := rcm.backend.BalanceAt(context.Background(), rcm.config.Operator.Address, nil); err == nil {
+			updateBalanceGauge(balance)
+		}
+	}
+}
+
+// updateBalanceGauge updates the operator balance in ether.
+func updateBalanceGauge(balance *big.Int) {
+	ether, _ := new(big.Float).Quo(new(big.Float).SetInt(balance), big.NewFloat(params.Ether)).Float64()
+	operatorBalanceGauge.Update(ether)
+}
@@ -0,0 +1,56 @@
+package pls
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/metrics"
+	"github.com/Onther-Tech/plasma-evm/params"
+	"github.com/Onther-Tech/plasma-evm/tx"
+)
+
+func TestCancelledSubmissionForgotten(t *testing.T) {
+	enabled := metrics.Enabled
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = enabled }()
+
+	rcm, cleanup := newTestOperatorManager(t, 0)
+	defer cleanup()
+
+	go rcm.runMetrics()
+	time.Sleep(100 * time.Millisecond) // wait for the subscriptions
+
+	addr := rcm.config.Operator.Address
+	for i := 0; i < 2; i++ {
+		raw := tx.NewRawTransaction(addr, params.SubmitBlockGasLimit, &common.Address{}, big.NewInt(0), []byte{byte(i)}, false, "submitORB")
+		if _, err := rcm.txManager.AddToPool(submitterPool, raw, submissionOrder(big.NewInt(0))); err != nil {
+			t.Fatalf("Failed to add submit transaction: %v", err)
+		}
+		rcm.submissionAdded(raw)
+	}
+
+	numSubmissions := func() int {
+		rcm.submissionsLock.Lock()
+		defer rcm.submissionsLock.Unlock()
+
+		return len(rcm.submissions)
+	}
+	if n := numSubmissions(); n != 2 {
+		t.Fatalf("Submissions mismatch: have %d, want %d", n, 2)
+	}
+
+	// cancel the last submit transaction.
+	if err := rcm.txManager.Cancel(addr, 1); err != nil {
+		t.Fatalf("Failed to cancel submit transaction: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for numSubmissions() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Cancelled submission is not forgotten: %d submissions", numSubmissions())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
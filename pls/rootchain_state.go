@@ -1,11 +1,16 @@
 package pls
 
 import (
+	"errors"
 	"math/big"
 	"sync"
 	"time"
+
+	"github.com/Onther-Tech/plasma-evm/log"
 )
 
+var errBlockNumbersNotLoaded = errors.New("last submitted and finalized block numbers are not loaded yet")
+
 type rootchainState struct {
 	rcm *RootChainManager
 
@@ -18,16 +23,28 @@ type rootchainState struct {
 	costNRB        uint64
 	maxRequests    uint64
 	requestGas     uint64
+	cpExit         uint64
+	cpComputation  uint64
+	cpWithholding  uint64
 	lastEpoch      uint64
 	currentFork    uint64
 
+	// last block numbers of the current fork, updated from RootChain events
+	lastSubmittedBlock uint64
+	lastFinalizedBlock uint64
+	blockNumbersLoaded bool
+	updateCh           chan struct{}
+
 	lastUpdateTime time.Time
 
 	lock sync.Mutex
 }
 
 func newRootchainState(rcm *RootChainManager) *rootchainState {
-	rs := &rootchainState{rcm: rcm}
+	rs := &rootchainState{
+		rcm:      rcm,
+		updateCh: make(chan struct{}, 1),
+	}
 
 	rs.costERO = rs.getCostERO()
 	rs.costERU = rs.getCostERU()
@@ -37,12 +54,64 @@ func newRootchainState(rcm *RootChainManager) *rootchainState {
 	rs.costNRB = rs.getCostNRB()
 	rs.maxRequests = rs.getMaxRequests()
 	rs.requestGas = rs.getRequestGas()
+	rs.cpExit = rs.getCPExit()
+	rs.cpComputation = rs.getCPComputation()
+	rs.cpWithholding = rs.getCPWithholding()
 	rs.lastEpoch = rs.getLastEpoch()
 	rs.currentFork = rs.getCurrentFork()
 
+	if err := rs.updateBlockNumbers(); err != nil {
+		log.Warn("Failed to load last submitted and finalized block numbers", "err", err)
+	}
+
 	return rs
 }
 
+// updateBlockNumbers reads the last submitted and finalized block numbers of
+// the current fork from RootChain contract.
+func (rs *rootchainState) updateBlockNumbers() error {
+	currentFork, err := rs.rcm.rootchainContract.CurrentFork(baseCallOpt)
+	if err != nil {
+		return err
+	}
+	submitted, err := rs.rcm.lastBlock(currentFork, false)
+	if err != nil {
+		return err
+	}
+	finalized, err := rs.rcm.rootchainContract.GetLastFinalizedBlock(baseCallOpt, currentFork)
+	if err != nil {
+		return err
+	}
+
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+
+	rs.lastSubmittedBlock = submitted.Uint64()
+	rs.lastFinalizedBlock = finalized.Uint64()
+	rs.blockNumbersLoaded = true
+	return nil
+}
+
+// requestBlockNumbersUpdate schedules the update of the last submitted and
+// finalized block numbers without blocking the caller.
+func (rs *rootchainState) requestBlockNumbersUpdate() {
+	select {
+	case rs.updateCh <- struct{}{}:
+	default:
+	}
+}
+
+// blockNumbers returns the cached last submitted and finalized block numbers.
+func (rs *rootchainState) blockNumbers() (submitted, finalized uint64, err error) {
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+
+	if !rs.blockNumbersLoaded {
+		return 0, 0, errBlockNumbersNotLoaded
+	}
+	return rs.lastSubmittedBlock, rs.lastFinalizedBlock, nil
+}
+
 func (rs *rootchainState) getCostERU() uint64 {
 	r, _ := rs.rcm.rootchainContract.COSTERU(baseCallOpt)
 	return r.Uint64()
@@ -75,6 +144,18 @@ func (rs *rootchainState) getRequestGas() uint64 {
 	r, _ := rs.rcm.rootchainContract.REQUESTGAS(baseCallOpt)
 	return r.Uint64()
 }
+func (rs *rootchainState) getCPExit() uint64 {
+	r, _ := rs.rcm.rootchainContract.CPEXIT(baseCallOpt)
+	return r.Uint64()
+}
+func (rs *rootchainState) getCPComputation() uint64 {
+	r, _ := rs.rcm.rootchainContract.CPCOMPUTATION(baseCallOpt)
+	return r.Uint64()
+}
+func (rs *rootchainState) getCPWithholding() uint64 {
+	r, _ := rs.rcm.rootchainContract.CPWITHHOLDING(baseCallOpt)
+	return r.Uint64()
+}
 func (rs *rootchainState) getLastEpoch() uint64 {
 	fork, _ := rs.rcm.rootchainContract.Forks(baseCallOpt, big.NewInt(int64(rs.currentFork)))
 	return fork.LastEpoch
@@ -0,0 +1,300 @@
+package pls
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/Onther-Tech/plasma-evm/accounts/abi/bind"
+	"github.com/Onther-Tech/plasma-evm/contracts/plasma/rootchain"
+	"github.com/Onther-Tech/plasma-evm/core/rawdb"
+	"github.com/Onther-Tech/plasma-evm/core/types"
+	"github.com/Onther-Tech/plasma-evm/event"
+	"github.com/Onther-Tech/plasma-evm/log"
+)
+
+// submissionCheckInterval is the interval to compare the submitted blocks with
+// the blocks received by the node.
+const submissionCheckInterval = 30 * time.Second
+
+// submissionDetector compares the roots submitted by submitNRE, submitORB and
+// submitURB with the roots of the blocks received by the node. Mismatched
+// roots and blocks not received during the withholding challenge period are
+// recorded as submission faults.
+type submissionDetector struct {
+	rcm *RootChainManager
+
+	started     bool
+	forkNumber  uint64 // fork of the epoch to check
+	epochNumber uint64 // next epoch to check
+	blockNumber uint64 // next block to check in request epoch
+}
+
+func newSubmissionDetector(rcm *RootChainManager) *submissionDetector {
+	return &submissionDetector{rcm: rcm}
+}
+
+// runSubmissionDetector checks the submitted blocks when BlockSubmitted event
+// is fired or periodically as NRE submission does not fire the event.
+func (rcm *RootChainManager) runSubmissionDetector() {
+	if rcm.config.NodeMode != ModeChallenger {
+		return
+	}
+
+	d := newSubmissionDetector(rcm)
+
+	filterer, err := rootchain.NewRootChainFilterer(rcm.config.RootChainContract, rcm.backend)
+	if err != nil {
+		log.Error("Failed to create RootChain filterer", "err", err)
+		return
+	}
+
+	// failed subscription is re-subscribed with backoff.
+	submittedCh := make(chan *rootchain.RootChainBlockSubmitted)
+	submittedSub := event.Resubscribe(resubscribeBackoff, func(ctx context.Context) (event.Subscription, error) {
+		sub, err := filterer.WatchBlockSubmitted(&bind.WatchOpts{Context: ctx}, submittedCh)
+		if err != nil {
+			log.Warn("Failed to watch block submitted event, retrying", "err", err)
+		}
+		return sub, err
+	})
+	defer submittedSub.Unsubscribe()
+
+	ticker := time.NewTicker(submissionCheckInterval)
+	defer ticker.Stop()
+
+	check := func() {
+		if err := d.check(); err != nil {
+			log.Warn("Failed to check submitted blocks", "err", err)
+		}
+	}
+
+	check()
+
+	for {
+		select {
+		case e := <-submittedCh:
+			if e != nil {
+				log.Debug("Block submitted", "forkNumber", e.Fork, "epochNumber", e.EpochNumber, "blockNumber", e.BlockNumber, "isRequest", e.IsRequest, "userActivated", e.UserActivated)
+				check()
+			}
+
+		case <-ticker.C:
+			check()
+
+		case <-rcm.quit:
+			return
+		}
+	}
+}
+
+// check checks the submitted epochs of the current fork in order. It stops at
+// the first epoch which is not submitted yet or whose blocks are not received
+// while the withholding challenge period is not over.
+func (d *submissionDetector) check() error {
+	rcm := d.rcm
+
+	currentFork, err := rcm.rootchainContract.CurrentFork(baseCallOpt)
+	if err != nil {
+		return err
+	}
+
+	fork, err := rcm.rootchainContract.Forks(baseCallOpt, currentFork)
+	if err != nil {
+		return err
+	}
+
+	// start from the first epoch of the fork, skipping finalized blocks.
+	if !d.started || d.forkNumber != currentFork.Uint64() {
+		d.started = true
+		d.forkNumber = currentFork.Uint64()
+		d.epochNumber = fork.FirstEpoch
+		d.blockNumber = fork.FirstBlock
+
+		if fork.LastFinalizedBlock >= fork.FirstBlock {
+			block, err := rcm.getBlock(currentFork, new(big.Int).SetUint64(fork.LastFinalizedBlock))
+			if err != nil {
+				return err
+			}
+			if block.EpochNumber > d.epochNumber {
+				d.epochNumber = block.EpochNumber
+			}
+			d.blockNumber = fork.LastFinalizedBlock + 1
+		}
+	}
+
+	for d.epochNumber <= fork.LastEpoch {
+		epoch, err := rcm.getEpoch(currentFork, new(big.Int).SetUint64(d.epochNumber))
+		if err != nil {
+			return err
+		}
+
+		if !epoch.Initialized {
+			return nil
+		}
+
+		if !epoch.IsEmpty {
+			var done bool
+			if epoch.IsRequest || epoch.Rebase {
+				done, err = d.checkBlocks(epoch)
+			} else {
+				done, err = d.checkNRE(epoch)
+			}
+			if err != nil || !done {
+				return err
+			}
+		}
+
+		d.epochNumber++
+		if d.blockNumber <= epoch.EndBlockNumber {
+			d.blockNumber = epoch.EndBlockNumber + 1
+		}
+	}
+
+	return nil
+}
+
+// checkNRE compares the epoch roots of the submitted NRE with the roots of
+// the local blocks. It returns false if the NRE should be checked again.
+func (d *submissionDetector) checkNRE(epoch rootchain.DataEpoch) (bool, error) {
+	rcm := d.rcm
+
+	if epoch.NRE.SubmittedAt == 0 {
+		return false, nil
+	}
+
+	sf := &rawdb.SubmissionFault{
+		ForkNumber:       d.forkNumber,
+		EpochNumber:      d.epochNumber,
+		StartBlockNumber: epoch.StartBlockNumber,
+		EndBlockNumber:   epoch.EndBlockNumber,
+		SubmittedAt:      epoch.NRE.SubmittedAt,
+		StatesRoot:       epoch.NRE.EpochStateRoot,
+		TransactionsRoot: epoch.NRE.EpochTransactionsRoot,
+		ReceiptsRoot:     epoch.NRE.EpochReceiptsRoot,
+	}
+
+	blocks := make(types.Blocks, 0, epoch.EndBlockNumber-epoch.StartBlockNumber+1)
+	for n := epoch.StartBlockNumber; n <= epoch.EndBlockNumber; n++ {
+		block := rcm.blockchain.GetBlockByNumber(n)
+		if block == nil {
+			return d.checkWithheld(sf), nil
+		}
+		blocks = append(blocks, block)
+	}
+
+	sf.LocalStatesRoot = blocks.StatesRoot()
+	sf.LocalTransactionsRoot = blocks.TransactionsRoot()
+	sf.LocalReceiptsRoot = blocks.ReceiptsRoot()
+
+	d.checkRoots(sf)
+	return true, nil
+}
+
+// checkBlocks compares the roots of the submitted request blocks (or rebased
+// blocks) with the roots of the local blocks. It returns false if the blocks
+// should be checked again.
+func (d *submissionDetector) checkBlocks(epoch rootchain.DataEpoch) (bool, error) {
+	rcm := d.rcm
+
+	if d.blockNumber < epoch.StartBlockNumber {
+		d.blockNumber = epoch.StartBlockNumber
+	}
+
+	// end block number of ORE' and NRE' is 0 until the epoch is rebased.
+	if epoch.EndBlockNumber < epoch.StartBlockNumber {
+		return false, nil
+	}
+
+	for ; d.blockNumber <= epoch.EndBlockNumber; d.blockNumber++ {
+		pb, err := rcm.getBlock(new(big.Int).SetUint64(d.forkNumber), new(big.Int).SetUint64(d.blockNumber))
+		if err != nil {
+			return false, err
+		}
+
+		// Wait until the block is submitted.
+		if pb.Timestamp == 0 {
+			return false, nil
+		}
+
+		sf := &rawdb.SubmissionFault{
+			ForkNumber:       d.forkNumber,
+			EpochNumber:      d.epochNumber,
+			StartBlockNumber: d.blockNumber,
+			EndBlockNumber:   d.blockNumber,
+			IsRequest:        pb.IsRequest,
+			SubmittedAt:      pb.Timestamp,
+			StatesRoot:       pb.StatesRoot,
+			TransactionsRoot: pb.TransactionsRoot,
+			ReceiptsRoot:     pb.ReceiptsRoot,
+		}
+
+		block := rcm.blockchain.GetBlockByNumber(d.blockNumber)
+		if block == nil {
+			if !d.checkWithheld(sf) {
+				return false, nil
+			}
+			continue
+		}
+
+		sf.LocalStatesRoot = block.Root()
+		sf.LocalTransactionsRoot = block.TxHash()
+		sf.LocalReceiptsRoot = block.ReceiptHash()
+
+		d.checkRoots(sf)
+	}
+
+	return true, nil
+}
+
+// checkRoots records the submission fault if the submitted roots are
+// different from the local roots.
+func (d *submissionDetector) checkRoots(sf *rawdb.SubmissionFault) {
+	if sf.StatesRoot == sf.LocalStatesRoot &&
+		sf.TransactionsRoot == sf.LocalTransactionsRoot &&
+		sf.ReceiptsRoot == sf.LocalReceiptsRoot {
+		return
+	}
+
+	sf.Kind = rawdb.SubmissionRootMismatch
+	d.record(sf)
+}
+
+// checkWithheld records the submission fault if the withholding challenge
+// period of the submitted blocks is over. It returns false if the period is
+// not over yet.
+func (d *submissionDetector) checkWithheld(sf *rawdb.SubmissionFault) bool {
+	if sf.SubmittedAt+d.rcm.state.cpWithholding > uint64(time.Now().Unix()) {
+		return false
+	}
+
+	sf.Kind = rawdb.SubmissionWithheld
+	d.record(sf)
+	return true
+}
+
+// record stores the submission fault and raises an alert.
+func (d *submissionDetector) record(sf *rawdb.SubmissionFault) {
+	// Skip already known submission fault.
+	if rawdb.ReadSubmissionFault(d.rcm.db, sf.ForkNumber, sf.StartBlockNumber) != nil {
+		return
+	}
+
+	sf.DetectedAt = uint64(time.Now().Unix())
+	rawdb.WriteSubmissionFault(d.rcm.db, sf)
+
+	log.Error("Submission fault detected",
+		"kind", sf.Kind,
+		"forkNumber", sf.ForkNumber,
+		"epochNumber", sf.EpochNumber,
+		"startBlockNumber", sf.StartBlockNumber,
+		"endBlockNumber", sf.EndBlockNumber,
+		"isRequest", sf.IsRequest,
+		"statesRoot", sf.StatesRoot,
+		"localStatesRoot", sf.LocalStatesRoot,
+		"transactionsRoot", sf.TransactionsRoot,
+		"localTransactionsRoot", sf.LocalTransactionsRoot,
+		"receiptsRoot", sf.ReceiptsRoot,
+		"localReceiptsRoot", sf.LocalReceiptsRoot,
+	)
+}
@@ -0,0 +1,96 @@
+package pls
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Onther-Tech/plasma-evm/accounts"
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/log"
+)
+
+const (
+	// submitterPool is the name of the transaction manager pool of the
+	// accounts submitting NREs and ORBs.
+	submitterPool = "submitter"
+
+	// submitterPoolInterval is the interval to check whether the submitter
+	// accounts are registered in RootChain contract.
+	submitterPoolInterval = 10 * time.Minute
+)
+
+// submissionOrder returns the order key of the submissions of the fork in the
+// submitter pool, as RootChain contract accepts blocks of a fork in order.
+func submissionOrder(forkNumber *big.Int) string {
+	return fmt.Sprintf("fork#%d", forkNumber)
+}
+
+// registeredSubmitters returns the operator and the additional submitter
+// accounts registered as submitters in RootChain contract. The operator is
+// used if no account is registered.
+func (rcm *RootChainManager) registeredSubmitters() []accounts.Account {
+	candidates := append([]accounts.Account{rcm.config.Operator}, rcm.config.Submitters...)
+
+	var submitters []accounts.Account
+	for _, acc := range candidates {
+		isSubmitter, err := rcm.rootchainContract.IsSubmitter(baseCallOpt, acc.Address)
+		if err != nil {
+			log.Warn("Failed to check submitter account", "address", acc.Address, "err", err)
+			continue
+		}
+		if !isSubmitter {
+			log.Warn("Account is not a submitter of RootChain contract", "address", acc.Address)
+			continue
+		}
+
+		bal, err := rcm.backend.BalanceAt(context.Background(), acc.Address, nil)
+		if err == nil && rcm.config.OperatorMinEther != nil && bal.Cmp(rcm.config.OperatorMinEther) < 0 {
+			log.Warn("Submitter account balance on rootchain is too low", "address", acc.Address, "balance", bal)
+		}
+
+		submitters = append(submitters, acc)
+	}
+
+	if len(submitters) == 0 {
+		submitters = []accounts.Account{rcm.config.Operator}
+	}
+	return submitters
+}
+
+// updateSubmitterPool sets the registered submitters to the submitter pool.
+func (rcm *RootChainManager) updateSubmitterPool() {
+	rcm.txManager.SetPool(submitterPool, rcm.registeredSubmitters())
+}
+
+// runSubmitterPool updates the submitter pool periodically as submitters can
+// be added or removed in RootChain contract while the node is running.
+func (rcm *RootChainManager) runSubmitterPool() {
+	ticker := time.NewTicker(submitterPoolInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			rcm.updateSubmitterPool()
+		case <-rcm.quit:
+			return
+		}
+	}
+}
+
+// submitterAddresses returns the addresses of the accounts in the submitter
+// pool.
+func (rcm *RootChainManager) submitterAddresses() []common.Address {
+	submitters := rcm.txManager.Pool(submitterPool)
+	if len(submitters) == 0 {
+		return []common.Address{rcm.config.Operator.Address}
+	}
+
+	addrs := make([]common.Address, len(submitters))
+	for i, acc := range submitters {
+		addrs[i] = acc.Address
+	}
+	return addrs
+}
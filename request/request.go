@@ -0,0 +1,279 @@
+// Package request constructs enter and exit requests to requestable contracts
+// mapped in RootChain contract.
+package request
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/Onther-Tech/plasma-evm"
+	"github.com/Onther-Tech/plasma-evm/accounts/abi"
+	"github.com/Onther-Tech/plasma-evm/accounts/abi/bind"
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/contracts/plasma/rootchain"
+	"github.com/Onther-Tech/plasma-evm/contracts/plasma/token"
+	"github.com/Onther-Tech/plasma-evm/core/types"
+	"github.com/Onther-Tech/plasma-evm/params"
+)
+
+var (
+	ErrNotMapped       = errors.New("requestable contract is not mapped in RootChain contract")
+	ErrZeroAmount      = errors.New("amount must be greater than 0")
+	ErrRequestRejected = errors.New("request is rejected by requestable contract in child chain")
+
+	requestableContractABI, _ = abi.JSON(strings.NewReader(rootchain.RequestableIABI))
+)
+
+// Request is an enter or exit request to a requestable contract.
+type Request struct {
+	IsExit    bool
+	Requestor common.Address
+	To        common.Address // requestable contract in root chain
+	Value     *big.Int       // ether to send with enter request
+	TrieKey   common.Hash
+	TrieValue []byte
+}
+
+// IsTransfer returns true if the request is a simple ether (PETH) transfer to
+// the requestor in child chain.
+func (r *Request) IsTransfer(etherToken common.Address) bool {
+	return !r.IsExit && r.To == etherToken
+}
+
+// Status is the status of a request in RootChain contract.
+type Status struct {
+	RequestId      uint64
+	UserActivated  bool
+	IsExit         bool
+	IsTransfer     bool
+	Requestor      common.Address
+	To             common.Address
+	TrieKey        common.Hash
+	TrieValue      []byte
+	Timestamp      uint64
+	RequestBlockId uint64
+	Submitted      bool // whether the request block is submitted
+	Challenged     bool
+	Finalized      bool
+}
+
+// Builder builds requests and sends them to RootChain contract.
+type Builder struct {
+	rootchainContract *rootchain.RootChain
+	rootchainBackend  bind.ContractBackend
+	childBackend      bind.ContractCaller
+
+	etherToken common.Address
+}
+
+// NewBuilder creates a request builder. childBackend is used to simulate
+// requests against child chain state. It can be nil to skip simulation.
+func NewBuilder(rootchainAddr common.Address, rootchainBackend bind.ContractBackend, childBackend bind.ContractCaller) (*Builder, error) {
+	rootchainContract, err := rootchain.NewRootChain(rootchainAddr, rootchainBackend)
+	if err != nil {
+		return nil, err
+	}
+
+	etherToken, err := rootchainContract.EtherToken(&bind.CallOpts{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Builder{
+		rootchainContract: rootchainContract,
+		rootchainBackend:  rootchainBackend,
+		childBackend:      childBackend,
+		etherToken:        etherToken,
+	}, nil
+}
+
+// EtherToken returns the address of EtherToken (PETH) contract in root chain.
+func (b *Builder) EtherToken() common.Address {
+	return b.etherToken
+}
+
+// NewTokenRequest builds a request to move amount of the requestable token
+// between root chain and child chain. The token can be EtherToken for PETH or
+// an ERC20 requestable token (e.g., RequestableSimpleToken).
+func (b *Builder) NewTokenRequest(isExit bool, requestor, tokenAddr common.Address, amount *big.Int) (*Request, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, ErrZeroAmount
+	}
+
+	r := &Request{
+		IsExit:    isExit,
+		Requestor: requestor,
+		To:        tokenAddr,
+		Value:     big.NewInt(0),
+		TrieValue: common.LeftPadBytes(amount.Bytes(), 32),
+	}
+
+	if !r.IsTransfer(b.etherToken) {
+		mapped, err := b.rootchainContract.RequestableContracts(&bind.CallOpts{}, tokenAddr)
+		if err != nil {
+			return nil, err
+		}
+		if (mapped == common.Address{}) {
+			return nil, ErrNotMapped
+		}
+	}
+
+	// EtherToken and requestable tokens have same balance trie key.
+	tokenContract, err := token.NewRequestableSimpleToken(tokenAddr, b.rootchainBackend)
+	if err != nil {
+		return nil, err
+	}
+
+	trieKey, err := tokenContract.GetBalanceTrieKey(&bind.CallOpts{}, requestor)
+	if err != nil {
+		return nil, err
+	}
+	r.TrieKey = trieKey
+
+	return r, nil
+}
+
+// Simulate applies the request to the requestable contract in child chain
+// without sending a transaction. It returns ErrRequestRejected if the request
+// would be reverted in child chain.
+func (b *Builder) Simulate(ctx context.Context, r *Request) error {
+	// PETH enter is a simple ether transfer in child chain.
+	if b.childBackend == nil || r.IsTransfer(b.etherToken) {
+		return nil
+	}
+
+	childContract, err := b.rootchainContract.RequestableContracts(&bind.CallOpts{Context: ctx}, r.To)
+	if err != nil {
+		return err
+	}
+	if (childContract == common.Address{}) {
+		return ErrNotMapped
+	}
+
+	// the request would have the next request id.
+	requestId, err := b.rootchainContract.GetNumEROs(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return err
+	}
+
+	input, err := requestableContractABI.Pack("applyRequestInChildChain", r.IsExit, requestId, r.Requestor, r.TrieKey, r.TrieValue)
+	if err != nil {
+		return err
+	}
+
+	output, err := b.childBackend.CallContract(ctx, ethereum.CallMsg{
+		From: params.NullAddress,
+		To:   &childContract,
+		Gas:  params.RequestTxGasLimit,
+		Data: input,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("%v: %v", ErrRequestRejected, err)
+	}
+
+	var success bool
+	if err := requestableContractABI.Unpack(&success, "applyRequestInChildChain", output); err != nil || !success {
+		return ErrRequestRejected
+	}
+	return nil
+}
+
+// Send sends startEnter or startExit transaction of the request to RootChain
+// contract. COST_ERO is paid for exit requests.
+func (b *Builder) Send(opt *bind.TransactOpts, r *Request) (*types.Transaction, error) {
+	txOpt := *opt
+
+	if r.IsExit {
+		costERO, err := b.rootchainContract.COSTERO(&bind.CallOpts{Context: opt.Context})
+		if err != nil {
+			return nil, err
+		}
+		txOpt.Value = costERO
+		return b.rootchainContract.StartExit(&txOpt, r.To, r.TrieKey, r.TrieValue)
+	}
+
+	txOpt.Value = r.Value
+	return b.rootchainContract.StartEnter(&txOpt, r.To, r.TrieKey, r.TrieValue)
+}
+
+// Status returns the status of the request. If userActivated is true, the
+// request is an escape request (ERU).
+func (b *Builder) Status(requestId uint64, userActivated bool) (*Status, error) {
+	callOpt := &bind.CallOpts{}
+	getRequest, getRequestBlock := b.rootchainContract.EROs, b.rootchainContract.ORBs
+	if userActivated {
+		getRequest, getRequestBlock = b.rootchainContract.ERUs, b.rootchainContract.URBs
+	}
+
+	request, err := getRequest(callOpt, new(big.Int).SetUint64(requestId))
+	if err != nil {
+		return nil, err
+	}
+
+	// Short circuit if the request does not exist.
+	if request.Timestamp == 0 {
+		return nil, fmt.Errorf("request #%d not found", requestId)
+	}
+
+	status := &Status{
+		RequestId:     requestId,
+		UserActivated: userActivated,
+		IsExit:        request.IsExit,
+		IsTransfer:    request.IsTransfer,
+		Requestor:     request.Requestor,
+		To:            request.To,
+		TrieKey:       request.TrieKey,
+		TrieValue:     request.TrieValue,
+		Timestamp:     request.Timestamp,
+		Challenged:    request.Challenged,
+		Finalized:     request.Finalized,
+	}
+
+	// find the request block including the request.
+	if userActivated {
+		for id := uint64(0); ; id++ {
+			rb, err := getRequestBlock(callOpt, new(big.Int).SetUint64(id))
+			if err != nil || rb.RequestEnd < rb.RequestStart {
+				break
+			}
+			if rb.RequestStart <= requestId && requestId <= rb.RequestEnd {
+				status.RequestBlockId = id
+				status.Submitted = rb.Submitted
+				break
+			}
+		}
+		return status, nil
+	}
+
+	numORBs, err := b.rootchainContract.GetNumORBs(callOpt)
+	if err != nil {
+		return nil, err
+	}
+
+	// binary search the ORB as request ids in ORBs are increasing.
+	lo, hi := uint64(0), numORBs.Uint64()
+	for lo < hi {
+		mid := (lo + hi) / 2
+
+		rb, err := getRequestBlock(callOpt, new(big.Int).SetUint64(mid))
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case requestId < rb.RequestStart:
+			hi = mid
+		case requestId > rb.RequestEnd:
+			lo = mid + 1
+		default:
+			status.RequestBlockId = mid
+			status.Submitted = rb.Submitted
+			return status, nil
+		}
+	}
+
+	return status, nil
+}
@@ -0,0 +1,285 @@
+package request
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	ethereum "github.com/Onther-Tech/plasma-evm"
+	"github.com/Onther-Tech/plasma-evm/accounts/abi"
+	"github.com/Onther-Tech/plasma-evm/accounts/abi/bind"
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/contracts/plasma/rootchain"
+	"github.com/Onther-Tech/plasma-evm/contracts/plasma/token"
+	"github.com/Onther-Tech/plasma-evm/core/types"
+	"github.com/Onther-Tech/plasma-evm/crypto"
+)
+
+var (
+	rootchainABI, _ = abi.JSON(strings.NewReader(rootchain.RootChainABI))
+	tokenABI, _     = abi.JSON(strings.NewReader(token.RequestableSimpleTokenABI))
+
+	testKey, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testRequestor = crypto.PubkeyToAddress(testKey.PublicKey)
+
+	testRootChain  = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	testEtherToken = common.HexToAddress("0x1000000000000000000000000000000000000002")
+	testToken      = common.HexToAddress("0x1000000000000000000000000000000000000003")
+	testChildToken = common.HexToAddress("0x2000000000000000000000000000000000000003")
+	testUnmapped   = common.HexToAddress("0x1000000000000000000000000000000000000004")
+
+	testCostERO = big.NewInt(1e17)
+	testNumEROs = big.NewInt(7)
+)
+
+// testBalanceTrieKey is the balance trie key returned by the fake requestable
+// token contracts.
+func testBalanceTrieKey(who common.Address) common.Hash {
+	return crypto.Keccak256Hash(common.LeftPadBytes(who.Bytes(), 32), common.Hash{}.Bytes())
+}
+
+// fakeBackend answers RootChain and requestable token calls by the method
+// selector and records the sent transactions and child chain calls.
+type fakeBackend struct {
+	mapped map[common.Address]common.Address
+	reject bool // whether applyRequestInChildChain fails
+
+	calls []ethereum.CallMsg
+	sent  []*types.Transaction
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{
+		mapped: map[common.Address]common.Address{
+			testEtherToken: testEtherToken,
+			testToken:      testChildToken,
+		},
+	}
+}
+
+func (b *fakeBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{0x1}, nil
+}
+
+func (b *fakeBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	b.calls = append(b.calls, call)
+
+	contractABI := rootchainABI
+	if *call.To != testRootChain {
+		contractABI = tokenABI
+	}
+
+	method, err := contractABI.MethodById(call.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	args, err := method.Inputs.UnpackValues(call.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	switch method.Name {
+	case "etherToken":
+		return method.Outputs.Pack(testEtherToken)
+	case "requestableContracts":
+		return method.Outputs.Pack(b.mapped[args[0].(common.Address)])
+	case "getBalanceTrieKey":
+		return method.Outputs.Pack(testBalanceTrieKey(args[0].(common.Address)))
+	case "COST_ERO":
+		return method.Outputs.Pack(testCostERO)
+	case "getNumEROs":
+		return method.Outputs.Pack(testNumEROs)
+	case "applyRequestInChildChain":
+		if b.reject {
+			return nil, errors.New("execution reverted")
+		}
+		return method.Outputs.Pack(true)
+	}
+	return nil, errors.New("unexpected call: " + method.Name)
+}
+
+func (b *fakeBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return []byte{0x1}, nil
+}
+
+func (b *fakeBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return 0, nil
+}
+
+func (b *fakeBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(1e9), nil
+}
+
+func (b *fakeBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return 100000, nil
+}
+
+func (b *fakeBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.sent = append(b.sent, tx)
+	return nil
+}
+
+func (b *fakeBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return nil, nil
+}
+
+func (b *fakeBackend) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return nil, errors.New("not supported")
+}
+
+func newTestBuilder(t *testing.T, backend *fakeBackend) *Builder {
+	b, err := NewBuilder(testRootChain, backend, backend)
+	if err != nil {
+		t.Fatalf("Failed to create builder: %v", err)
+	}
+	if b.EtherToken() != testEtherToken {
+		t.Fatalf("EtherToken mismatch: have %s, want %s", b.EtherToken().Hex(), testEtherToken.Hex())
+	}
+	return b
+}
+
+func TestNewTokenRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		isExit   bool
+		token    common.Address
+		amount   *big.Int
+		transfer bool
+		err      error
+	}{
+		{name: "PETH enter", token: testEtherToken, amount: big.NewInt(1e18), transfer: true},
+		{name: "PETH exit", isExit: true, token: testEtherToken, amount: big.NewInt(1e18)},
+		{name: "ERC20 enter", token: testToken, amount: big.NewInt(300)},
+		{name: "ERC20 exit", isExit: true, token: testToken, amount: big.NewInt(300)},
+		{name: "unmapped token", token: testUnmapped, amount: big.NewInt(300), err: ErrNotMapped},
+		{name: "zero amount", token: testToken, amount: big.NewInt(0), err: ErrZeroAmount},
+		{name: "nil amount", token: testEtherToken, amount: nil, err: ErrZeroAmount},
+	}
+
+	for _, tt := range tests {
+		b := newTestBuilder(t, newFakeBackend())
+
+		r, err := b.NewTokenRequest(tt.isExit, testRequestor, tt.token, tt.amount)
+		if err != tt.err {
+			t.Errorf("%s: error mismatch: have %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		if r.IsExit != tt.isExit || r.Requestor != testRequestor || r.To != tt.token {
+			t.Errorf("%s: request mismatch: %+v", tt.name, r)
+		}
+		if r.IsTransfer(b.EtherToken()) != tt.transfer {
+			t.Errorf("%s: transfer mismatch: have %v, want %v", tt.name, r.IsTransfer(b.EtherToken()), tt.transfer)
+		}
+		if want := testBalanceTrieKey(testRequestor); r.TrieKey != want {
+			t.Errorf("%s: trie key mismatch: have %s, want %s", tt.name, r.TrieKey.Hex(), want.Hex())
+		}
+		if want := common.LeftPadBytes(tt.amount.Bytes(), 32); !bytes.Equal(r.TrieValue, want) {
+			t.Errorf("%s: trie value mismatch: have %x, want %x", tt.name, r.TrieValue, want)
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	tests := []struct {
+		name   string
+		isExit bool
+		token  common.Address
+		amount *big.Int
+		method string
+		value  *big.Int
+	}{
+		{name: "PETH enter", token: testEtherToken, amount: big.NewInt(1e18), method: "startEnter", value: big.NewInt(0)},
+		{name: "PETH exit", isExit: true, token: testEtherToken, amount: big.NewInt(1e18), method: "startExit", value: testCostERO},
+		{name: "ERC20 enter", token: testToken, amount: big.NewInt(300), method: "startEnter", value: big.NewInt(0)},
+		{name: "ERC20 exit", isExit: true, token: testToken, amount: big.NewInt(300), method: "startExit", value: testCostERO},
+	}
+
+	for _, tt := range tests {
+		backend := newFakeBackend()
+		b := newTestBuilder(t, backend)
+
+		r, err := b.NewTokenRequest(tt.isExit, testRequestor, tt.token, tt.amount)
+		if err != nil {
+			t.Fatalf("%s: failed to build request: %v", tt.name, err)
+		}
+
+		tx, err := b.Send(bind.NewKeyedTransactor(testKey), r)
+		if err != nil {
+			t.Fatalf("%s: failed to send request: %v", tt.name, err)
+		}
+		if len(backend.sent) != 1 || backend.sent[0] != tx {
+			t.Fatalf("%s: transaction is not sent", tt.name)
+		}
+
+		want, _ := rootchainABI.Pack(tt.method, tt.token, testBalanceTrieKey(testRequestor), common.LeftPadBytes(tt.amount.Bytes(), 32))
+		if !bytes.Equal(tx.Data(), want) {
+			t.Errorf("%s: calldata mismatch: have %x, want %x", tt.name, tx.Data(), want)
+		}
+		if *tx.To() != testRootChain {
+			t.Errorf("%s: recipient mismatch: have %s, want %s", tt.name, tx.To().Hex(), testRootChain.Hex())
+		}
+		if tx.Value().Cmp(tt.value) != 0 {
+			t.Errorf("%s: value mismatch: have %v, want %v", tt.name, tx.Value(), tt.value)
+		}
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	tests := []struct {
+		name      string
+		isExit    bool
+		token     common.Address
+		reject    bool
+		simulated bool
+		err       error
+	}{
+		{name: "PETH enter", token: testEtherToken},
+		{name: "PETH exit", isExit: true, token: testEtherToken, simulated: true},
+		{name: "ERC20 enter", token: testToken, simulated: true},
+		{name: "ERC20 exit rejected", isExit: true, token: testToken, reject: true, simulated: true, err: ErrRequestRejected},
+	}
+
+	for _, tt := range tests {
+		backend := newFakeBackend()
+		b := newTestBuilder(t, backend)
+
+		r, err := b.NewTokenRequest(tt.isExit, testRequestor, tt.token, big.NewInt(300))
+		if err != nil {
+			t.Fatalf("%s: failed to build request: %v", tt.name, err)
+		}
+
+		backend.calls = nil
+		backend.reject = tt.reject
+
+		err = b.Simulate(context.Background(), r)
+		if (err == nil) != (tt.err == nil) || (err != nil && !strings.HasPrefix(err.Error(), tt.err.Error())) {
+			t.Errorf("%s: error mismatch: have %v, want %v", tt.name, err, tt.err)
+		}
+
+		var applied *ethereum.CallMsg
+		for i, call := range backend.calls {
+			if bytes.Equal(call.Data[:4], requestableContractABI.Methods["applyRequestInChildChain"].ID()) {
+				applied = &backend.calls[i]
+			}
+		}
+		if (applied != nil) != tt.simulated {
+			t.Errorf("%s: simulation mismatch: have %v, want %v", tt.name, applied != nil, tt.simulated)
+			continue
+		}
+		if applied == nil {
+			continue
+		}
+
+		want, _ := requestableContractABI.Pack("applyRequestInChildChain", tt.isExit, testNumEROs, testRequestor, r.TrieKey, r.TrieValue)
+		if !bytes.Equal(applied.Data, want) {
+			t.Errorf("%s: calldata mismatch: have %x, want %x", tt.name, applied.Data, want)
+		}
+	}
+}
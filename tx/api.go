@@ -0,0 +1,173 @@
+package tx
+
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/common/hexutil"
+	"github.com/Onther-Tech/plasma-evm/rpc"
+)
+
+// APIs returns the collection of RPC services the transaction manager offers.
+func (tm *TransactionManager) APIs() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "txmanager",
+			Version:   "1.0",
+			Service:   NewPrivateTransactionManagerAPI(tm),
+			Public:    false,
+		},
+	}
+}
+
+// PrivateTransactionManagerAPI provides private RPC methods to inspect and
+// control the transaction manager. These methods can be abused by external
+// users and must be considered insecure for use by untrusted users.
+type PrivateTransactionManagerAPI struct {
+	tm *TransactionManager
+}
+
+// NewPrivateTransactionManagerAPI creates a new RPC service which controls the
+// transaction manager.
+func NewPrivateTransactionManagerAPI(tm *TransactionManager) *PrivateTransactionManagerAPI {
+	return &PrivateTransactionManagerAPI{tm}
+}
+
+// Config returns the current gas price, gas price range and interval.
+func (api *PrivateTransactionManagerAPI) Config() map[string]interface{} {
+	minGasPrice, maxGasPrice := api.tm.GasPriceRange()
+
+	return map[string]interface{}{
+		"gasPrice":    (*hexutil.Big)(api.tm.GasPrice()),
+		"minGasPrice": (*hexutil.Big)(minGasPrice),
+		"maxGasPrice": (*hexutil.Big)(maxGasPrice),
+		"interval":    api.tm.Interval().String(),
+	}
+}
+
+// Accounts returns the accounts which have sent raw transactions.
+func (api *PrivateTransactionManagerAPI) Accounts() []common.Address {
+	return api.tm.Addresses()
+}
+
+// RawTransactions returns the pending and unconfirmed raw transactions of the
+// account.
+func (api *PrivateTransactionManagerAPI) RawTransactions(addr common.Address) map[string]interface{} {
+	pending, unconfirmed, numConfirmed, nonce := api.tm.Inspect(addr)
+
+	return map[string]interface{}{
+		"nonce":        hexutil.Uint64(nonce),
+		"pending":      rpcMarshalRawTransactions(pending),
+		"unconfirmed":  rpcMarshalRawTransactions(unconfirmed),
+		"numConfirmed": numConfirmed,
+	}
+}
+
+// Resend sends the pending raw transaction of the index again. If gasPrice is
+// given, gas price is raised to it and the raw transaction is resent in the
+// next interval. Otherwise, the last sent transaction is broadcast again.
+func (api *PrivateTransactionManagerAPI) Resend(addr common.Address, index hexutil.Uint64, gasPrice *hexutil.Big) (bool, error) {
+	if err := api.tm.Resend(addr, uint64(index), (*big.Int)(gasPrice)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Cancel removes the pending raw transaction of the index which is not sent yet.
+func (api *PrivateTransactionManagerAPI) Cancel(addr common.Address, index hexutil.Uint64) (bool, error) {
+	if err := api.tm.Cancel(addr, uint64(index)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CancelStuck replaces the transaction of the first pending raw transaction of
+// the account with a zero-value self-transfer at a higher gas price. The raw
+// transaction is sent again with the next nonce, or by another pool account if
+// a raw transaction of the account is waiting for it.
+func (api *PrivateTransactionManagerAPI) CancelStuck(addr common.Address) (common.Hash, error) {
+	return api.tm.CancelStuck(addr)
+}
+
+// ReconcileNonce repairs the nonces of the account drifted from root chain.
+func (api *PrivateTransactionManagerAPI) ReconcileNonce(addr common.Address) (bool, error) {
+	if err := api.tm.ReconcileNonce(addr); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetMinGasPrice sets the minimum gas price to send raw transactions.
+func (api *PrivateTransactionManagerAPI) SetMinGasPrice(gasPrice hexutil.Big) (bool, error) {
+	_, maxGasPrice := api.tm.GasPriceRange()
+	if err := api.tm.SetGasPriceRange((*big.Int)(&gasPrice), maxGasPrice); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetMaxGasPrice sets the maximum gas price to send raw transactions.
+func (api *PrivateTransactionManagerAPI) SetMaxGasPrice(gasPrice hexutil.Big) (bool, error) {
+	minGasPrice, _ := api.tm.GasPriceRange()
+	if err := api.tm.SetGasPriceRange(minGasPrice, (*big.Int)(&gasPrice)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetInterval sets the interval to send raw transactions, e.g., "10s".
+func (api *PrivateTransactionManagerAPI) SetInterval(interval string) (bool, error) {
+	d, err := time.ParseDuration(interval)
+	if err != nil {
+		return false, errors.New("invalid interval: " + err.Error())
+	}
+	if err := api.tm.SetInterval(d); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func rpcMarshalRawTransactions(raws RawTransactions) []map[string]interface{} {
+	fields := make([]map[string]interface{}, 0, len(raws))
+	for _, raw := range raws {
+		fields = append(fields, rpcMarshalRawTransaction(raw))
+	}
+	return fields
+}
+
+// rpcMarshalRawTransaction converts the raw transaction to the RPC output.
+func rpcMarshalRawTransaction(raw *RawTransaction) map[string]interface{} {
+	raw.lock.RLock()
+	defer raw.lock.RUnlock()
+
+	pendingTxs := make([]map[string]interface{}, 0, len(raw.PendingTxs))
+	for _, tx := range raw.PendingTxs {
+		pendingTxs = append(pendingTxs, map[string]interface{}{
+			"hash":     tx.Hash(),
+			"gasPrice": (*hexutil.Big)(tx.GasPrice()),
+		})
+	}
+
+	fields := map[string]interface{}{
+		"index":               hexutil.Uint64(raw.Index),
+		"caption":             raw.Caption,
+		"from":                raw.From,
+		"to":                  raw.Recipient,
+		"value":               (*hexutil.Big)(raw.Amount),
+		"gas":                 hexutil.Uint64(raw.GasLimit),
+		"nonce":               (*hexutil.Big)(raw.Nonce),
+		"resendCount":         hexutil.Uint64(raw.ResendCount),
+		"lastSentBlockNumber": hexutil.Uint64(raw.LastSentBlockNumber),
+		"pendingTxs":          pendingTxs,
+		"reverted":            raw.Reverted,
+	}
+
+	if (raw.MinedTxHash != common.Hash{}) {
+		fields["minedTxHash"] = raw.MinedTxHash
+		fields["minedBlockNumber"] = (*hexutil.Big)(raw.MinedBlockNumber)
+	}
+
+	return fields
+}
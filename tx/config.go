@@ -13,6 +13,11 @@ var DefaultConfig = &Config{
 	MaxGasPrice: new(big.Int).SetInt64(100 * params.GWei),
 	Interval:    10 * time.Second,
 	ChainId:     new(big.Int).SetInt64(1),
+
+	GasPriceStrategy: FixedStepStrategy,
+	OracleBlocks:     20,
+	OraclePercentile: 60,
+	DeadlineWindow:   time.Hour,
 }
 
 type Config struct {
@@ -21,4 +26,16 @@ type Config struct {
 	MaxGasPrice *big.Int
 	ChainId     *big.Int
 	Interval    time.Duration
+
+	// GasPriceStrategy is the default gas price strategy of raw transactions.
+	GasPriceStrategy string
+
+	OracleBlocks     uint64        // number of recent blocks to suggest gas price
+	OraclePercentile int           // percentile of gas prices in recent blocks
+	DeadlineWindow   time.Duration // period to escalate gas price before deadline
+
+	// FillNonceGap enables filling nonce gaps before sent transactions with
+	// self-transfers. It is disabled by default because a lagging root chain
+	// provider reports a pending nonce lower than the actual one.
+	FillNonceGap bool
 }
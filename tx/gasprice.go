@@ -0,0 +1,254 @@
+package tx
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Onther-Tech/plasma-evm/core/types"
+	"github.com/Onther-Tech/plasma-evm/log"
+)
+
+const (
+	FixedStepStrategy = "fixed"    // steps gas price up on failure and down on success
+	OracleStrategy    = "oracle"   // percentile of gas prices in recent blocks
+	DeadlineStrategy  = "deadline" // oracle price escalated toward the deadline
+)
+
+// GasPriceRequest is the information to decide the gas price of a raw
+// transaction.
+type GasPriceRequest struct {
+	Raw *RawTransaction
+
+	// Previous is the gas price of the last transaction sent for the raw
+	// transaction, or nil if it is not sent yet.
+	Previous *big.Int
+
+	// Bump is true if the last transaction must be replaced with higher gas
+	// price, e.g., it is underpriced or not mined for a while.
+	Bump bool
+
+	MinGasPrice *big.Int
+	MaxGasPrice *big.Int
+}
+
+// GasPriceStrategy decides the gas price of raw transactions. Gas price
+// returned by a strategy is adjusted into the range of the transaction manager.
+type GasPriceStrategy interface {
+	// GasPrice returns the gas price to send the raw transaction.
+	GasPrice(req *GasPriceRequest) (*big.Int, error)
+
+	// Mined is called when the raw transaction is mined with the gas price.
+	Mined(raw *RawTransaction, gasPrice *big.Int)
+}
+
+// fixedStepStrategy shares a single gas price between raw transactions. The gas
+// price is raised by 20% if a transaction should be replaced, and it is
+// lowered to 40% of the gas price of a mined transaction.
+type fixedStepStrategy struct {
+	tm *TransactionManager
+}
+
+func (s *fixedStepStrategy) GasPrice(req *GasPriceRequest) (*big.Int, error) {
+	if !req.Bump {
+		return s.tm.GasPrice(), nil
+	}
+
+	previous := req.Previous
+	if previous == nil {
+		previous = s.tm.GasPrice()
+	}
+
+	// new gas price = previous gas price * 1.2
+	gasPrice := new(big.Int).Mul(new(big.Int).Div(previous, big.NewInt(10)), big.NewInt(12))
+	s.tm.setGasPrice(gasPrice)
+
+	return gasPrice, nil
+}
+
+func (s *fixedStepStrategy) Mined(raw *RawTransaction, gasPrice *big.Int) {
+	// new gas price = previous gas price * 0.4
+	s.tm.setGasPrice(new(big.Int).Mul(new(big.Int).Div(gasPrice, big.NewInt(10)), big.NewInt(4)))
+}
+
+// oracleBackend is the root chain backend to read recent blocks.
+type oracleBackend interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+}
+
+// oracleStrategy suggests the percentile of gas prices of transactions in
+// recent root chain blocks.
+type oracleStrategy struct {
+	backend    oracleBackend
+	blocks     uint64
+	percentile int
+
+	prices map[uint64][]*big.Int // block number => gas prices
+
+	lock sync.Mutex
+}
+
+func newOracleStrategy(backend oracleBackend, blocks uint64, percentile int) *oracleStrategy {
+	if blocks == 0 {
+		blocks = DefaultConfig.OracleBlocks
+	}
+	if percentile < 0 || percentile > 100 {
+		percentile = DefaultConfig.OraclePercentile
+	}
+
+	return &oracleStrategy{
+		backend:    backend,
+		blocks:     blocks,
+		percentile: percentile,
+		prices:     make(map[uint64][]*big.Int),
+	}
+}
+
+func (s *oracleStrategy) GasPrice(req *GasPriceRequest) (*big.Int, error) {
+	gasPrice, err := s.suggest()
+	if err != nil {
+		return nil, err
+	}
+
+	// use min gas price if there is no transaction in recent blocks.
+	if gasPrice == nil {
+		gasPrice = new(big.Int).Set(req.MinGasPrice)
+	}
+	return gasPrice, nil
+}
+
+func (s *oracleStrategy) Mined(raw *RawTransaction, gasPrice *big.Int) {}
+
+// suggest returns the percentile of gas prices in recent blocks. Gas prices of
+// each block are cached until the block gets old. Blocks not cached are
+// fetched concurrently without the lock.
+func (s *oracleStrategy) suggest() (*big.Int, error) {
+	head, err := s.backend.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var start uint64
+	if head.Number.Uint64()+1 > s.blocks {
+		start = head.Number.Uint64() + 1 - s.blocks
+	}
+
+	var (
+		prices  []*big.Int
+		missing []uint64
+	)
+
+	s.lock.Lock()
+	for n := range s.prices {
+		if n < start {
+			delete(s.prices, n)
+		}
+	}
+	for n := start; n <= head.Number.Uint64(); n++ {
+		if blockPrices, ok := s.prices[n]; ok {
+			prices = append(prices, blockPrices...)
+		} else {
+			missing = append(missing, n)
+		}
+	}
+	s.lock.Unlock()
+
+	fetched, err := s.fetchPrices(missing)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	for n, blockPrices := range fetched {
+		s.prices[n] = blockPrices
+		prices = append(prices, blockPrices...)
+	}
+	s.lock.Unlock()
+
+	if len(prices) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(prices, func(i, j int) bool { return prices[i].Cmp(prices[j]) < 0 })
+	return new(big.Int).Set(prices[(len(prices)-1)*s.percentile/100]), nil
+}
+
+// fetchPrices returns the gas prices of transactions in the blocks.
+func (s *oracleStrategy) fetchPrices(numbers []uint64) (map[uint64][]*big.Int, error) {
+	var (
+		prices = make([][]*big.Int, len(numbers))
+		errs   = make([]error, len(numbers))
+		wg     sync.WaitGroup
+	)
+
+	for i, n := range numbers {
+		wg.Add(1)
+		go func(i int, n uint64) {
+			defer wg.Done()
+
+			block, err := s.backend.BlockByNumber(context.Background(), new(big.Int).SetUint64(n))
+			if err != nil {
+				errs[i] = err
+				return
+			}
+
+			txs := block.Transactions()
+			prices[i] = make([]*big.Int, len(txs))
+			for j, tx := range txs {
+				prices[i][j] = tx.GasPrice()
+			}
+		}(i, n)
+	}
+	wg.Wait()
+
+	fetched := make(map[uint64][]*big.Int, len(numbers))
+	for i, n := range numbers {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		fetched[n] = prices[i]
+	}
+	return fetched, nil
+}
+
+// deadlineStrategy escalates the gas price suggested by the base strategy as
+// the deadline of the raw transaction nears, e.g., the end of a challenge
+// period. The gas price reaches the max gas price at the deadline. Raw
+// transactions without deadline are priced by the base strategy.
+type deadlineStrategy struct {
+	base   GasPriceStrategy
+	window time.Duration
+}
+
+func (s *deadlineStrategy) GasPrice(req *GasPriceRequest) (*big.Int, error) {
+	gasPrice, err := s.base.GasPrice(req)
+	if err != nil || req.Raw.Deadline == 0 {
+		return gasPrice, err
+	}
+
+	remaining := int64(req.Raw.Deadline) - time.Now().Unix()
+	window := int64(s.window / time.Second)
+
+	if remaining <= 0 {
+		return new(big.Int).Set(req.MaxGasPrice), nil
+	}
+	if remaining >= window || gasPrice.Cmp(req.MaxGasPrice) >= 0 {
+		return gasPrice, nil
+	}
+
+	// gas price = base + (max - base) * elapsed / window
+	escalation := new(big.Int).Sub(req.MaxGasPrice, gasPrice)
+	escalation.Mul(escalation, big.NewInt(window-remaining))
+	escalation.Div(escalation, big.NewInt(window))
+
+	log.Debug("Gas price is escalated toward deadline", "caption", req.Raw.getCaption(), "remaining", time.Duration(remaining)*time.Second, "escalation", gasPriceToString(escalation))
+
+	return gasPrice.Add(gasPrice, escalation), nil
+}
+
+func (s *deadlineStrategy) Mined(raw *RawTransaction, gasPrice *big.Int) {
+	s.base.Mined(raw, gasPrice)
+}
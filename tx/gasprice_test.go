@@ -0,0 +1,165 @@
+package tx
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/core/types"
+)
+
+// constStrategy prices every raw transaction at the same gas price.
+type constStrategy struct {
+	gasPrice *big.Int
+	err      error
+}
+
+func (s *constStrategy) GasPrice(req *GasPriceRequest) (*big.Int, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return new(big.Int).Set(s.gasPrice), nil
+}
+
+func (s *constStrategy) Mined(raw *RawTransaction, gasPrice *big.Int) {}
+
+func TestDeadlineStrategy(t *testing.T) {
+	const window = 1000 * time.Second
+
+	var (
+		now     = time.Now().Unix()
+		base    = big.NewInt(10)
+		max     = big.NewInt(110)
+		errBase = errors.New("no gas price")
+	)
+
+	tests := []struct {
+		name     string
+		base     *big.Int
+		err      error
+		deadline int64 // zero if the raw transaction has no deadline
+		want     int64
+	}{
+		{name: "no deadline", base: base, want: 10},
+		{name: "before window", base: base, deadline: now + 2000, want: 10},
+		{name: "window start", base: base, deadline: now + 1000, want: 10},
+		{name: "within window", base: base, deadline: now + 500, want: 60},
+		{name: "past deadline", base: base, deadline: now - 10, want: 110},
+		{name: "at max", base: max, deadline: now + 500, want: 110},
+		{name: "above max", base: big.NewInt(200), deadline: now + 500, want: 200},
+		{name: "base error", err: errBase, deadline: now + 500},
+	}
+
+	for _, tt := range tests {
+		s := &deadlineStrategy{&constStrategy{tt.base, tt.err}, window}
+		raw := &RawTransaction{Caption: tt.name}
+		if tt.deadline != 0 {
+			raw.Deadline = uint64(tt.deadline)
+		}
+
+		gasPrice, err := s.GasPrice(&GasPriceRequest{Raw: raw, MinGasPrice: big.NewInt(1), MaxGasPrice: max})
+		if err != tt.err {
+			t.Errorf("%s: error mismatch: have %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if gasPrice.Int64() != tt.want {
+			t.Errorf("%s: gas price mismatch: have %v, want %d", tt.name, gasPrice, tt.want)
+		}
+	}
+}
+
+// fakeOracleBackend serves blocks of transactions priced at the given gas
+// prices.
+type fakeOracleBackend struct {
+	blocks [][]int64 // gas prices of transactions in each block
+
+	fetched map[uint64]int // number of fetches of each block
+	lock    sync.Mutex
+}
+
+func (b *fakeOracleBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Number: big.NewInt(int64(len(b.blocks) - 1))}, nil
+}
+
+func (b *fakeOracleBackend) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	n := number.Uint64()
+	if n >= uint64(len(b.blocks)) {
+		return nil, errors.New("unknown block")
+	}
+	b.fetched[n]++
+
+	var txs types.Transactions
+	for i, gasPrice := range b.blocks[n] {
+		txs = append(txs, types.NewTransaction(uint64(i), common.Address{}, big.NewInt(0), 21000, big.NewInt(gasPrice), nil))
+	}
+	return types.NewBlock(&types.Header{Number: number}, txs, nil, nil), nil
+}
+
+func TestOracleStrategy(t *testing.T) {
+	// gas prices 1 to 9 in the recent 3 blocks. Block#0 is out of the range.
+	blocks := [][]int64{{100, 100}, {3, 1, 2}, {}, {6, 4, 5, 9, 7, 8}}
+
+	tests := []struct {
+		blocks     [][]int64
+		percentile int
+		want       int64
+	}{
+		{blocks: blocks, percentile: 0, want: 1},
+		{blocks: blocks, percentile: 50, want: 5},
+		{blocks: blocks, percentile: 60, want: 5},
+		{blocks: blocks, percentile: 100, want: 9},
+		// out of bounds percentile is the default percentile.
+		{blocks: blocks, percentile: -1, want: 5},
+		{blocks: blocks, percentile: 101, want: 5},
+		// no transaction in recent blocks.
+		{blocks: [][]int64{{100}, {}, {}, {}}, percentile: 50, want: 3},
+		// fewer blocks than the range.
+		{blocks: [][]int64{{2, 1}}, percentile: 100, want: 2},
+	}
+
+	for i, tt := range tests {
+		backend := &fakeOracleBackend{blocks: tt.blocks, fetched: make(map[uint64]int)}
+		s := newOracleStrategy(backend, 3, tt.percentile)
+
+		gasPrice, err := s.GasPrice(&GasPriceRequest{MinGasPrice: big.NewInt(3), MaxGasPrice: big.NewInt(1000)})
+		if err != nil {
+			t.Fatalf("test %d: failed to get gas price: %v", i, err)
+		}
+		if gasPrice.Int64() != tt.want {
+			t.Errorf("test %d: gas price mismatch: have %v, want %d", i, gasPrice, tt.want)
+		}
+	}
+}
+
+func TestOracleStrategyCache(t *testing.T) {
+	backend := &fakeOracleBackend{blocks: [][]int64{{1}, {2}, {3}}, fetched: make(map[uint64]int)}
+	s := newOracleStrategy(backend, 2, 100)
+
+	for i := 0; i < 2; i++ {
+		if gasPrice, err := s.suggest(); err != nil || gasPrice.Int64() != 3 {
+			t.Fatalf("Gas price mismatch: have %v %v, want %d", gasPrice, err, 3)
+		}
+	}
+	if backend.fetched[0] != 0 || backend.fetched[1] != 1 || backend.fetched[2] != 1 {
+		t.Fatalf("Blocks are not fetched once: %v", backend.fetched)
+	}
+
+	// old block is removed from the cache when a new block arrives.
+	backend.blocks = append(backend.blocks, []int64{4})
+	if gasPrice, err := s.suggest(); err != nil || gasPrice.Int64() != 4 {
+		t.Fatalf("Gas price mismatch: have %v %v, want %d", gasPrice, err, 4)
+	}
+	if _, ok := s.prices[1]; ok || len(s.prices) != 2 {
+		t.Fatalf("Old block is not removed from the cache: %v", s.prices)
+	}
+}
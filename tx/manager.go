@@ -12,12 +12,12 @@ import (
 
 	"github.com/Onther-Tech/plasma-evm"
 	"github.com/Onther-Tech/plasma-evm/accounts"
-	"github.com/Onther-Tech/plasma-evm/accounts/keystore"
 	"github.com/Onther-Tech/plasma-evm/common"
 	"github.com/Onther-Tech/plasma-evm/core"
 	"github.com/Onther-Tech/plasma-evm/core/types"
 	"github.com/Onther-Tech/plasma-evm/ethclient"
 	"github.com/Onther-Tech/plasma-evm/ethdb"
+	"github.com/Onther-Tech/plasma-evm/event"
 	"github.com/Onther-Tech/plasma-evm/log"
 	"github.com/Onther-Tech/plasma-evm/params"
 )
@@ -38,18 +38,24 @@ var (
 	ErrKnownTransaction = errors.New("known transaction")
 	ErrDuplicateRaw     = errors.New("duplicate raw transaction")
 	ErrNoDuplicateRaw   = errors.New("there is no duplicate raw transaction")
+	ErrUnknownRaw       = errors.New("raw transaction not found in pending queue")
+	ErrNotSentRaw       = errors.New("raw transaction is not sent yet")
+	ErrSentRaw          = errors.New("raw transaction is already sent")
+	ErrMinedRaw         = errors.New("raw transaction is already mined")
+	ErrGasPriceRange    = errors.New("gas price is out of range")
 )
 
-// TODO: Add JSONRPC API for TransactionManager
 type TransactionManager struct {
 	config *Config
 
-	ks      *keystore.KeyStore
+	am      *accounts.Manager // finds wallets to sign transactions
 	backend *ethclient.Client
 	db      ethdb.Database
 
 	currentBlockNumber *big.Int // current block number of root chian network
-	gasPrice           *big.Int
+	gasPrice           *big.Int // gas price of fixed step strategy
+
+	strategies map[string]GasPriceStrategy
 
 	addresses []common.Address // list of account address
 
@@ -57,25 +63,36 @@ type TransactionManager struct {
 	unconfirmed map[common.Address]RawTransactions // mined but not confirmed raw transactions
 	pending     map[common.Address]RawTransactions // raw transactions to be sent
 
-	nonce map[common.Address]uint64 // account nonce
+	pools map[string][]accounts.Account // accounts sending raw transactions in order
+
+	nonce          map[common.Address]uint64    // account nonce
+	lastReconciled map[common.Address]time.Time // last time the nonce is reconciled with root chain
 
 	lastInspectTime time.Time
 
 	numKnownErr map[common.Hash]uint64 // number of know transaction error
 
-	taskCh chan *RawTransaction
+	taskCh     chan *RawTransaction
+	intervalCh chan struct{} // notifies the send loop that the interval is changed
+
+	minedFeed     event.Feed
+	confirmedFeed event.Feed
+	cancelledFeed event.Feed
+	scope         event.SubscriptionScope
 
 	lock         sync.RWMutex
+	cancelLock   sync.Mutex
 	gasPriceLock sync.Mutex
+	strategyLock sync.RWMutex
 	wg           *sync.WaitGroup
 	quit         chan struct{}
 }
 
-func NewTransactionManager(ks *keystore.KeyStore, backend *ethclient.Client, db ethdb.Database, config *Config) (*TransactionManager, error) {
+func NewTransactionManager(am *accounts.Manager, backend *ethclient.Client, db ethdb.Database, config *Config) (*TransactionManager, error) {
 	tm := &TransactionManager{
 		config: config,
 
-		ks:      ks,
+		am:      am,
 		db:      db,
 		backend: backend,
 
@@ -86,11 +103,15 @@ func NewTransactionManager(ks *keystore.KeyStore, backend *ethclient.Client, db
 		unconfirmed: make(map[common.Address]RawTransactions),
 		pending:     make(map[common.Address]RawTransactions),
 
-		nonce: make(map[common.Address]uint64),
+		pools: make(map[string][]accounts.Account),
+
+		nonce:          make(map[common.Address]uint64),
+		lastReconciled: make(map[common.Address]time.Time),
 
 		numKnownErr: make(map[common.Hash]uint64),
 
-		taskCh: make(chan *RawTransaction, MaxNumTask),
+		taskCh:     make(chan *RawTransaction, MaxNumTask),
+		intervalCh: make(chan struct{}, 1),
 
 		wg:   new(sync.WaitGroup),
 		quit: make(chan struct{}),
@@ -118,6 +139,21 @@ func NewTransactionManager(ks *keystore.KeyStore, backend *ethclient.Client, db
 	}
 
 	tm.gasPrice = gasPrice
+	gasPriceGauge.Update(gasPrice.Int64())
+
+	oracle := newOracleStrategy(backend, config.OracleBlocks, config.OraclePercentile)
+	tm.strategies = map[string]GasPriceStrategy{
+		FixedStepStrategy: &fixedStepStrategy{tm},
+		OracleStrategy:    oracle,
+		DeadlineStrategy:  &deadlineStrategy{oracle, config.DeadlineWindow},
+	}
+
+	if config.GasPriceStrategy == "" {
+		config.GasPriceStrategy = FixedStepStrategy
+	}
+	if _, ok := tm.strategies[config.GasPriceStrategy]; !ok {
+		return nil, fmt.Errorf("unknown gas price strategy: %s", config.GasPriceStrategy)
+	}
 
 	numAddrs := ReadNumAddr(db)
 
@@ -178,11 +214,17 @@ func (tm *TransactionManager) Add(account accounts.Account, raw *RawTransaction,
 	tm.lock.Lock()
 	defer tm.lock.Unlock()
 
+	return tm.add(account, raw, duplicate)
+}
+
+// add adds raw transaction to pending queue of the account. The caller must
+// hold tm.lock.
+func (tm *TransactionManager) add(account accounts.Account, raw *RawTransaction, duplicate bool) error {
 	addr := account.Address
 
 	tm.inspect(addr)
 
-	if !tm.ks.HasAddress(addr) {
+	if _, err := tm.am.Find(account); err != nil {
 		return ErrUnknownAccount
 	}
 
@@ -208,7 +250,7 @@ func (tm *TransactionManager) Add(account accounts.Account, raw *RawTransaction,
 		if previous := ReadRawTxHash(tm.db, addr, raw.Hash()); previous != nil {
 			return ErrDuplicateRaw
 		}
-		WriteRawTxHash(tm.db, addr, *raw)
+		WriteRawTxHash(tm.db, addr, raw)
 	} else {
 		// add duplicate raw transaction
 
@@ -258,7 +300,30 @@ func (tm *TransactionManager) Count(account accounts.Account, tx *types.Transact
 	return count
 }
 
+// NumPending returns the number of raw transactions of the account not mined yet.
+func (tm *TransactionManager) NumPending(account accounts.Account) int {
+	tm.lock.RLock()
+	defer tm.lock.RUnlock()
+
+	return len(tm.pending[account.Address])
+}
+
+// GasPrice returns current gas price to send transactions.
+func (tm *TransactionManager) GasPrice() *big.Int {
+	tm.gasPriceLock.Lock()
+	defer tm.gasPriceLock.Unlock()
+
+	return new(big.Int).Set(tm.gasPrice)
+}
+
 func (tm *TransactionManager) Start() {
+	// reconcile nonces drifted while the node was stopped.
+	for _, addr := range tm.Addresses() {
+		if err := tm.ReconcileNonce(addr); err != nil {
+			log.Error("Failed to reconcile account nonce", "addr", addr, "err", err)
+		}
+	}
+
 	tm.wg.Add(1)
 	go tm.confirmLoop()
 
@@ -273,6 +338,16 @@ func (tm *TransactionManager) Start() {
 			return raw.MinedTxHash, nil
 		}
 
+		// short circuit if raw transaction is cancelled
+		if raw.cancelled {
+			return common.Hash{}, nil
+		}
+
+		// short circuit if raw transaction is handed over to another account
+		if !tm.isPending(addr, raw) {
+			return common.Hash{}, nil
+		}
+
 		// subscribe new block mined event
 		newHeaderEvents := make(chan *types.Header)
 		newHeaderSub, err := tm.backend.SubscribeNewHead(context.Background(), newHeaderEvents)
@@ -314,15 +389,13 @@ func (tm *TransactionManager) Start() {
 				return common.Hash{}, nil
 			}
 
-			tm.gasPriceLock.Lock()
-			tx := raw.ToTransaction(tm.gasPrice)
-			tm.gasPriceLock.Unlock()
+			tx := raw.ToTransaction(tm.nextGasPrice(raw))
 
-			signedTx, err := tm.ks.SignTx(from, tx, tm.config.ChainId)
+			signedTx, err := tm.signTx(from, tx)
 
 			if err != nil {
 				log.Error("failed to sign transaction", "err", err, "raw", raw.Hash(), "caption", raw.getCaption(), "tx", tx.Hash())
-				return signedTx.Hash(), err
+				return common.Hash{}, err
 			}
 
 			// short circuit raw transaction already has same transaction.
@@ -330,6 +403,7 @@ func (tm *TransactionManager) Start() {
 				return signedTx.Hash(), nil
 			}
 
+			resend := raw.NumPending() > 0 || raw.ResendCount > 0
 			err = raw.AddPending(signedTx)
 			if err != nil {
 				log.Error(err.Error(), "raw", raw.Hash(), "caption", raw.getCaption(), "tx", tx.Hash())
@@ -344,6 +418,10 @@ func (tm *TransactionManager) Start() {
 			err = tm.backend.SendTransaction(context.Background(), signedTx)
 
 			if err == nil {
+				sentMeter.Mark(1)
+				if resend {
+					resendMeter.Mark(1)
+				}
 				log.Info("Transaction sent", "hash", signedTx.Hash(), "nonce", raw.Nonce, "caption", raw.getCaption(), "gasprice", signedTx.GasPrice())
 				return signedTx.Hash(), nil
 			}
@@ -414,10 +492,18 @@ func (tm *TransactionManager) Start() {
 
 	go func() {
 		ticker := time.NewTicker(tm.config.Interval)
-		defer ticker.Stop()
+		defer func() { ticker.Stop() }()
 
 		for {
 			select {
+			case <-tm.intervalCh:
+				tm.gasPriceLock.Lock()
+				interval := tm.config.Interval
+				tm.gasPriceLock.Unlock()
+
+				ticker.Stop()
+				ticker = time.NewTicker(interval)
+
 			case _, ok := <-ticker.C:
 				if !ok {
 					continue
@@ -433,6 +519,12 @@ func (tm *TransactionManager) Start() {
 						tm.clearQueue(addr)
 						tm.confirmQueue(addr)
 
+						if tm.shouldReconcile(addr) {
+							if err := tm.ReconcileNonce(addr); err != nil {
+								log.Error("Failed to reconcile account nonce", "addr", addr, "err", err)
+							}
+						}
+
 						if len(queue) == 0 {
 							return
 						}
@@ -452,6 +544,12 @@ func (tm *TransactionManager) Start() {
 							return
 						}
 
+						// wait until the previous raw transaction in pool is mined
+						if tm.waitingPrev(raw) {
+							log.Debug("Wait for previous raw transaction to be mined", "caption", raw.getCaption(), "prev", raw.Prev.From, "index", raw.Prev.Index)
+							return
+						}
+
 						hash, err := send(addr, raw)
 
 						// resubmit transaction in pending intarval loop
@@ -517,61 +615,367 @@ func (tm *TransactionManager) Start() {
 	}()
 }
 
-// adjustGasPrice adjust gas prices at a reasonable price.
-func (tm *TransactionManager) adjustGasPrice(raw *RawTransaction, decrease bool) {
+// signTx signs the transaction with the wallet of the account. The wallet may
+// be a keystore, an external signer like clef or a USB hardware wallet.
+func (tm *TransactionManager) signTx(account accounts.Account, tx *types.Transaction) (*types.Transaction, error) {
+	wallet, err := tm.am.Find(account)
+	if err != nil {
+		return nil, ErrUnknownAccount
+	}
+	return wallet.SignTx(account, tx, tm.config.ChainId)
+}
+
+// Inspect returns the pending and unconfirmed raw transactions, the number of
+// confirmed raw transactions and the next nonce of the account.
+func (tm *TransactionManager) Inspect(addr common.Address) (pending, unconfirmed RawTransactions, numConfirmed int, nonce uint64) {
+	tm.lock.RLock()
+	defer tm.lock.RUnlock()
+
+	pending = append(RawTransactions{}, tm.pending[addr]...)
+	unconfirmed = append(RawTransactions{}, tm.unconfirmed[addr]...)
+	return pending, unconfirmed, len(tm.confirmed[addr]), tm.nonce[addr]
+}
+
+// pendingRaw returns the pending raw transaction of the index. The caller must
+// hold tm.lock.
+func (tm *TransactionManager) pendingRaw(addr common.Address, index uint64) (int, *RawTransaction) {
+	for i, raw := range tm.pending[addr] {
+		if raw.Index == index {
+			return i, raw
+		}
+	}
+	return -1, nil
+}
+
+// isPending returns true if the raw transaction is in pending queue of the
+// account.
+func (tm *TransactionManager) isPending(addr common.Address, raw *RawTransaction) bool {
+	tm.lock.RLock()
+	defer tm.lock.RUnlock()
+
+	for _, r := range tm.pending[addr] {
+		if r == raw {
+			return true
+		}
+	}
+	return false
+}
+
+// Resend sends the pending raw transaction again. If gasPrice is nil, the last
+// transaction sent for the raw transaction is broadcast again. Otherwise, the
+// raw transaction is sent with gasPrice in the next interval.
+func (tm *TransactionManager) Resend(addr common.Address, index uint64, gasPrice *big.Int) error {
+	tm.lock.RLock()
+	_, raw := tm.pendingRaw(addr, index)
+	tm.lock.RUnlock()
+
+	if raw == nil {
+		return ErrUnknownRaw
+	}
+	if raw.Mined(tm.backend) {
+		return ErrMinedRaw
+	}
+
+	if gasPrice == nil {
+		raw.lock.RLock()
+		if len(raw.PendingTxs) == 0 {
+			raw.lock.RUnlock()
+			return ErrNotSentRaw
+		}
+		last := raw.PendingTxs[len(raw.PendingTxs)-1]
+		raw.lock.RUnlock()
+
+		err := tm.backend.SendTransaction(context.Background(), last)
+		if err != nil && !strings.Contains(strings.ToLower(err.Error()), "known transaction") {
+			return err
+		}
+
+		resendMeter.Mark(1)
+		log.Info("Transaction is sent again", "hash", last.Hash(), "nonce", last.Nonce(), "caption", raw.getCaption(), "gasprice", last.GasPrice())
+		return nil
+	}
+
+	minGasPrice, maxGasPrice := tm.GasPriceRange()
+	if gasPrice.Cmp(minGasPrice) < 0 || gasPrice.Cmp(maxGasPrice) > 0 {
+		return ErrGasPriceRange
+	}
+
+	raw.lock.Lock()
+	raw.nextGasPrice = new(big.Int).Set(gasPrice)
+	raw.lock.Unlock()
+
+	raw.sendLock.Lock()
+	raw.LastSentBlockNumber = 0
+	raw.sendLock.Unlock()
+
+	log.Info("Raw transaction will be resent", "caption", raw.getCaption(), "gasprice", gasPriceToString(gasPrice))
+	return nil
+}
+
+// Cancel removes the pending raw transaction which is not sent yet. Nonces of
+// the following raw transactions are decreased by one.
+func (tm *TransactionManager) Cancel(addr common.Address, index uint64) error {
+	tm.lock.RLock()
+	i, raw := tm.pendingRaw(addr, index)
+	var following RawTransactions
+	if raw != nil {
+		following = append(following, tm.pending[addr][i+1:]...)
+	}
+	tm.lock.RUnlock()
+
+	if raw == nil {
+		return ErrUnknownRaw
+	}
+
+	// check mined status before taking the locks. Raw transactions sent after
+	// this are checked again by the number of pending transactions.
+	for _, r := range append(RawTransactions{raw}, following...) {
+		if r.NumPending() > 0 || r.Mined(tm.backend) {
+			return errSentRaw(raw, r)
+		}
+	}
+
+	// notify the cancellation after the locks are released.
+	var cancelled bool
+	defer func() {
+		if cancelled {
+			tm.cancelledFeed.Send(raw)
+		}
+	}()
+
+	// block sending the raw transaction while it is cancelled. raw.sendLock
+	// must be acquired before tm.lock as send does.
+	raw.sendLock.Lock()
+	defer raw.sendLock.Unlock()
+
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	i, _ = tm.pendingRaw(addr, index)
+	if i < 0 {
+		return ErrUnknownRaw
+	}
+
+	following = tm.pending[addr][i+1:]
+	for _, r := range append(RawTransactions{raw}, following...) {
+		if r.NumPending() > 0 {
+			return errSentRaw(raw, r)
+		}
+	}
+
+	raw.cancelled, cancelled = true, true
+	for _, r := range following {
+		r.Nonce = new(big.Int).Sub(r.Nonce, big.NewInt(1))
+	}
+	tm.nonce[addr]--
+	WriteAddrNonce(tm.db, addr, tm.nonce[addr])
+
+	tm.pending[addr] = append(tm.pending[addr][:i:i], following...)
+	WritePendingTxs(tm.db, addr, tm.pending[addr])
+	DeleteRawTxHash(tm.db, addr, raw.Hash())
+	tm.updateGauges(addr)
+
+	log.Info("Raw transaction is cancelled", "addr", addr, "caption", raw.getCaption(), "nonce", raw.Nonce)
+	return nil
+}
+
+// errSentRaw returns the error cancelling the raw transaction when r, the raw
+// transaction or one of the following raw transactions, is already sent.
+func errSentRaw(raw, r *RawTransaction) error {
+	if r == raw {
+		return ErrSentRaw
+	}
+	return fmt.Errorf("following raw transaction %q is already sent", r.getCaption())
+}
+
+// SetGasPriceRange changes the min and max gas price. Current gas price is
+// adjusted into the range.
+func (tm *TransactionManager) SetGasPriceRange(minGasPrice, maxGasPrice *big.Int) error {
+	if minGasPrice.Sign() <= 0 || minGasPrice.Cmp(maxGasPrice) > 0 {
+		return errors.New("min gas price cannot exceed max gas price")
+	}
+
 	tm.gasPriceLock.Lock()
 	defer tm.gasPriceLock.Unlock()
 
-	var previousGasPrice *big.Int
+	tm.config.MinGasPrice = new(big.Int).Set(minGasPrice)
+	tm.config.MaxGasPrice = new(big.Int).Set(maxGasPrice)
 
-	if (raw.MinedTxHash != common.Hash{}) {
-		tx, isPending, err := tm.backend.TransactionByHash(context.Background(), raw.MinedTxHash)
-		if isPending || err != nil {
-			return
-		}
-		previousGasPrice = tx.GasPrice()
-	} else if len(raw.PendingTxs) == 0 {
-		previousGasPrice = new(big.Int).Set(tm.gasPrice)
-	} else {
-		lastPendingTx := raw.PendingTxs[len(raw.PendingTxs)-1]
-		previousGasPrice = new(big.Int).Set(lastPendingTx.GasPrice())
+	if tm.gasPrice.Cmp(minGasPrice) < 0 {
+		tm.gasPrice = new(big.Int).Set(minGasPrice)
+	} else if tm.gasPrice.Cmp(maxGasPrice) > 0 {
+		tm.gasPrice = new(big.Int).Set(maxGasPrice)
+	}
+	gasPriceGauge.Update(tm.gasPrice.Int64())
+	WriteGasPrice(tm.db, tm.gasPrice)
+
+	log.Info("Gas price range changed", "min", gasPriceToString(minGasPrice), "max", gasPriceToString(maxGasPrice), "gasprice", gasPriceToString(tm.gasPrice))
+	return nil
+}
+
+// GasPriceRange returns the min and max gas price.
+func (tm *TransactionManager) GasPriceRange() (*big.Int, *big.Int) {
+	tm.gasPriceLock.Lock()
+	defer tm.gasPriceLock.Unlock()
+
+	return new(big.Int).Set(tm.config.MinGasPrice), new(big.Int).Set(tm.config.MaxGasPrice)
+}
+
+// SetInterval changes the interval to send raw transactions.
+func (tm *TransactionManager) SetInterval(interval time.Duration) error {
+	if interval <= 0 {
+		return errors.New("interval must be positive")
 	}
 
-	gasPrice := new(big.Int).Set(previousGasPrice)
+	tm.gasPriceLock.Lock()
+	tm.config.Interval = interval
+	tm.gasPriceLock.Unlock()
 
-	if decrease {
-		// new gas price = previous gas price * 0.4
-		gasPrice = new(big.Int).Mul(new(big.Int).Div(tm.gasPrice, big.NewInt(10)), big.NewInt(4))
-	} else {
-		// new gas price = previous gas price * 1.2
-		gasPrice = new(big.Int).Mul(new(big.Int).Div(tm.gasPrice, big.NewInt(10)), big.NewInt(12))
+	select {
+	case tm.intervalCh <- struct{}{}:
+	default:
 	}
 
-	// make target gas price in range
-	if gasPrice.Cmp(tm.config.MinGasPrice) < 0 {
-		gasPrice.Set(tm.config.MinGasPrice)
+	log.Info("Interval changed", "interval", interval)
+	return nil
+}
+
+// Interval returns the interval to send raw transactions.
+func (tm *TransactionManager) Interval() time.Duration {
+	tm.gasPriceLock.Lock()
+	defer tm.gasPriceLock.Unlock()
+
+	return tm.config.Interval
+}
+
+// RegisterGasPriceStrategy adds a gas price strategy which can be selected by
+// raw transactions with the name.
+func (tm *TransactionManager) RegisterGasPriceStrategy(name string, strategy GasPriceStrategy) {
+	tm.strategyLock.Lock()
+	defer tm.strategyLock.Unlock()
+
+	tm.strategies[name] = strategy
+}
+
+// strategyOf returns the gas price strategy of the raw transaction.
+func (tm *TransactionManager) strategyOf(raw *RawTransaction) GasPriceStrategy {
+	tm.strategyLock.RLock()
+	defer tm.strategyLock.RUnlock()
+
+	if strategy, ok := tm.strategies[raw.GasPriceStrategy]; ok {
+		return strategy
 	}
-	if gasPrice.Cmp(tm.config.MaxGasPrice) > 0 {
-		gasPrice.Set(tm.config.MaxGasPrice)
+	if raw.GasPriceStrategy != "" {
+		log.Warn("Unknown gas price strategy, use default strategy", "caption", raw.getCaption(), "strategy", raw.GasPriceStrategy)
 	}
+	return tm.strategies[tm.config.GasPriceStrategy]
+}
 
-	tm.gasPrice = new(big.Int).Set(gasPrice)
+// setGasPrice sets the gas price of fixed step strategy in range.
+func (tm *TransactionManager) setGasPrice(gasPrice *big.Int) {
+	tm.gasPriceLock.Lock()
+	defer tm.gasPriceLock.Unlock()
+
+	gasPrice = clampGasPrice(gasPrice, tm.config.MinGasPrice, tm.config.MaxGasPrice)
+
+	tm.gasPrice = gasPrice
+	gasPriceGauge.Update(gasPrice.Int64())
 
 	WriteGasPrice(tm.db, gasPrice)
+}
+
+// gasPriceOf returns the gas price of the raw transaction decided by its
+// strategy. The gas price is not lower than the last sent transaction, and it
+// is higher than that by at least 10% if bump is true to replace the
+// transaction.
+func (tm *TransactionManager) gasPriceOf(raw *RawTransaction, bump bool) *big.Int {
+	raw.lock.RLock()
+	var previous *big.Int
+	if len(raw.PendingTxs) > 0 {
+		previous = raw.PendingTxs[len(raw.PendingTxs)-1].GasPrice()
+	}
+	raw.lock.RUnlock()
+
+	minGasPrice, maxGasPrice := tm.GasPriceRange()
+
+	req := &GasPriceRequest{
+		Raw:         raw,
+		Previous:    previous,
+		Bump:        bump,
+		MinGasPrice: minGasPrice,
+		MaxGasPrice: maxGasPrice,
+	}
+
+	gasPrice, err := tm.strategyOf(raw).GasPrice(req)
+	if err != nil || gasPrice == nil {
+		log.Warn("Failed to get gas price from strategy", "caption", raw.getCaption(), "strategy", raw.GasPriceStrategy, "err", err)
+		gasPrice = tm.GasPrice()
+	}
+
+	if previous != nil {
+		floor := previous
+		if bump {
+			// replacement transaction must be priced higher by 10%
+			floor = new(big.Int).Div(new(big.Int).Mul(previous, big.NewInt(11)), big.NewInt(10))
+		}
+		if gasPrice.Cmp(floor) < 0 {
+			gasPrice = floor
+		}
+	}
+
+	return clampGasPrice(gasPrice, minGasPrice, maxGasPrice)
+}
+
+// nextGasPrice returns the gas price to send the raw transaction. The gas
+// price bumped or requested to resend is used once.
+func (tm *TransactionManager) nextGasPrice(raw *RawTransaction) *big.Int {
+	raw.lock.Lock()
+	next := raw.nextGasPrice
+	raw.nextGasPrice = nil
+	raw.lock.Unlock()
+
+	if next != nil {
+		return next
+	}
+	return tm.gasPriceOf(raw, false)
+}
+
+// adjustGasPrice adjust gas prices at a reasonable price. If decrease is false,
+// gas price of the raw transaction is bumped to replace the last transaction.
+// Otherwise, the strategy is notified that the raw transaction is mined.
+func (tm *TransactionManager) adjustGasPrice(raw *RawTransaction, decrease bool) {
+	if decrease {
+		tx, isPending, err := tm.backend.TransactionByHash(context.Background(), raw.MinedTxHash)
+		if isPending || err != nil {
+			return
+		}
+		tm.strategyOf(raw).Mined(raw, tx.GasPrice())
+		return
+	}
+
+	gasPrice := tm.gasPriceOf(raw, true)
 
-	previousGwei := gasPriceToString(previousGasPrice)
-	adjustGwei := gasPriceToString(tm.gasPrice)
+	raw.lock.Lock()
+	raw.nextGasPrice = gasPrice
+	raw.lock.Unlock()
 
-	log.Info("Gas price adjusted", "caption", raw.getCaption(), "decrease", decrease,
-		"previous", previousGwei,
-		"adjusted", adjustGwei)
+	log.Info("Gas price adjusted", "caption", raw.getCaption(), "strategy", raw.GasPriceStrategy, "adjusted", gasPriceToString(gasPrice))
 }
 
 // clearQueue check raw transaction is mined. Mined raw transactions move to unconfirmed pending.
 // Before confirmed, if the mined raw transaction is removed from root chian network, it goes back to the pending again.
 func (tm *TransactionManager) clearQueue(addr common.Address) {
+	// notify subscribers after the lock is released.
+	var minedRaws RawTransactions
+	defer func() {
+		for _, raw := range minedRaws {
+			tm.minedFeed.Send(raw)
+		}
+	}()
+
 	tm.lock.Lock()
 	defer tm.lock.Unlock()
+	defer tm.updateGauges(addr)
 
 	// short circuit if pending is nil or empty.
 	if tm.pending[addr] == nil || len(tm.pending[addr]) == 0 {
@@ -593,13 +997,13 @@ func (tm *TransactionManager) clearQueue(addr common.Address) {
 		log.Info("Transaction is mined", "nonce", raw.Nonce, "caption", raw.getCaption(), "reverted", raw.Reverted, "from", addr, "hash", raw.MinedTxHash.String())
 
 		if raw.Reverted {
+			revertedMeter.Mark(1)
 			log.Error("Transaction is reverted", "caption", raw.getCaption(), "hash", raw.MinedTxHash.String())
 		}
 		tm.adjustGasPrice(raw, true)
 	}
 
 	// remove mined raw transactions
-	var minedRaws RawTransactions
 	i := 0
 	for ; i < len(tm.pending[addr]); i++ {
 		raw := tm.pending[addr][i]
@@ -629,8 +1033,17 @@ func (tm *TransactionManager) clearQueue(addr common.Address) {
 // confirmQueue check mined raw transaction is confirmed.
 // If unconfirmed transaction is removed from canonical chain, insert it into pending pending.
 func (tm *TransactionManager) confirmQueue(addr common.Address) {
+	// notify subscribers after the lock is released.
+	var confirmed RawTransactions
+	defer func() {
+		for _, raw := range confirmed {
+			tm.confirmedFeed.Send(raw)
+		}
+	}()
+
 	tm.lock.Lock()
 	defer tm.lock.Unlock()
+	defer tm.updateGauges(addr)
 
 	if time.Since(tm.lastInspectTime) > time.Second*5 {
 		tm.inspect(addr)
@@ -653,6 +1066,7 @@ func (tm *TransactionManager) confirmQueue(addr common.Address) {
 
 		if removed {
 			log.Info("Raw transaction is removed", "addr", addr, "caption", raw.getCaption())
+			removedMeter.Mark(1)
 			raw.PrepareToResend()
 			tm.pending[addr] = append(tm.pending[addr], raw)
 		} else {
@@ -688,6 +1102,7 @@ func (tm *TransactionManager) confirmQueue(addr common.Address) {
 		tm.confirmed[addr] = append(tm.confirmed[addr], raw)
 		WriteConfirmedTx(tm.db, addr, numConfirmed, raw)
 		numConfirmed++
+		confirmed = append(confirmed, raw)
 	}
 
 	// update database
@@ -698,6 +1113,45 @@ func (tm *TransactionManager) confirmQueue(addr common.Address) {
 	}
 }
 
+// SubscribeMinedTxs registers a subscription of raw transactions mined in root
+// chain.
+func (tm *TransactionManager) SubscribeMinedTxs(ch chan<- *RawTransaction) event.Subscription {
+	return tm.scope.Track(tm.minedFeed.Subscribe(ch))
+}
+
+// SubscribeConfirmedTxs registers a subscription of raw transactions confirmed
+// in root chain.
+func (tm *TransactionManager) SubscribeConfirmedTxs(ch chan<- *RawTransaction) event.Subscription {
+	return tm.scope.Track(tm.confirmedFeed.Subscribe(ch))
+}
+
+// SubscribeCancelledTxs registers a subscription of raw transactions cancelled
+// before they are sent.
+func (tm *TransactionManager) SubscribeCancelledTxs(ch chan<- *RawTransaction) event.Subscription {
+	return tm.scope.Track(tm.cancelledFeed.Subscribe(ch))
+}
+
+// Addresses returns the addresses of the accounts which have sent raw
+// transactions.
+func (tm *TransactionManager) Addresses() []common.Address {
+	tm.lock.RLock()
+	defer tm.lock.RUnlock()
+
+	return append([]common.Address{}, tm.addresses...)
+}
+
+// Confirmed returns the confirmed raw transactions of the account from the
+// index of confirmed transactions.
+func (tm *TransactionManager) Confirmed(addr common.Address, from uint64) RawTransactions {
+	tm.lock.RLock()
+	defer tm.lock.RUnlock()
+
+	if from >= uint64(len(tm.confirmed[addr])) {
+		return nil
+	}
+	return append(RawTransactions{}, tm.confirmed[addr][from:]...)
+}
+
 func (tm *TransactionManager) indexOf(addr common.Address) int {
 	var i int
 	for i = 0; i < len(tm.addresses); i++ {
@@ -806,6 +1260,7 @@ func (tm *TransactionManager) confirmLoop() {
 func (tm *TransactionManager) Stop() {
 	close(tm.quit)
 	tm.wg.Wait()
+	tm.scope.Close()
 }
 
 func (tm *TransactionManager) inspect(addr common.Address) {
@@ -817,6 +1272,24 @@ func (tm *TransactionManager) inspect(addr common.Address) {
 	tm.lastInspectTime = time.Now()
 }
 
+// updateGauges updates the metrics of the account queues. The caller must hold
+// tm.lock.
+func (tm *TransactionManager) updateGauges(addr common.Address) {
+	accountGauge(addr, "pending").Update(int64(len(tm.pending[addr])))
+	accountGauge(addr, "unconfirmed").Update(int64(len(tm.unconfirmed[addr])))
+}
+
+// clampGasPrice returns the gas price in range.
+func clampGasPrice(gasPrice, minGasPrice, maxGasPrice *big.Int) *big.Int {
+	if gasPrice.Cmp(minGasPrice) < 0 {
+		return new(big.Int).Set(minGasPrice)
+	}
+	if gasPrice.Cmp(maxGasPrice) > 0 {
+		return new(big.Int).Set(maxGasPrice)
+	}
+	return new(big.Int).Set(gasPrice)
+}
+
 func gasPriceToString(gp *big.Int) string {
 	ngp := new(big.Float).Quo(new(big.Float).SetInt(gp), new(big.Float).SetInt64(params.GWei))
 	ngp.SetPrec(10)
@@ -133,7 +133,8 @@ func makeTestManager(db ethdb.Database) *TransactionManager {
 		}
 	}
 
-	tm, _ := NewTransactionManager(ks, backend, db, testConfig)
+	am := accounts.NewManager(&accounts.Config{InsecureUnlockAllowed: true}, ks)
+	tm, _ := NewTransactionManager(am, backend, db, testConfig)
 
 	return tm
 }
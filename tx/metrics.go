@@ -0,0 +1,21 @@
+package tx
+
+import (
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/metrics"
+)
+
+var (
+	gasPriceGauge = metrics.NewRegisteredGauge("tx/gasprice", nil)
+
+	sentMeter     = metrics.NewRegisteredMeter("tx/sent", nil)
+	resendMeter   = metrics.NewRegisteredMeter("tx/resend", nil)   // transactions sent again with another gas price or nonce
+	removedMeter  = metrics.NewRegisteredMeter("tx/removed", nil)  // mined transactions removed by root chain reorg
+	revertedMeter = metrics.NewRegisteredMeter("tx/reverted", nil) // mined transactions reverted
+)
+
+// accountGauge returns the gauge of the account. Gauges are registered when
+// they are used first.
+func accountGauge(addr common.Address, name string) metrics.Gauge {
+	return metrics.GetOrRegisterGauge("tx/account/"+addr.Hex()+"/"+name, nil)
+}
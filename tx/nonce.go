@@ -0,0 +1,413 @@
+package tx
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/Onther-Tech/plasma-evm/accounts"
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/core/types"
+	"github.com/Onther-Tech/plasma-evm/log"
+	"github.com/Onther-Tech/plasma-evm/params"
+)
+
+// NonceReconcileInterval is the interval to reconcile account nonces with root
+// chain.
+const NonceReconcileInterval = time.Minute
+
+var ErrNotStuck = errors.New("no sent raw transaction to cancel")
+
+// lockPending locks the raw transactions in pending queue of the account not
+// to be sent, and then tm.lock to modify them. If after is given, only the raw
+// transactions following it are locked, or nothing if it is not pending. It
+// returns the raw transactions locked and a function to unlock them.
+// raw.sendLock must be acquired before tm.lock as send does.
+func (tm *TransactionManager) lockPending(addr common.Address, after *RawTransaction) (RawTransactions, func()) {
+	return tm.lockRaws(func() RawTransactions {
+		return tm.followingRaws(addr, after)
+	})
+}
+
+// followingRaws returns the raw transactions following the given one in
+// pending queue of the account, or all of them if after is nil.
+func (tm *TransactionManager) followingRaws(addr common.Address, after *RawTransaction) RawTransactions {
+	if after == nil {
+		return append(RawTransactions{}, tm.pending[addr]...)
+	}
+	for i, raw := range tm.pending[addr] {
+		if raw == after {
+			return append(RawTransactions{}, tm.pending[addr][i+1:]...)
+		}
+	}
+	return nil
+}
+
+// lockRaws locks the raw transactions returned by queue, and then tm.lock.
+// queue is called with tm.lock held, again after the raw transactions are
+// locked, and it is retried until they are not changed while locking.
+func (tm *TransactionManager) lockRaws(queue func() RawTransactions) (RawTransactions, func()) {
+	for {
+		tm.lock.RLock()
+		raws := queue()
+		tm.lock.RUnlock()
+
+		for _, raw := range raws {
+			raw.sendLock.Lock()
+		}
+		unlock := func() {
+			for _, raw := range raws {
+				raw.sendLock.Unlock()
+			}
+		}
+
+		// retry if raw transactions are added or removed while locking.
+		tm.lock.Lock()
+		if equalRaws(raws, queue()) {
+			return raws, func() {
+				tm.lock.Unlock()
+				unlock()
+			}
+		}
+		tm.lock.Unlock()
+		unlock()
+	}
+}
+
+func equalRaws(a, b RawTransactions) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// anySent returns true if any of the raw transactions is sent or mined.
+func anySent(raws RawTransactions) bool {
+	for _, raw := range raws {
+		if raw.NumPending() > 0 || raw.Mined(nil) {
+			return true
+		}
+	}
+	return false
+}
+
+// shouldReconcile returns true if the nonce of the account is not reconciled
+// for NonceReconcileInterval.
+func (tm *TransactionManager) shouldReconcile(addr common.Address) bool {
+	tm.lock.RLock()
+	defer tm.lock.RUnlock()
+
+	return time.Since(tm.lastReconciled[addr]) > NonceReconcileInterval
+}
+
+// ReconcileNonce compares the nonces of the account with root chain and
+// repairs the drift caused by dropped transactions or transactions sent
+// outside of the manager.
+//
+// Nonce of a sent raw transaction which is used by another transaction is
+// reassigned. Raw transactions not sent yet are assigned nonces following the
+// sent ones in order. Nonce gap before a sent raw transaction is filled with
+// zero-value self-transfers only if Config.FillNonceGap is set, because the
+// pending nonce of a lagging root chain provider looks like a gap.
+//
+// Root chain is read before the raw transactions are locked, and the
+// self-transfers are sent after they are unlocked.
+func (tm *TransactionManager) ReconcileNonce(addr common.Address) error {
+	tm.lock.Lock()
+	tm.lastReconciled[addr] = time.Now()
+	tm.lock.Unlock()
+
+	minedNonce, err := tm.backend.NonceAt(context.Background(), addr, nil)
+	if err != nil {
+		return err
+	}
+	pendingNonce, err := tm.backend.PendingNonceAt(context.Background(), addr)
+	if err != nil {
+		return err
+	}
+
+	tm.lock.RLock()
+	raws := append(RawTransactions{}, tm.pending[addr]...)
+	tm.lock.RUnlock()
+
+	for _, raw := range raws {
+		if _, err := raw.CheckMined(tm.backend, false); err != nil {
+			return err
+		}
+	}
+
+	raws, unlock := tm.lockPending(addr, nil)
+
+	next, gaps, changed := reconcileNonces(raws, minedNonce, pendingNonce)
+
+	if tm.nonce[addr] != next {
+		log.Warn("Account nonce is reconciled with root chain", "addr", addr, "previous", tm.nonce[addr], "nonce", next, "minedNonce", minedNonce, "pendingNonce", pendingNonce)
+		tm.nonce[addr] = next
+		WriteAddrNonce(tm.db, addr, next)
+	}
+
+	if changed {
+		WritePendingTxs(tm.db, addr, tm.pending[addr])
+	}
+
+	unlock()
+
+	if len(gaps) > 0 && !tm.config.FillNonceGap {
+		log.Warn("Nonce gap is not filled", "addr", addr, "from", gaps[0], "to", gaps[len(gaps)-1], "minedNonce", minedNonce, "pendingNonce", pendingNonce)
+		return nil
+	}
+
+	// fill nonce gap not filled by transactions in tx pool.
+	for _, n := range gaps {
+		hash, err := tm.sendSelfTransfer(addr, n, tm.GasPrice())
+		if err != nil {
+			return err
+		}
+		log.Warn("Nonce gap is filled", "addr", addr, "nonce", n, "hash", hash)
+	}
+
+	return nil
+}
+
+// reconcileNonces reassigns the nonces of the pending raw transactions with
+// the nonces of root chain. It returns the next nonce of the account, the
+// nonce gaps before sent raw transactions and whether a nonce is reassigned.
+func reconcileNonces(raws RawTransactions, minedNonce, pendingNonce uint64) (next uint64, gaps []uint64, changed bool) {
+	// find the last sent raw transaction not mined yet.
+	lastSent := -1
+	for i, raw := range raws {
+		if !raw.Mined(nil) && raw.NumPending() > 0 {
+			lastSent = i
+		}
+	}
+
+	next = minedNonce
+
+	for i, raw := range raws {
+		if raw.Mined(nil) {
+			continue
+		}
+
+		if raw.NumPending() > 0 {
+			nonce := raw.Nonce.Uint64()
+
+			if nonce >= next {
+				for n := maxUint64(next, pendingNonce); n < nonce; n++ {
+					gaps = append(gaps, n)
+				}
+				next = nonce + 1
+				continue
+			}
+
+			// nonce is used by another transaction.
+			log.Warn("Nonce of raw transaction is used by another transaction", "nonce", nonce, "minedNonce", minedNonce, "caption", raw.getCaption())
+
+			raw.lock.Lock()
+			raw.PrepareToResend()
+			raw.lock.Unlock()
+		}
+
+		// nonces after the last sent one may be used by transactions in tx pool.
+		if i > lastSent && next < pendingNonce {
+			next = pendingNonce
+		}
+
+		if raw.Nonce == nil || raw.Nonce.Uint64() != next {
+			log.Info("Nonce of raw transaction is reassigned", "caption", raw.getCaption(), "previous", raw.Nonce, "nonce", next)
+			raw.Nonce = new(big.Int).SetUint64(next)
+			changed = true
+		}
+		next++
+	}
+
+	if next < pendingNonce {
+		next = pendingNonce
+	}
+
+	return next, gaps, changed
+}
+
+// CancelStuck replaces the transaction of the first pending raw transaction
+// with a zero-value self-transfer at a higher gas price. The raw transaction
+// and the following ones not sent yet are sent again with the nonces after the
+// following ones already sent.
+//
+// If a raw transaction of another pool account is waiting for the stuck one,
+// the stuck one is handed over to that account instead, so that the pool
+// proceeds without the stuck account.
+//
+// Only the stuck raw transaction is locked while the self-transfer is sent,
+// and the following ones are locked after it to reassign nonces.
+func (tm *TransactionManager) CancelStuck(addr common.Address) (common.Hash, error) {
+	// raw transactions of multiple accounts are locked to hand over.
+	tm.cancelLock.Lock()
+	defer tm.cancelLock.Unlock()
+
+	var stuck *RawTransaction
+
+	tm.lock.RLock()
+	for _, raw := range tm.pending[addr] {
+		if !raw.Mined(tm.backend) {
+			stuck = raw
+			break
+		}
+	}
+	tm.lock.RUnlock()
+
+	if stuck == nil {
+		return common.Hash{}, ErrNotStuck
+	}
+
+	// block sending the stuck raw transaction while it is replaced.
+	stuck.sendLock.Lock()
+	defer stuck.sendLock.Unlock()
+
+	if stuck.Mined(tm.backend) || stuck.NumPending() == 0 {
+		return common.Hash{}, ErrNotStuck
+	}
+
+	nonce := stuck.Nonce.Uint64()
+	hash, err := tm.sendSelfTransfer(addr, nonce, tm.gasPriceOf(stuck, true))
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	log.Warn("Stuck transaction is cancelled", "addr", addr, "nonce", nonce, "caption", stuck.getCaption(), "hash", hash)
+
+	// queue is called last with tm.lock held, so the raw transactions are the
+	// locked ones.
+	var (
+		following, waiting RawTransactions
+		to                 common.Address
+	)
+	_, unlock := tm.lockRaws(func() RawTransactions {
+		following = tm.followingRaws(addr, stuck)
+		to, waiting = tm.waitingRaws(addr, stuck)
+		return append(append(RawTransactions{}, following...), waiting...)
+	})
+	defer unlock()
+
+	// the stuck transaction is mined before the self-transfer.
+	if i, _ := tm.pendingRaw(addr, stuck.Index); i < 0 || stuck.Mined(tm.backend) {
+		log.Warn("Stuck transaction is mined before it is cancelled", "addr", addr, "nonce", nonce, "caption", stuck.getCaption())
+		return hash, nil
+	}
+
+	stuck.lock.Lock()
+	stuck.PrepareToResend()
+	stuck.lock.Unlock()
+
+	if len(waiting) > 0 && !anySent(waiting) {
+		tm.handOver(addr, to, stuck, waiting)
+		log.Warn("Stuck transaction is handed over", "from", addr, "to", to, "caption", stuck.getCaption())
+		return hash, nil
+	}
+
+	next := reassignCancelled(stuck, following)
+	if next > tm.nonce[addr] {
+		tm.nonce[addr] = next
+		WriteAddrNonce(tm.db, addr, next)
+	}
+	WritePendingTxs(tm.db, addr, tm.pending[addr])
+
+	return hash, nil
+}
+
+// reassignCancelled reassigns the nonces of the cancelled raw transaction and
+// the following ones not sent yet. They are assigned nonces after the highest
+// nonce of the following ones already sent, so that the resent transactions
+// do not replace them. It returns the next nonce of the account.
+func reassignCancelled(cancelled *RawTransaction, following RawTransactions) uint64 {
+	next := cancelled.Nonce.Uint64() + 1
+	for _, raw := range following {
+		if !raw.Mined(nil) && raw.NumPending() > 0 && raw.Nonce.Uint64() >= next {
+			next = raw.Nonce.Uint64() + 1
+		}
+	}
+
+	for _, raw := range append(RawTransactions{cancelled}, following...) {
+		if raw.Mined(nil) || (raw != cancelled && raw.NumPending() > 0) {
+			continue
+		}
+		raw.Nonce = new(big.Int).SetUint64(next)
+		next++
+	}
+	return next
+}
+
+// waitingRaws returns the raw transaction in pending queue of another account
+// waiting for the given one as its previous raw transaction, with the raw
+// transactions following it.
+func (tm *TransactionManager) waitingRaws(addr common.Address, prev *RawTransaction) (common.Address, RawTransactions) {
+	for from, raws := range tm.pending {
+		if from == addr {
+			continue
+		}
+		for i, raw := range raws {
+			if raw.Prev != nil && raw.Prev.From == addr && raw.Prev.Index == prev.Index {
+				return from, append(RawTransactions{}, raws[i:]...)
+			}
+		}
+	}
+	return common.Address{}, nil
+}
+
+// handOver moves the cancelled raw transaction from the account to the front of
+// the raw transactions waiting for it in another account. The cancelled raw
+// transaction takes the nonce of the first waiting one, and the waiting ones
+// are shifted by one. The nonce of the cancelled one in the previous account
+// is taken by the self-transfer.
+func (tm *TransactionManager) handOver(addr, to common.Address, cancelled *RawTransaction, waiting RawTransactions) {
+	i, _ := tm.pendingRaw(addr, cancelled.Index)
+	tm.pending[addr] = append(tm.pending[addr][:i:i], tm.pending[addr][i+1:]...)
+	WritePendingTxs(tm.db, addr, tm.pending[addr])
+	tm.updateGauges(addr)
+
+	cancelled.From = to
+	cancelled.Index = ReadNumRawTxs(tm.db, to)
+	WriteNumRawTxs(tm.db, to, cancelled.Index+1)
+	WriteRawTxHash(tm.db, to, cancelled)
+
+	cancelled.Nonce = new(big.Int).Set(waiting[0].Nonce)
+	for _, raw := range waiting {
+		raw.Nonce = new(big.Int).Add(raw.Nonce, big.NewInt(1))
+	}
+	waiting[0].Prev = &RawTxRef{From: to, Index: cancelled.Index}
+
+	tm.nonce[to]++
+	WriteAddrNonce(tm.db, to, tm.nonce[to])
+
+	j, _ := tm.pendingRaw(to, waiting[0].Index)
+	tm.pending[to] = append(tm.pending[to][:j:j], append(RawTransactions{cancelled}, tm.pending[to][j:]...)...)
+	WritePendingTxs(tm.db, to, tm.pending[to])
+	tm.updateGauges(to)
+}
+
+// sendSelfTransfer sends zero-value transaction to the account itself with the
+// nonce to fill or replace it.
+func (tm *TransactionManager) sendSelfTransfer(addr common.Address, nonce uint64, gasPrice *big.Int) (common.Hash, error) {
+	tx := types.NewTransaction(nonce, addr, big.NewInt(0), params.TxGas, gasPrice, nil)
+
+	signedTx, err := tm.signTx(accounts.Account{Address: addr}, tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	if err := tm.backend.SendTransaction(context.Background(), signedTx); err != nil {
+		return common.Hash{}, err
+	}
+	return signedTx.Hash(), nil
+}
+
+func maxUint64(a, b uint64) uint64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,229 @@
+package tx
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/core/types"
+)
+
+type testRawState int
+
+const (
+	unsent testRawState = iota
+	sent
+	mined
+)
+
+func newTestNonceRaw(state testRawState, nonce uint64) *RawTransaction {
+	raw := &RawTransaction{Nonce: new(big.Int).SetUint64(nonce), Caption: "test"}
+	switch state {
+	case sent:
+		raw.PendingTxs = types.Transactions{types.NewTransaction(nonce, common.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil)}
+	case mined:
+		raw.MinedTxHash = common.HexToHash("0x01")
+	}
+	return raw
+}
+
+func TestReconcileNonces(t *testing.T) {
+	type raw struct {
+		state testRawState
+		nonce uint64
+	}
+
+	tests := []struct {
+		name         string
+		raws         []raw
+		minedNonce   uint64
+		pendingNonce uint64
+		nonces       []uint64 // nonces after reconciliation
+		resent       []bool   // whether the raw transaction is prepared to resend
+		next         uint64
+		gaps         []uint64
+	}{
+		{
+			name:       "no drift",
+			raws:       []raw{{mined, 4}, {sent, 5}, {sent, 6}, {unsent, 7}},
+			minedNonce: 5, pendingNonce: 7,
+			nonces: []uint64{4, 5, 6, 7}, resent: []bool{false, false, false, false},
+			next: 8,
+		},
+		{
+			name:       "nonce used by another transaction",
+			raws:       []raw{{sent, 5}, {unsent, 6}},
+			minedNonce: 6, pendingNonce: 6,
+			nonces: []uint64{6, 7}, resent: []bool{true, false},
+			next: 8,
+		},
+		{
+			name:       "nonce gap before sent",
+			raws:       []raw{{sent, 7}, {unsent, 8}},
+			minedNonce: 5, pendingNonce: 5,
+			nonces: []uint64{7, 8}, resent: []bool{false, false},
+			next: 9, gaps: []uint64{5, 6},
+		},
+		{
+			name:       "nonce gap filled by tx pool",
+			raws:       []raw{{sent, 7}},
+			minedNonce: 5, pendingNonce: 6,
+			nonces: []uint64{7}, resent: []bool{false},
+			next: 8, gaps: []uint64{6},
+		},
+		{
+			name:       "transactions sent outside",
+			raws:       []raw{{unsent, 5}, {unsent, 6}},
+			minedNonce: 5, pendingNonce: 8,
+			nonces: []uint64{8, 9}, resent: []bool{false, false},
+			next: 10,
+		},
+		{
+			name:       "dropped transaction",
+			raws:       []raw{{unsent, 9}},
+			minedNonce: 5, pendingNonce: 5,
+			nonces: []uint64{5}, resent: []bool{false},
+			next: 6,
+		},
+		{
+			name:       "no raw transaction",
+			minedNonce: 5, pendingNonce: 7,
+			next: 7,
+		},
+	}
+
+	for _, tt := range tests {
+		var raws RawTransactions
+		for _, r := range tt.raws {
+			raws = append(raws, newTestNonceRaw(r.state, r.nonce))
+		}
+
+		next, gaps, _ := reconcileNonces(raws, tt.minedNonce, tt.pendingNonce)
+		if next != tt.next {
+			t.Errorf("%s: next nonce mismatch: have %d, want %d", tt.name, next, tt.next)
+		}
+		if !reflect.DeepEqual(gaps, tt.gaps) {
+			t.Errorf("%s: nonce gaps mismatch: have %v, want %v", tt.name, gaps, tt.gaps)
+		}
+		for i, raw := range raws {
+			if raw.Nonce.Uint64() != tt.nonces[i] {
+				t.Errorf("%s: raw transaction %d nonce mismatch: have %d, want %d", tt.name, i, raw.Nonce, tt.nonces[i])
+			}
+			if resent := raw.ResendCount > 0; resent != tt.resent[i] {
+				t.Errorf("%s: raw transaction %d resend mismatch: have %v, want %v", tt.name, i, resent, tt.resent[i])
+			}
+		}
+	}
+}
+
+func TestReassignCancelled(t *testing.T) {
+	type raw struct {
+		state testRawState
+		nonce uint64
+	}
+
+	tests := []struct {
+		name      string
+		following []raw
+		nonces    []uint64 // nonces of the cancelled and following raw transactions after reassignment
+		next      uint64
+	}{
+		{
+			name:      "no following",
+			following: nil,
+			nonces:    []uint64{6},
+			next:      7,
+		},
+		{
+			name:      "following not sent",
+			following: []raw{{unsent, 6}, {unsent, 7}},
+			nonces:    []uint64{6, 7, 8},
+			next:      9,
+		},
+		{
+			name:      "following sent",
+			following: []raw{{sent, 6}, {sent, 7}, {unsent, 8}},
+			nonces:    []uint64{8, 6, 7, 9},
+			next:      10,
+		},
+		{
+			name:      "following mined",
+			following: []raw{{mined, 6}, {sent, 7}},
+			nonces:    []uint64{8, 6, 7},
+			next:      9,
+		},
+	}
+
+	for _, tt := range tests {
+		// the cancelled raw transaction is prepared to resend.
+		cancelled := newTestNonceRaw(unsent, 5)
+		raws := RawTransactions{cancelled}
+		for _, r := range tt.following {
+			raws = append(raws, newTestNonceRaw(r.state, r.nonce))
+		}
+
+		if next := reassignCancelled(cancelled, raws[1:]); next != tt.next {
+			t.Errorf("%s: next nonce mismatch: have %d, want %d", tt.name, next, tt.next)
+		}
+		for i, raw := range raws {
+			if raw.Nonce.Uint64() != tt.nonces[i] {
+				t.Errorf("%s: raw transaction %d nonce mismatch: have %d, want %d", tt.name, i, raw.Nonce, tt.nonces[i])
+			}
+		}
+	}
+}
+
+func TestLockPending(t *testing.T) {
+	addr := common.HexToAddress("0x01")
+	raws := RawTransactions{newTestNonceRaw(sent, 1), newTestNonceRaw(unsent, 2), newTestNonceRaw(unsent, 3)}
+	tm := &TransactionManager{pending: map[common.Address]RawTransactions{addr: raws}}
+
+	locked := func(raw *RawTransaction) bool {
+		done := make(chan struct{})
+		go func() {
+			raw.sendLock.Lock()
+			raw.sendLock.Unlock()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+			return false
+		case <-time.After(50 * time.Millisecond):
+			<-done
+			return true
+		}
+	}
+
+	// raw transactions following the stuck one are locked.
+	following, unlock := tm.lockPending(addr, raws[0])
+	if !reflect.DeepEqual(following, raws[1:]) {
+		t.Fatalf("Locked raw transactions mismatch: have %v, want %v", following, raws[1:])
+	}
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		unlock()
+	}()
+	if locked(raws[0]) {
+		t.Fatalf("Raw transaction before the stuck one is locked")
+	}
+	if !locked(raws[2]) {
+		t.Fatalf("Following raw transaction is not locked")
+	}
+
+	// nothing is locked if the raw transaction is not pending.
+	if following, unlock := tm.lockPending(addr, newTestNonceRaw(sent, 4)); len(following) != 0 {
+		t.Fatalf("Raw transactions following unknown one are locked: %v", following)
+	} else {
+		unlock()
+	}
+
+	// all raw transactions are locked.
+	all, unlock := tm.lockPending(addr, nil)
+	if !reflect.DeepEqual(all, raws) {
+		t.Fatalf("Locked raw transactions mismatch: have %v, want %v", all, raws)
+	}
+	unlock()
+}
@@ -0,0 +1,134 @@
+package tx
+
+import (
+	"errors"
+
+	"github.com/Onther-Tech/plasma-evm/accounts"
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/log"
+)
+
+var ErrEmptyPool = errors.New("no account in pool")
+
+// RawTxRef refers to a raw transaction by the account and the index.
+type RawTxRef struct {
+	From  common.Address
+	Index uint64
+}
+
+// SetPool sets the accounts of the pool. Raw transactions added to a pool are
+// distributed to the accounts. Raw transactions added with the same order key
+// are sent in the order they are added, e.g., block submissions of a fork to
+// RootChain contract. Raw transactions already added remain in the queues of
+// their accounts.
+func (tm *TransactionManager) SetPool(name string, accs []accounts.Account) {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	if samePool(tm.pools[name], accs) {
+		return
+	}
+	tm.pools[name] = append([]accounts.Account{}, accs...)
+
+	addrs := make([]common.Address, len(accs))
+	for i, acc := range accs {
+		addrs[i] = acc.Address
+	}
+	log.Info("Transaction pool accounts are set", "pool", name, "accounts", addrs)
+}
+
+// Pool returns the accounts of the pool.
+func (tm *TransactionManager) Pool(name string) []accounts.Account {
+	tm.lock.RLock()
+	defer tm.lock.RUnlock()
+
+	return append([]accounts.Account{}, tm.pools[name]...)
+}
+
+// AddToPool adds the raw transaction to the account in the pool with the
+// fewest pending raw transactions, so a stuck account does not take new raw
+// transactions. If order is not empty, the raw transaction is sent after the
+// last raw transaction added to the pool with the same order is mined. It
+// returns the account the raw transaction is assigned to.
+func (tm *TransactionManager) AddToPool(name string, raw *RawTransaction, order string) (accounts.Account, error) {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	accs := tm.pools[name]
+	if len(accs) == 0 {
+		return accounts.Account{}, ErrEmptyPool
+	}
+
+	// raw transaction must be unique in the pool regardless of the account.
+	for _, acc := range accs {
+		if ReadRawTxHash(tm.db, acc.Address, raw.Hash()) != nil {
+			return accounts.Account{}, ErrDuplicateRaw
+		}
+	}
+
+	var last *RawTxRef
+	if order != "" {
+		last = ReadPoolLast(tm.db, name, order)
+	}
+
+	// prefer the account next to the last one among the least busy accounts.
+	start := 0
+	if last != nil {
+		for i, acc := range accs {
+			if acc.Address == last.From {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	account := accs[start%len(accs)]
+	for i := 0; i < len(accs); i++ {
+		acc := accs[(start+i)%len(accs)]
+		if len(tm.pending[acc.Address]) < len(tm.pending[account.Address]) {
+			account = acc
+		}
+	}
+
+	raw.From = account.Address
+	raw.Prev = last
+
+	if err := tm.add(account, raw, false); err != nil {
+		return accounts.Account{}, err
+	}
+	if order != "" {
+		WritePoolLast(tm.db, name, order, &RawTxRef{account.Address, raw.Index})
+	}
+
+	log.Debug("Raw transaction is assigned in pool", "pool", name, "account", account.Address, "caption", raw.getCaption())
+
+	return account, nil
+}
+
+// waitingPrev returns true if the previous raw transaction of the raw
+// transaction is in pending queue and not mined yet. Mined status is checked
+// after the lock is released. The previous raw transaction is read with the
+// lock held as it is changed when the previous one is handed over.
+func (tm *TransactionManager) waitingPrev(raw *RawTransaction) bool {
+	var prev *RawTransaction
+
+	tm.lock.RLock()
+	if ref := raw.Prev; ref != nil {
+		_, prev = tm.pendingRaw(ref.From, ref.Index)
+	}
+	tm.lock.RUnlock()
+
+	return prev != nil && !prev.Mined(tm.backend)
+}
+
+func samePool(a, b []accounts.Account) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Address != b[i].Address {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+package tx
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/core/rawdb"
+)
+
+func TestWaitingPrev(t *testing.T) {
+	addr := common.HexToAddress("0x01")
+	prev := newTestNonceRaw(sent, 1)
+	prev.Index = 5
+	tm := &TransactionManager{pending: map[common.Address]RawTransactions{addr: {prev}}}
+
+	raw := newTestNonceRaw(unsent, 2)
+	if tm.waitingPrev(raw) {
+		t.Fatalf("Raw transaction without previous one is waiting")
+	}
+
+	raw.Prev = &RawTxRef{From: addr, Index: 5}
+	if !tm.waitingPrev(raw) {
+		t.Fatalf("Raw transaction is not waiting for pending previous one")
+	}
+
+	prev.MinedTxHash = common.HexToHash("0x01")
+	if tm.waitingPrev(raw) {
+		t.Fatalf("Raw transaction is waiting for mined previous one")
+	}
+
+	tm.pending[addr] = nil
+	if tm.waitingPrev(raw) {
+		t.Fatalf("Raw transaction is waiting for previous one not in pending queue")
+	}
+}
+
+func TestHandOver(t *testing.T) {
+	var (
+		stuckAddr = common.HexToAddress("0x01")
+		addr      = common.HexToAddress("0x02")
+		db        = rawdb.NewMemoryDatabase()
+	)
+
+	// the stuck raw transaction is prepared to resend after it is cancelled.
+	stuck, following := newTestNonceRaw(unsent, 0), newTestNonceRaw(unsent, 6)
+	stuck.Index, stuck.From, stuck.Recipient = 3, stuckAddr, &common.Address{}
+
+	done, waiting, next := newTestNonceRaw(mined, 9), newTestNonceRaw(unsent, 10), newTestNonceRaw(unsent, 11)
+	done.Index, waiting.Index, next.Index = 0, 1, 2
+	waiting.Prev = &RawTxRef{From: stuckAddr, Index: stuck.Index}
+	WriteNumRawTxs(db, addr, 3)
+
+	tm := &TransactionManager{
+		db:      db,
+		pending: map[common.Address]RawTransactions{stuckAddr: {stuck, following}, addr: {done, waiting, next}},
+		nonce:   map[common.Address]uint64{stuckAddr: 7, addr: 12},
+	}
+
+	to, raws := tm.waitingRaws(stuckAddr, stuck)
+	if to != addr || !reflect.DeepEqual(raws, RawTransactions{waiting, next}) {
+		t.Fatalf("Waiting raw transactions mismatch: have %x %v, want %x %v", to, raws, addr, RawTransactions{waiting, next})
+	}
+	if _, raws := tm.waitingRaws(stuckAddr, following); len(raws) != 0 {
+		t.Fatalf("Raw transactions waiting for unknown one: %v", raws)
+	}
+
+	tm.handOver(stuckAddr, to, stuck, raws)
+
+	if !reflect.DeepEqual(tm.pending[stuckAddr], RawTransactions{following}) || following.Nonce.Uint64() != 6 {
+		t.Fatalf("Stuck account pending queue mismatch: have %v", tm.pending[stuckAddr])
+	}
+	if !reflect.DeepEqual(tm.pending[addr], RawTransactions{done, stuck, waiting, next}) {
+		t.Fatalf("Pending queue mismatch: have %v", tm.pending[addr])
+	}
+	for i, want := range []uint64{9, 10, 11, 12} {
+		if nonce := tm.pending[addr][i].Nonce.Uint64(); nonce != want {
+			t.Errorf("Raw transaction %d nonce mismatch: have %d, want %d", i, nonce, want)
+		}
+	}
+	if stuck.From != addr || stuck.Index != 3 || ReadNumRawTxs(db, addr) != 4 || tm.nonce[addr] != 13 || tm.nonce[stuckAddr] != 7 {
+		t.Fatalf("Handed over raw transaction mismatch: from %x index %d, num raws %d, nonces %v", stuck.From, stuck.Index, ReadNumRawTxs(db, addr), tm.nonce)
+	}
+
+	// the waiting raw transaction waits for the handed over one.
+	if *waiting.Prev != (RawTxRef{From: addr, Index: 3}) || !tm.waitingPrev(waiting) {
+		t.Fatalf("Raw transaction is not waiting for handed over one: %v", waiting.Prev)
+	}
+	if !tm.isPending(addr, stuck) || tm.isPending(stuckAddr, stuck) {
+		t.Fatalf("Handed over raw transaction is pending in the stuck account")
+	}
+}
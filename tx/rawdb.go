@@ -34,6 +34,8 @@ var (
 	pendingTxsPrefix      = []byte("pending-raw-txs")       // pendingTxsPrefix + account address -> (resend + pending) raw transactions
 
 	rawTxHashPrefix = []byte("raw-tx-hash") // rawTxHashPrefix + account address + raw transaction hash -> raw transaction without index
+
+	poolLastPrefix = []byte("pool-last-raw-tx") // poolLastPrefix + pool name + "/" + order -> reference to the last raw transaction added to the pool with the order
 )
 
 func ReadGasPrice(db ethdb.Reader) *big.Int {
@@ -318,7 +320,7 @@ func ReadRawTxHash(db ethdb.Reader, addr common.Address, rawHash common.Hash) *R
 	return &raw
 }
 
-func WriteRawTxHash(db ethdb.KeyValueWriter, addr common.Address, raw RawTransaction) {
+func WriteRawTxHash(db ethdb.KeyValueWriter, addr common.Address, raw *RawTransaction) {
 	data, err := rlp.EncodeToBytes(raw)
 	if err != nil {
 		log.Crit("Failed to encode raw transaction", "err", err)
@@ -328,6 +330,42 @@ func WriteRawTxHash(db ethdb.KeyValueWriter, addr common.Address, raw RawTransac
 	}
 }
 
+func DeleteRawTxHash(db ethdb.KeyValueWriter, addr common.Address, rawHash common.Hash) {
+	if err := db.Delete(rawTxHashKey(addr, rawHash)); err != nil {
+		log.Crit("Failed to delete raw transaction", "err", err)
+	}
+}
+
+func poolLastKey(pool, order string) []byte {
+	return append(poolLastPrefix, []byte(pool+"/"+order)...)
+}
+
+func ReadPoolLast(db ethdb.Reader, pool, order string) *RawTxRef {
+	data, _ := db.Get(poolLastKey(pool, order))
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	var ref RawTxRef
+	if err := rlp.DecodeBytes(data, &ref); err != nil {
+		log.Crit("Failed to decode last raw transaction of pool", "err", err, "pool", pool)
+		return nil
+	}
+
+	return &ref
+}
+
+func WritePoolLast(db ethdb.KeyValueWriter, pool, order string, ref *RawTxRef) {
+	data, err := rlp.EncodeToBytes(ref)
+	if err != nil {
+		log.Crit("Failed to encode last raw transaction of pool", "err", err)
+	}
+	if err := db.Put(poolLastKey(pool, order), data); err != nil {
+		log.Crit("Failed to store last raw transaction of pool", "err", err)
+	}
+}
+
 // encodeBlockNumber encodes a number as big endian uint64
 func encodeNumber(number uint64) []byte {
 	enc := make([]byte, 8)
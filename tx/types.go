@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/pkg/errors"
+	"io"
 	"math/big"
 	"sync"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/Onther-Tech/plasma-evm/common"
 	"github.com/Onther-Tech/plasma-evm/core/types"
 	"github.com/Onther-Tech/plasma-evm/ethclient"
+	"github.com/Onther-Tech/plasma-evm/rlp"
 )
 
 const (
@@ -50,6 +52,21 @@ type RawTransaction struct {
 
 	Caption string
 
+	// GasPriceStrategy is the name of gas price strategy for the raw
+	// transaction. Default strategy of the manager is used if it is empty.
+	GasPriceStrategy string
+
+	// Deadline is the unix timestamp by which the raw transaction should be
+	// mined, e.g., the end of challenge period. Zero means no deadline.
+	Deadline uint64
+
+	// Prev is the raw transaction which must be mined before the raw
+	// transaction is sent, e.g., the previous one added to the same pool.
+	Prev *RawTxRef
+
+	cancelled    bool     // protected by sendLock
+	nextGasPrice *big.Int // gas price to resend, protected by lock
+
 	sendLock sync.Mutex
 	lock     sync.RWMutex
 }
@@ -68,6 +85,107 @@ func NewRawTransaction(from common.Address, gasLimit uint64, receipt *common.Add
 	return rawTx
 }
 
+// rawTransactionRLP is the RLP layout of RawTransaction. Fields added after the
+// first layout are encoded in Ext in order, so that raw transactions stored
+// before the fields are added can be decoded.
+type rawTransactionRLP struct {
+	Index               uint64
+	ConfirmedIndex      uint64
+	ResendCount         uint64
+	LastSentBlockNumber uint64
+
+	Nonce     *big.Int
+	From      common.Address
+	GasLimit  uint64
+	Recipient *common.Address
+	Amount    *big.Int
+	Payload   []byte
+
+	AllowRevert bool
+
+	PendingTxs       types.Transactions
+	MinedTxHash      common.Hash
+	MinedBlockNumber *big.Int
+	Reverted         bool
+
+	Caption string
+
+	// Ext is GasPriceStrategy, Deadline and Prev. Prev is omitted if it is nil.
+	Ext []rlp.RawValue `rlp:"tail"`
+}
+
+// EncodeRLP implements rlp.Encoder.
+func (raw *RawTransaction) EncodeRLP(w io.Writer) error {
+	enc := &rawTransactionRLP{
+		Index:               raw.Index,
+		ConfirmedIndex:      raw.ConfirmedIndex,
+		ResendCount:         raw.ResendCount,
+		LastSentBlockNumber: raw.LastSentBlockNumber,
+		Nonce:               raw.Nonce,
+		From:                raw.From,
+		GasLimit:            raw.GasLimit,
+		Recipient:           raw.Recipient,
+		Amount:              raw.Amount,
+		Payload:             raw.Payload,
+		AllowRevert:         raw.AllowRevert,
+		PendingTxs:          raw.PendingTxs,
+		MinedTxHash:         raw.MinedTxHash,
+		MinedBlockNumber:    raw.MinedBlockNumber,
+		Reverted:            raw.Reverted,
+		Caption:             raw.Caption,
+	}
+
+	ext := []interface{}{raw.GasPriceStrategy, raw.Deadline}
+	if raw.Prev != nil {
+		ext = append(ext, raw.Prev)
+	}
+	for _, v := range ext {
+		data, err := rlp.EncodeToBytes(v)
+		if err != nil {
+			return err
+		}
+		enc.Ext = append(enc.Ext, data)
+	}
+
+	return rlp.Encode(w, enc)
+}
+
+// DecodeRLP implements rlp.Decoder. Fields missing in Ext are left zero.
+func (raw *RawTransaction) DecodeRLP(s *rlp.Stream) error {
+	var dec rawTransactionRLP
+	if err := s.Decode(&dec); err != nil {
+		return err
+	}
+
+	raw.Index = dec.Index
+	raw.ConfirmedIndex = dec.ConfirmedIndex
+	raw.ResendCount = dec.ResendCount
+	raw.LastSentBlockNumber = dec.LastSentBlockNumber
+	raw.Nonce = dec.Nonce
+	raw.From = dec.From
+	raw.GasLimit = dec.GasLimit
+	raw.Recipient = dec.Recipient
+	raw.Amount = dec.Amount
+	raw.Payload = dec.Payload
+	raw.AllowRevert = dec.AllowRevert
+	raw.PendingTxs = dec.PendingTxs
+	raw.MinedTxHash = dec.MinedTxHash
+	raw.MinedBlockNumber = dec.MinedBlockNumber
+	raw.Reverted = dec.Reverted
+	raw.Caption = dec.Caption
+
+	ext := []interface{}{&raw.GasPriceStrategy, &raw.Deadline, &raw.Prev}
+	for i, data := range dec.Ext {
+		if i == len(ext) {
+			break
+		}
+		if err := rlp.DecodeBytes(data, ext[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (raw *RawTransaction) getCaption() string {
 	if raw.ResendCount == 0 {
 		return raw.Caption
@@ -111,6 +229,14 @@ func (raw *RawTransaction) AddPending(tx *types.Transaction) error {
 	return nil
 }
 
+// NumPending returns the number of transactions sent for the raw transaction.
+func (raw *RawTransaction) NumPending() int {
+	raw.lock.RLock()
+	defer raw.lock.RUnlock()
+
+	return len(raw.PendingTxs)
+}
+
 // CheckMined clears all pending transactions and sets mined transaction hash if transaction is mined .
 func (raw *RawTransaction) CheckMined(backend *ethclient.Client, force bool) (mined bool, err error) {
 	raw.lock.Lock()
@@ -0,0 +1,177 @@
+package tx
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/Onther-Tech/plasma-evm/common"
+	"github.com/Onther-Tech/plasma-evm/core/types"
+	"github.com/Onther-Tech/plasma-evm/rlp"
+)
+
+// legacyRawTransaction is the layout of raw transactions stored before gas
+// price strategy and deadline are added.
+type legacyRawTransaction struct {
+	Index               uint64
+	ConfirmedIndex      uint64
+	ResendCount         uint64
+	LastSentBlockNumber uint64
+
+	Nonce     *big.Int
+	From      common.Address
+	GasLimit  uint64
+	Recipient *common.Address
+	Amount    *big.Int
+	Payload   []byte
+
+	AllowRevert bool
+
+	PendingTxs       types.Transactions
+	MinedTxHash      common.Hash
+	MinedBlockNumber *big.Int
+	Reverted         bool
+
+	Caption string
+}
+
+func newTestRawTransaction() *RawTransaction {
+	recipient := common.HexToAddress("0x02")
+	return &RawTransaction{
+		Index:            3,
+		Nonce:            big.NewInt(7),
+		From:             common.HexToAddress("0x01"),
+		GasLimit:         21000,
+		Recipient:        &recipient,
+		Amount:           big.NewInt(100),
+		Payload:          []byte{0x01, 0x02},
+		AllowRevert:      true,
+		PendingTxs:       types.Transactions{},
+		MinedBlockNumber: big.NewInt(0),
+		Caption:          "submitNRE",
+	}
+}
+
+func TestDecodeLegacyRawTransaction(t *testing.T) {
+	want := newTestRawTransaction()
+	legacy := &legacyRawTransaction{
+		Index:            want.Index,
+		Nonce:            want.Nonce,
+		From:             want.From,
+		GasLimit:         want.GasLimit,
+		Recipient:        want.Recipient,
+		Amount:           want.Amount,
+		Payload:          want.Payload,
+		AllowRevert:      want.AllowRevert,
+		PendingTxs:       want.PendingTxs,
+		MinedBlockNumber: want.MinedBlockNumber,
+		Caption:          want.Caption,
+	}
+
+	data, err := rlp.EncodeToBytes(legacy)
+	if err != nil {
+		t.Fatalf("Failed to encode legacy raw transaction: %v", err)
+	}
+
+	var raw RawTransaction
+	if err := rlp.DecodeBytes(data, &raw); err != nil {
+		t.Fatalf("Failed to decode legacy raw transaction: %v", err)
+	}
+	assertRawTransactionEqual(t, &raw, want)
+
+	// pending raw transactions are stored as a list.
+	data, _ = rlp.EncodeToBytes([]*legacyRawTransaction{legacy, legacy})
+
+	var raws RawTransactions
+	if err := rlp.DecodeBytes(data, &raws); err != nil {
+		t.Fatalf("Failed to decode legacy raw transactions: %v", err)
+	}
+	if len(raws) != 2 {
+		t.Fatalf("Raw transactions mismatch: have %d, want %d", len(raws), 2)
+	}
+	assertRawTransactionEqual(t, raws[1], want)
+}
+
+func TestRawTransactionRLP(t *testing.T) {
+	tests := []func(raw *RawTransaction){
+		func(raw *RawTransaction) {},
+		func(raw *RawTransaction) { raw.GasPriceStrategy = DeadlineStrategy },
+		func(raw *RawTransaction) { raw.GasPriceStrategy, raw.Deadline = DeadlineStrategy, 1600000000 },
+		func(raw *RawTransaction) { raw.Prev = &RawTxRef{From: common.HexToAddress("0x03"), Index: 2} },
+		func(raw *RawTransaction) {
+			raw.GasPriceStrategy, raw.Prev = OracleStrategy, &RawTxRef{From: common.HexToAddress("0x03")}
+		},
+	}
+
+	for i, modify := range tests {
+		want := newTestRawTransaction()
+		modify(want)
+
+		data, err := rlp.EncodeToBytes(want)
+		if err != nil {
+			t.Fatalf("test %d: failed to encode raw transaction: %v", i, err)
+		}
+
+		var raw RawTransaction
+		if err := rlp.DecodeBytes(data, &raw); err != nil {
+			t.Fatalf("test %d: failed to decode raw transaction: %v", i, err)
+		}
+		assertRawTransactionEqual(t, &raw, want)
+	}
+}
+
+func TestDecodeRawTransactionWithoutPrev(t *testing.T) {
+	want := newTestRawTransaction()
+	want.GasPriceStrategy, want.Deadline = DeadlineStrategy, 1600000000
+
+	// layout of raw transactions stored before the previous raw transaction
+	// is added: legacy fields followed by gas price strategy and deadline.
+	data, _ := rlp.EncodeToBytes(&legacyRawTransaction{
+		Index:            want.Index,
+		Nonce:            want.Nonce,
+		From:             want.From,
+		GasLimit:         want.GasLimit,
+		Recipient:        want.Recipient,
+		Amount:           want.Amount,
+		Payload:          want.Payload,
+		AllowRevert:      want.AllowRevert,
+		PendingTxs:       want.PendingTxs,
+		MinedBlockNumber: want.MinedBlockNumber,
+		Caption:          want.Caption,
+	})
+	var fields []rlp.RawValue
+	if err := rlp.DecodeBytes(data, &fields); err != nil {
+		t.Fatalf("Failed to split legacy raw transaction: %v", err)
+	}
+	strategy, _ := rlp.EncodeToBytes(want.GasPriceStrategy)
+	deadline, _ := rlp.EncodeToBytes(want.Deadline)
+	data, _ = rlp.EncodeToBytes(append(fields, strategy, deadline))
+
+	var raw RawTransaction
+	if err := rlp.DecodeBytes(data, &raw); err != nil {
+		t.Fatalf("Failed to decode raw transaction without previous one: %v", err)
+	}
+	assertRawTransactionEqual(t, &raw, want)
+
+	// raw transaction without previous one is encoded in the same layout.
+	if have, _ := rlp.EncodeToBytes(want); !reflect.DeepEqual(have, data) {
+		t.Fatalf("Encoding mismatch: have %x, want %x", have, data)
+	}
+}
+
+func assertRawTransactionEqual(t *testing.T, have, want *RawTransaction) {
+	t.Helper()
+
+	if have.Index != want.Index || have.Nonce.Cmp(want.Nonce) != 0 || have.From != want.From || have.GasLimit != want.GasLimit {
+		t.Fatalf("Raw transaction mismatch: have %+v, want %+v", have, want)
+	}
+	if *have.Recipient != *want.Recipient || have.Amount.Cmp(want.Amount) != 0 || !reflect.DeepEqual(have.Payload, want.Payload) {
+		t.Fatalf("Raw transaction mismatch: have %+v, want %+v", have, want)
+	}
+	if have.AllowRevert != want.AllowRevert || have.Caption != want.Caption || have.MinedBlockNumber.Cmp(want.MinedBlockNumber) != 0 {
+		t.Fatalf("Raw transaction mismatch: have %+v, want %+v", have, want)
+	}
+	if have.GasPriceStrategy != want.GasPriceStrategy || have.Deadline != want.Deadline || !reflect.DeepEqual(have.Prev, want.Prev) {
+		t.Fatalf("Raw transaction extension mismatch: have %q %d %v, want %q %d %v", have.GasPriceStrategy, have.Deadline, have.Prev, want.GasPriceStrategy, want.Deadline, want.Prev)
+	}
+}